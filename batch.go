@@ -0,0 +1,133 @@
+package neox
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+// DefaultBatchSize is the number of items written per transaction by BatchWrite
+// when the provided batch size is not positive
+const DefaultBatchSize = 1000
+
+// Counters are the changes made to the database by the statements of a batched write
+type Counters struct {
+	NodesCreated         int
+	NodesDeleted         int
+	RelationshipsCreated int
+	RelationshipsDeleted int
+	PropertiesSet        int
+	LabelsAdded          int
+	LabelsRemoved        int
+	IndexesAdded         int
+	IndexesRemoved       int
+	ConstraintsAdded     int
+	ConstraintsRemoved   int
+}
+
+// ContainsUpdates reports whether any of the counters is greater than 0
+func (c Counters) ContainsUpdates() bool {
+	return c != Counters{}
+}
+
+// Add adds the provided counters to c
+func (c *Counters) Add(o Counters) {
+	c.NodesCreated += o.NodesCreated
+	c.NodesDeleted += o.NodesDeleted
+	c.RelationshipsCreated += o.RelationshipsCreated
+	c.RelationshipsDeleted += o.RelationshipsDeleted
+	c.PropertiesSet += o.PropertiesSet
+	c.LabelsAdded += o.LabelsAdded
+	c.LabelsRemoved += o.LabelsRemoved
+	c.IndexesAdded += o.IndexesAdded
+	c.IndexesRemoved += o.IndexesRemoved
+	c.ConstraintsAdded += o.ConstraintsAdded
+	c.ConstraintsRemoved += o.ConstraintsRemoved
+}
+
+// countersOf returns the counters reported in the summary of a statement
+func countersOf(counters neo4j.Counters) Counters {
+	if counters == nil {
+		return Counters{}
+	}
+	return Counters{
+		NodesCreated:         counters.NodesCreated(),
+		NodesDeleted:         counters.NodesDeleted(),
+		RelationshipsCreated: counters.RelationshipsCreated(),
+		RelationshipsDeleted: counters.RelationshipsDeleted(),
+		PropertiesSet:        counters.PropertiesSet(),
+		LabelsAdded:          counters.LabelsAdded(),
+		LabelsRemoved:        counters.LabelsRemoved(),
+		IndexesAdded:         counters.IndexesAdded(),
+		IndexesRemoved:       counters.IndexesRemoved(),
+		ConstraintsAdded:     counters.ConstraintsAdded(),
+		ConstraintsRemoved:   counters.ConstraintsRemoved(),
+	}
+}
+
+// BatchWrite writes a slice of items in chunks of batchSize, each chunk passed to the cypher
+// statement as the $batch list parameter and run in its own write transaction with the retry
+// logic of the driver in place. Items are structs, pointers to structs, Args or maps, converted
+// with ToArgs. The statement usually unwinds the batch
+//
+//	counters, err := session.BatchWrite(`
+//		unwind $batch as row
+//		merge (u:User {id: row.id})
+//		set u.name = row.name`, users, 500)
+//
+// The counters of the chunks are summed up. When a chunk fails, the counters of the chunks
+// committed before it are returned along with the error
+func (s *Session) BatchWrite(cypher string, items interface{}, batchSize int) (Counters, error) {
+	return s.BatchWriteContext(context.Background(), cypher, items, batchSize)
+}
+
+// BatchWriteContext is like BatchWrite, passing the provided context on to the hooks of the driver.
+// The context is checked between chunks, the chunks that are not written once it is done are skipped
+func (s *Session) BatchWriteContext(ctx context.Context, cypher string, items interface{}, batchSize int) (Counters, error) {
+	var counters Counters
+
+	list := reflect.ValueOf(items)
+	if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+		return counters, fmt.Errorf("neox: batch items must be a slice, got %T", items)
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	batch := make([]interface{}, list.Len())
+	for i := range batch {
+		args, err := ToArgs(list.Index(i).Interface())
+		if err != nil {
+			return counters, fmt.Errorf("neox: batch item %d: %w", i, err)
+		}
+		batch[i] = map[string]interface{}(args)
+	}
+
+	for start := 0; start < len(batch); start += batchSize {
+		if err := ctx.Err(); err != nil {
+			return counters, err
+		}
+		end := start + batchSize
+		if end > len(batch) {
+			end = len(batch)
+		}
+		chunk := batch[start:end]
+
+		summary, err := s.WriteTransactionxContext(ctx, func(tx *Transaction) (interface{}, error) {
+			res, err := tx.Runx(cypher, Args{"batch": chunk})
+			if err != nil {
+				return nil, err
+			}
+			return res.Consume()
+		})
+		if err != nil {
+			return counters, err
+		}
+		if summary, ok := summary.(neo4j.ResultSummary); ok && summary != nil {
+			counters.Add(countersOf(summary.Counters()))
+		}
+	}
+	return counters, nil
+}
@@ -0,0 +1,121 @@
+package neox_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+	"github.com/syllabix/neox"
+	"github.com/syllabix/neox/neoxtest"
+)
+
+func TestToArgs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    neox.Args
+		wantErr bool
+	}{
+		{
+			name:  "Should convert structs by db tags",
+			value: user{ID: "u1", Name: "Yolanda Erasmus", Age: 17, password: "secret"},
+			want:  neox.Args{"id": "u1", "name": "Yolanda Erasmus", "age": int64(17), "first name": ""},
+		},
+		{
+			name:  "Should convert pointers to structs",
+			value: &user{ID: "u2"},
+			want:  neox.Args{"id": "u2", "name": "", "age": int64(0), "first name": ""},
+		},
+		{name: "Should return args", value: neox.Args{"id": 1}, want: neox.Args{"id": 1}},
+		{name: "Should convert maps", value: map[string]interface{}{"id": 1}, want: neox.Args{"id": 1}},
+		{name: "Should reject other values", value: 1, wantErr: true},
+		{name: "Should reject nil pointers", value: (*user)(nil), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := neox.ToArgs(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ToArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ToArgs() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSession_BatchWrite(t *testing.T) {
+	t.Parallel()
+
+	const cypher = "unwind $batch as row merge (u:User {id: row.id}) set u.name = row.name"
+	row := func(id string) interface{} {
+		return map[string]interface{}{"id": id, "name": id, "age": int64(0), "first name": ""}
+	}
+	users := []user{{ID: "a", Name: "a"}, {ID: "b", Name: "b"}, {ID: "c", Name: "c"}, {ID: "d", Name: "d"}, {ID: "e", Name: "e"}}
+
+	t.Run("Should write chunks and sum counters", func(t *testing.T) {
+		fake := neoxtest.NewDriver()
+		fake.On(cypher).WithSummary(neoxtest.Summary{Counters: neoxtest.Counters{NodesCreated: 2, PropertiesSet: 4}}).Times(2)
+		fake.On(cypher).WithSummary(neoxtest.Summary{Counters: neoxtest.Counters{NodesCreated: 1, PropertiesSet: 2}})
+
+		session, _ := fake.Neox().Sessionx(neo4j.AccessModeWrite)
+		defer session.Close()
+
+		counters, err := session.BatchWrite(cypher, users, 2)
+		if err != nil {
+			t.Fatalf("Session.BatchWrite() error = %v", err)
+		}
+		if want := (neox.Counters{NodesCreated: 5, PropertiesSet: 10}); counters != want {
+			t.Errorf("Session.BatchWrite() counters = %+v, want %+v", counters, want)
+		}
+
+		calls := fake.Calls()
+		if len(calls) != 3 || !calls[0].Transaction {
+			t.Fatalf("Calls() = %+v, want 3 transactional calls", calls)
+		}
+		want := []interface{}{row("e")}
+		if got := calls[2].Args["batch"]; !reflect.DeepEqual(got, want) {
+			t.Errorf("Calls()[2] batch = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Should return the counters of committed chunks on failure", func(t *testing.T) {
+		fake := neoxtest.NewDriver()
+		fake.On(cypher).WithArgs(neox.Args{"batch": []interface{}{row("a"), row("b"), row("c")}}).
+			WithSummary(neoxtest.Summary{Counters: neoxtest.Counters{NodesCreated: 3}})
+		fake.On(cypher).ReturnError(neoxtest.DatabaseError("Neo.ClientError.Schema.ConstraintValidationFailed", "already exists"))
+
+		session, _ := fake.Neox().Sessionx(neo4j.AccessModeWrite)
+		defer session.Close()
+
+		counters, err := session.BatchWrite(cypher, users, 3)
+		if !errors.Is(err, neox.ErrConstraintViolation) {
+			t.Errorf("Session.BatchWrite() error = %v, want %v", err, neox.ErrConstraintViolation)
+		}
+		if counters.NodesCreated != 3 {
+			t.Errorf("Session.BatchWrite() counters = %+v", counters)
+		}
+	})
+
+	t.Run("Should reject items that are not a slice", func(t *testing.T) {
+		session, _ := neoxtest.NewDriver().Neox().Sessionx(neo4j.AccessModeWrite)
+		defer session.Close()
+		if _, err := session.BatchWrite(cypher, users[0], 2); err == nil {
+			t.Errorf("Session.BatchWrite() error = nil, want an error")
+		}
+	})
+}
+
+func TestCounters_Add(t *testing.T) {
+	t.Parallel()
+
+	got := neox.Counters{NodesCreated: 1, PropertiesSet: 2}
+	got.Add(neox.Counters{NodesCreated: 2, RelationshipsCreated: 1, ConstraintsRemoved: 1})
+	want := neox.Counters{NodesCreated: 3, RelationshipsCreated: 1, PropertiesSet: 2, ConstraintsRemoved: 1}
+	if got != want {
+		t.Errorf("Counters.Add() got = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,385 @@
+package neox
+
+import (
+	"math"
+	"net/url"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+	"github.com/syllabix/neox/bolt"
+)
+
+// NewBoltDriver constructs an instance of a neox.Driver backed by the pure Go
+// Bolt transport of the neox/bolt package, rather than the seabolt connector of
+// the neo4j driver. Only direct connections to a single server are supported
+func NewBoltDriver(target string, auth bolt.AuthToken, configurers ...func(*bolt.Config)) (*Driver, error) {
+	d, err := bolt.NewDriver(target, auth, configurers...)
+	if err != nil {
+		return nil, ClassifyError(err)
+	}
+
+	return &Driver{Driver: &boltDriver{driver: d}}, nil
+}
+
+// boltDriver adapts a bolt.Driver to a neo4j.Driver
+type boltDriver struct {
+	driver *bolt.Driver
+}
+
+func (d *boltDriver) Target() url.URL {
+	return d.driver.Target()
+}
+
+func (d *boltDriver) Session(accessMode neo4j.AccessMode, bookmarks ...string) (neo4j.Session, error) {
+	s, err := d.driver.Session(bolt.AccessMode(accessMode), bookmarks...)
+	if err != nil {
+		return nil, err
+	}
+	return &boltSession{session: s}, nil
+}
+
+func (d *boltDriver) Close() error {
+	return d.driver.Close()
+}
+
+// boltSession adapts a bolt.Session to a neo4j.Session
+type boltSession struct {
+	session *bolt.Session
+}
+
+func (s *boltSession) LastBookmark() string {
+	return s.session.LastBookmark()
+}
+
+func (s *boltSession) BeginTransaction(configurers ...func(*neo4j.TransactionConfig)) (neo4j.Transaction, error) {
+	tx, err := s.session.BeginTransaction(boltTxConfig(configurers)...)
+	if err != nil {
+		return nil, err
+	}
+	return &boltTransaction{tx: tx}, nil
+}
+
+func (s *boltSession) ReadTransaction(work neo4j.TransactionWork, configurers ...func(*neo4j.TransactionConfig)) (interface{}, error) {
+	return s.session.ReadTransaction(boltWork(work), boltTxConfig(configurers)...)
+}
+
+func (s *boltSession) WriteTransaction(work neo4j.TransactionWork, configurers ...func(*neo4j.TransactionConfig)) (interface{}, error) {
+	return s.session.WriteTransaction(boltWork(work), boltTxConfig(configurers)...)
+}
+
+func (s *boltSession) Run(cypher string, params map[string]interface{}, configurers ...func(*neo4j.TransactionConfig)) (neo4j.Result, error) {
+	res, err := s.session.Run(cypher, toBoltParams(params), boltTxConfig(configurers)...)
+	if err != nil {
+		return nil, err
+	}
+	return &boltResult{result: res, params: params}, nil
+}
+
+func (s *boltSession) Close() error {
+	return s.session.Close()
+}
+
+func boltWork(work neo4j.TransactionWork) bolt.TransactionWork {
+	return func(tx *bolt.Transaction) (interface{}, error) {
+		return work(&boltTransaction{tx: tx})
+	}
+}
+
+func boltTxConfig(configurers []func(*neo4j.TransactionConfig)) []func(*bolt.TransactionConfig) {
+	if len(configurers) == 0 {
+		return nil
+	}
+	var config neo4j.TransactionConfig
+	for _, configurer := range configurers {
+		configurer(&config)
+	}
+	return []func(*bolt.TransactionConfig){
+		bolt.WithTxTimeout(config.Timeout),
+		bolt.WithTxMetadata(toBoltParams(config.Metadata)),
+	}
+}
+
+// boltTransaction adapts a bolt.Transaction to a neo4j.Transaction
+type boltTransaction struct {
+	tx *bolt.Transaction
+}
+
+func (t *boltTransaction) Run(cypher string, params map[string]interface{}) (neo4j.Result, error) {
+	res, err := t.tx.Run(cypher, toBoltParams(params))
+	if err != nil {
+		return nil, err
+	}
+	return &boltResult{result: res, params: params}, nil
+}
+
+func (t *boltTransaction) Commit() error {
+	return t.tx.Commit()
+}
+
+func (t *boltTransaction) Rollback() error {
+	return t.tx.Rollback()
+}
+
+func (t *boltTransaction) Close() error {
+	return t.tx.Close()
+}
+
+// boltResult adapts a bolt.Result to a neo4j.Result
+type boltResult struct {
+	result  *bolt.Result
+	params  map[string]interface{}
+	current neo4j.Record
+}
+
+func (r *boltResult) Keys() ([]string, error) {
+	return r.result.Keys()
+}
+
+func (r *boltResult) Next() bool {
+	if !r.result.Next() {
+		r.current = nil
+		return false
+	}
+	rec := r.result.Record()
+	values := make([]interface{}, len(rec.Values()))
+	for i, v := range rec.Values() {
+		values[i] = fromBolt(v)
+	}
+	r.current = &boltRecord{keys: rec.Keys(), values: values}
+	return true
+}
+
+func (r *boltResult) Err() error {
+	return r.result.Err()
+}
+
+func (r *boltResult) Record() neo4j.Record {
+	return r.current
+}
+
+func (r *boltResult) Summary() (neo4j.ResultSummary, error) {
+	s, err := r.result.Summary()
+	if err != nil {
+		return nil, err
+	}
+	return &boltSummary{summary: s, params: r.params}, nil
+}
+
+func (r *boltResult) Consume() (neo4j.ResultSummary, error) {
+	s, err := r.result.Consume()
+	if err != nil {
+		return nil, err
+	}
+	return &boltSummary{summary: s, params: r.params}, nil
+}
+
+// boltRecord is a neo4j.Record holding values converted from the bolt package
+type boltRecord struct {
+	keys   []string
+	values []interface{}
+}
+
+func (r *boltRecord) Keys() []string {
+	return r.keys
+}
+
+func (r *boltRecord) Values() []interface{} {
+	return r.values
+}
+
+func (r *boltRecord) Get(key string) (interface{}, bool) {
+	for i, k := range r.keys {
+		if k == key && i < len(r.values) {
+			return r.values[i], true
+		}
+	}
+	return nil, false
+}
+
+func (r *boltRecord) GetByIndex(index int) interface{} {
+	if index < 0 || index >= len(r.values) {
+		return nil
+	}
+	return r.values[index]
+}
+
+// boltSummary adapts a bolt.Summary to a neo4j.ResultSummary
+type boltSummary struct {
+	summary *bolt.Summary
+	params  map[string]interface{}
+}
+
+func (s *boltSummary) Server() neo4j.ServerInfo {
+	return &boltServer{info: s.summary.Server}
+}
+
+func (s *boltSummary) Statement() neo4j.Statement {
+	return &boltStatement{text: s.summary.Statement, params: s.params}
+}
+
+func (s *boltSummary) StatementType() neo4j.StatementType {
+	return neo4j.StatementType(s.summary.StatementType)
+}
+
+func (s *boltSummary) Counters() neo4j.Counters {
+	return &boltCounters{c: s.summary.Counters}
+}
+
+func (s *boltSummary) Plan() neo4j.Plan {
+	return nil
+}
+
+func (s *boltSummary) Profile() neo4j.ProfiledPlan {
+	return nil
+}
+
+func (s *boltSummary) Notifications() []neo4j.Notification {
+	return nil
+}
+
+func (s *boltSummary) ResultAvailableAfter() time.Duration {
+	return s.summary.ResultAvailableAfter
+}
+
+func (s *boltSummary) ResultConsumedAfter() time.Duration {
+	return s.summary.ResultConsumedAfter
+}
+
+type boltServer struct {
+	info bolt.ServerInfo
+}
+
+func (s *boltServer) Address() string { return s.info.Address }
+func (s *boltServer) Version() string { return s.info.Agent }
+
+type boltStatement struct {
+	text   string
+	params map[string]interface{}
+}
+
+func (s *boltStatement) Text() string                   { return s.text }
+func (s *boltStatement) Params() map[string]interface{} { return s.params }
+
+type boltCounters struct {
+	c bolt.Counters
+}
+
+func (c *boltCounters) ContainsUpdates() bool     { return c.c.ContainsUpdates() }
+func (c *boltCounters) NodesCreated() int         { return c.c.NodesCreated }
+func (c *boltCounters) NodesDeleted() int         { return c.c.NodesDeleted }
+func (c *boltCounters) RelationshipsCreated() int { return c.c.RelationshipsCreated }
+func (c *boltCounters) RelationshipsDeleted() int { return c.c.RelationshipsDeleted }
+func (c *boltCounters) PropertiesSet() int        { return c.c.PropertiesSet }
+func (c *boltCounters) LabelsAdded() int          { return c.c.LabelsAdded }
+func (c *boltCounters) LabelsRemoved() int        { return c.c.LabelsRemoved }
+func (c *boltCounters) IndexesAdded() int         { return c.c.IndexesAdded }
+func (c *boltCounters) IndexesRemoved() int       { return c.c.IndexesRemoved }
+func (c *boltCounters) ConstraintsAdded() int     { return c.c.ConstraintsAdded }
+func (c *boltCounters) ConstraintsRemoved() int   { return c.c.ConstraintsRemoved }
+
+// fromBolt converts the values of the bolt package into those of the neo4j driver
+func fromBolt(v interface{}) interface{} {
+	switch v := v.(type) {
+	case *bolt.Node:
+		return fromBoltNode(v)
+	case *bolt.Relationship:
+		return fromBoltRelationship(v)
+	case *bolt.Path:
+		p := &graphPath{}
+		for _, n := range v.Nodes {
+			p.nodes = append(p.nodes, fromBoltNode(n))
+		}
+		for _, r := range v.Relationships {
+			p.rels = append(p.rels, fromBoltRelationship(r))
+		}
+		return p
+	case bolt.Date:
+		return neo4j.DateOf(time.Time(v))
+	case bolt.Time:
+		return neo4j.OffsetTimeOf(time.Time(v))
+	case bolt.LocalTime:
+		return neo4j.LocalTimeOf(time.Time(v))
+	case bolt.LocalDateTime:
+		return neo4j.LocalDateTimeOf(time.Time(v))
+	case bolt.Duration:
+		return neo4j.DurationOf(v.Months, v.Days, v.Seconds, v.Nanos)
+	case bolt.Point2D:
+		return neo4j.NewPoint2D(int(v.SRID), v.X, v.Y)
+	case bolt.Point3D:
+		return neo4j.NewPoint3D(int(v.SRID), v.X, v.Y, v.Z)
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = fromBolt(item)
+		}
+		return list
+	case map[string]interface{}:
+		return fromBoltProps(v)
+	}
+	return v
+}
+
+func fromBoltNode(n *bolt.Node) neo4j.Node {
+	return &graphNode{id: n.ID, labels: n.Labels, props: fromBoltProps(n.Props)}
+}
+
+func fromBoltRelationship(r *bolt.Relationship) neo4j.Relationship {
+	return &graphRelationship{id: r.ID, startID: r.StartID, endID: r.EndID, relType: r.Type, props: fromBoltProps(r.Props)}
+}
+
+func fromBoltProps(props map[string]interface{}) map[string]interface{} {
+	if props == nil {
+		return nil
+	}
+	converted := make(map[string]interface{}, len(props))
+	for k, v := range props {
+		converted[k] = fromBolt(v)
+	}
+	return converted
+}
+
+// toBolt converts the values of the neo4j driver into those of the bolt package
+func toBolt(v interface{}) interface{} {
+	switch v := v.(type) {
+	case neo4j.Date:
+		return bolt.Date(v.Time())
+	case neo4j.OffsetTime:
+		return bolt.Time(v.Time())
+	case neo4j.LocalTime:
+		return bolt.LocalTime(v.Time())
+	case neo4j.LocalDateTime:
+		return bolt.LocalDateTime(v.Time())
+	case neo4j.Duration:
+		return bolt.Duration{Months: v.Months(), Days: v.Days(), Seconds: v.Seconds(), Nanos: v.Nanos()}
+	case *neo4j.Point:
+		if v == nil {
+			return nil
+		}
+		if z := v.Z(); !math.IsNaN(z) {
+			return bolt.Point3D{SRID: uint32(v.SrId()), X: v.X(), Y: v.Y(), Z: z}
+		}
+		return bolt.Point2D{SRID: uint32(v.SrId()), X: v.X(), Y: v.Y()}
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = toBolt(item)
+		}
+		return list
+	case map[string]interface{}:
+		return toBoltParams(v)
+	case Args:
+		return toBoltParams(v)
+	}
+	return v
+}
+
+func toBoltParams(params map[string]interface{}) map[string]interface{} {
+	if params == nil {
+		return nil
+	}
+	converted := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		converted[k] = toBolt(v)
+	}
+	return converted
+}
@@ -0,0 +1,381 @@
+package bolt
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/syllabix/neox/boltstub"
+)
+
+func fastRetries(config *Config) {
+	config.RetryDelay = time.Millisecond
+	config.MaxTransactionRetryTime = time.Second
+}
+
+// serve starts a server playing the script, and reports the failures of the conversation
+// once the test and its deferred calls are over
+func serve(t *testing.T, script string) *boltstub.Server {
+	t.Helper()
+	srv, err := boltstub.NewServer(boltstub.MustParse(script))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := srv.Close(); err != nil {
+			t.Errorf("Server.Close() error = %v", err)
+		}
+	})
+	return srv
+}
+
+func TestSession_Run(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		version string
+		pull    string
+	}{
+		{name: "Should run statements over bolt 4", version: "4.4", pull: `PULL {"n": -1}`},
+		{name: "Should run statements over bolt 3", version: "3", pull: `PULL_ALL`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := serve(t, fmt.Sprintf(`
+				!: BOLT %s
+				!: AUTO RESET
+
+				C: HELLO {"scheme": "basic", "principal": "neo4j", "credentials": "secret", "user_agent": "*"}
+				S: SUCCESS {"server": "Neo4j/4.0.0", "connection_id": "bolt-1"}
+				C: RUN "match (u:User) where u.age > $age return u.name as name, u.age as age" {"age": 16} {"mode": "r", "bookmarks": ["bookmark:0"]}
+				S: SUCCESS {"fields": ["name", "age"], "t_first": 3}
+				C: %s
+				S: RECORD ["Yolanda Erasmus", 17]
+				   RECORD ["Jordan Ames", 34]
+				   SUCCESS {"type": "rw", "bookmark": "bookmark:1", "t_last": 5, "stats": {"nodes-created": 2, "properties-set": 4}}
+			`, tt.version, tt.pull))
+
+			driver, err := NewDriver(srv.URL(), BasicAuth("neo4j", "secret", ""))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer driver.Close()
+
+			session, err := driver.Session(AccessModeRead, "bookmark:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer session.Close()
+
+			result, err := session.Run("match (u:User) where u.age > $age return u.name as name, u.age as age", map[string]interface{}{"age": 16})
+			if err != nil {
+				t.Fatalf("Session.Run() error = %v", err)
+			}
+			if keys, _ := result.Keys(); !reflect.DeepEqual(keys, []string{"name", "age"}) {
+				t.Errorf("Result.Keys() got = %v", keys)
+			}
+
+			var names []interface{}
+			for result.Next() {
+				name, _ := result.Record().Get("name")
+				names = append(names, name)
+			}
+			if err := result.Err(); err != nil {
+				t.Fatalf("Result.Err() = %v", err)
+			}
+			if !reflect.DeepEqual(names, []interface{}{"Yolanda Erasmus", "Jordan Ames"}) {
+				t.Errorf("Result.Next() got = %v", names)
+			}
+
+			summary, err := result.Consume()
+			if err != nil {
+				t.Fatalf("Result.Consume() error = %v", err)
+			}
+			if summary.Counters.NodesCreated != 2 || summary.StatementType != StatementTypeReadWrite {
+				t.Errorf("Result.Consume() got = %+v", summary)
+			}
+			if summary.ResultAvailableAfter != 3*time.Millisecond || summary.ResultConsumedAfter != 5*time.Millisecond {
+				t.Errorf("Result.Consume() timings = %v, %v", summary.ResultAvailableAfter, summary.ResultConsumedAfter)
+			}
+			if summary.Server.Agent != "Neo4j/4.0.0" {
+				t.Errorf("Result.Consume() server = %+v", summary.Server)
+			}
+			if session.LastBookmark() != "bookmark:1" {
+				t.Errorf("Session.LastBookmark() = %v, want bookmark:1", session.LastBookmark())
+			}
+		})
+	}
+}
+
+func TestSession_RunFailure(t *testing.T) {
+	t.Parallel()
+
+	srv := serve(t, `
+		!: AUTO HELLO
+		!: AUTO RESET
+
+		C: RUN "invalid" {} {}
+		S: FAILURE {"code": "Neo.ClientError.Statement.SyntaxError", "message": "Invalid input 'i'"}
+		C: PULL {"n": -1}
+		S: IGNORED
+		C: RUN "return 1 as n" {} {}
+		S: SUCCESS {"fields": ["n"]}
+		C: PULL {"n": -1}
+		S: RECORD [1]
+		   SUCCESS {}
+	`)
+
+	driver, _ := NewDriver(srv.URL(), NoAuth())
+	defer driver.Close()
+	session, _ := driver.Session(AccessModeWrite)
+	defer session.Close()
+
+	_, err := session.Run("invalid", nil)
+	var failure *Error
+	if !errors.As(err, &failure) || failure.Code() != "Neo.ClientError.Statement.SyntaxError" || failure.Classification() != "ClientError" {
+		t.Fatalf("Session.Run() error = %v, want a syntax error", err)
+	}
+
+	result, err := session.Run("return 1 as n", nil)
+	if err != nil {
+		t.Fatalf("Session.Run() after a failure error = %v", err)
+	}
+	if !result.Next() || result.Record().GetByIndex(0) != int64(1) {
+		t.Errorf("Result.Next() after a failure got = %v, %v", result.Record(), result.Err())
+	}
+}
+
+func TestSession_Buffering(t *testing.T) {
+	t.Parallel()
+
+	long := strings.Repeat("x", 70000)
+	srv := serve(t, fmt.Sprintf(`
+		!: AUTO HELLO
+		!: AUTO RESET
+
+		C: RUN "return $s as s" {"s": %[1]q} {}
+		S: SUCCESS {"fields": ["s"]}
+		C: PULL {"n": -1}
+		S: RECORD [%[1]q]
+		   RECORD ["y"]
+		   SUCCESS {}
+		C: RUN "return 'y' as s" {} {}
+		S: SUCCESS {"fields": ["s"]}
+		C: PULL {"n": -1}
+		S: RECORD ["y"]
+		   SUCCESS {}
+	`, long))
+
+	driver, _ := NewDriver(srv.URL(), NoAuth())
+	defer driver.Close()
+	session, _ := driver.Session(AccessModeWrite)
+	defer session.Close()
+
+	first, err := session.Run("return $s as s", map[string]interface{}{"s": long})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := session.Run("return 'y' as s", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []int
+	for first.Next() {
+		s, _ := first.Record().Get("s")
+		got = append(got, len(s.(string)))
+	}
+	if !reflect.DeepEqual(got, []int{70000, 1}) {
+		t.Errorf("Result.Next() of a buffered result got lengths = %v", got)
+	}
+	if _, err := second.Consume(); err != nil {
+		t.Errorf("Result.Consume() error = %v", err)
+	}
+}
+
+func TestSession_WriteTransaction(t *testing.T) {
+	t.Parallel()
+
+	srv := serve(t, `
+		!: AUTO HELLO
+		!: AUTO RESET
+
+		C: BEGIN {"tx_timeout": 2000, "tx_metadata": {"app": "neox"}}
+		S: SUCCESS {}
+		C: RUN "create (u:User) return id(u) as id" {} {}
+		S: FAILURE {"code": "Neo.TransientError.Transaction.DeadlockDetected", "message": "deadlock"}
+		C: PULL {"n": -1}
+		S: IGNORED
+		C: BEGIN {"tx_timeout": 2000, "tx_metadata": {"app": "neox"}}
+		S: SUCCESS {}
+		C: RUN "create (u:User) return id(u) as id" {} {}
+		S: SUCCESS {"fields": ["id"]}
+		C: PULL {"n": -1}
+		S: RECORD [7]
+		   SUCCESS {}
+		C: COMMIT
+		S: SUCCESS {"bookmark": "bookmark:9"}
+	`)
+
+	driver, _ := NewDriver(srv.URL(), NoAuth(), fastRetries)
+	defer driver.Close()
+	session, _ := driver.Session(AccessModeWrite)
+	defer session.Close()
+
+	var attempts int
+	id, err := session.WriteTransaction(func(tx *Transaction) (interface{}, error) {
+		attempts++
+		result, err := tx.Run("create (u:User) return id(u) as id", nil)
+		if err != nil {
+			return nil, err
+		}
+		if !result.Next() {
+			return nil, result.Err()
+		}
+		return result.Record().GetByIndex(0), nil
+	}, WithTxTimeout(2*time.Second), WithTxMetadata(map[string]interface{}{"app": "neox"}))
+	if err != nil {
+		t.Fatalf("Session.WriteTransaction() error = %v", err)
+	}
+	if id != int64(7) || attempts != 2 {
+		t.Errorf("Session.WriteTransaction() got = %v after %d attempts", id, attempts)
+	}
+	if session.LastBookmark() != "bookmark:9" {
+		t.Errorf("Session.LastBookmark() = %v, want bookmark:9", session.LastBookmark())
+	}
+}
+
+func TestTransaction_Commit(t *testing.T) {
+	t.Parallel()
+
+	srv := serve(t, `
+		!: AUTO HELLO
+		!: AUTO RESET
+
+		C: BEGIN {}
+		S: SUCCESS {}
+		C: RUN "match (u:User) set u.seen = true return id(u) as id" {} {}
+		S: SUCCESS {"fields": ["id"]}
+		C: PULL {"n": -1}
+		S: RECORD [1]
+		   FAILURE {"code": "Neo.TransientError.Transaction.DeadlockDetected", "message": "deadlock"}
+	`)
+	driver, _ := NewDriver(srv.URL(), NoAuth())
+	defer driver.Close()
+	session, _ := driver.Session(AccessModeWrite)
+	defer session.Close()
+
+	tx, err := session.BeginTransaction()
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := tx.Run("match (u:User) set u.seen = true return id(u) as id", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for result.Next() {
+	}
+
+	err = tx.Commit()
+	var failure *Error
+	if !errors.As(err, &failure) || failure.Code() != "Neo.TransientError.Transaction.DeadlockDetected" {
+		t.Fatalf("Transaction.Commit() error = %v, want the failure of the statement", err)
+	}
+	if !IsTransient(err) {
+		t.Errorf("IsTransient() got = %v, want %v", false, true)
+	}
+}
+
+func TestTransaction_Rollback(t *testing.T) {
+	t.Parallel()
+
+	srv := serve(t, `
+		!: AUTO HELLO
+		!: AUTO RESET
+
+		C: BEGIN {}
+		S: SUCCESS {}
+		C: ROLLBACK
+		S: SUCCESS {}
+	`)
+	driver, _ := NewDriver(srv.URL(), NoAuth())
+	defer driver.Close()
+	session, _ := driver.Session(AccessModeWrite)
+	defer session.Close()
+
+	tx, err := session.BeginTransaction()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := session.Run("return 1", nil); err != ErrOpenTransaction {
+		t.Errorf("Session.Run() error = %v, want %v", err, ErrOpenTransaction)
+	}
+	if err := tx.Close(); err != nil {
+		t.Errorf("Transaction.Close() error = %v", err)
+	}
+	if err := tx.Commit(); err != ErrClosed {
+		t.Errorf("Transaction.Commit() error = %v, want %v", err, ErrClosed)
+	}
+}
+
+func TestDriver_Connectivity(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should reject unsupported protocol versions", func(t *testing.T) {
+		srv, err := boltstub.NewServer(boltstub.MustParse("!: BOLT 5.0"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer srv.Close()
+
+		driver, _ := NewDriver(srv.URL(), NoAuth())
+		defer driver.Close()
+		if err := driver.VerifyConnectivity(); !IsConnectivity(err) {
+			t.Errorf("Driver.VerifyConnectivity() error = %v, want a connectivity error", err)
+		}
+	})
+
+	t.Run("Should report the version of the server", func(t *testing.T) {
+		srv := serve(t, "!: AUTO HELLO\n!: AUTO RESET")
+		driver, _ := NewDriver(srv.URL(), NoAuth())
+		defer driver.Close()
+		version, agent, err := driver.ServerVersion()
+		if err != nil || version != (Version{4, 4}) || agent != "Neo4j/4.4.0" {
+			t.Errorf("Driver.ServerVersion() = %v, %v, %v", version, agent, err)
+		}
+	})
+
+	t.Run("Should reuse pooled connections", func(t *testing.T) {
+		// the script is played for a single connection, the server reports any other
+		srv := serve(t, "!: AUTO HELLO\n!: AUTO RESET")
+		driver, _ := NewDriver(srv.URL(), NoAuth())
+		defer driver.Close()
+		for i := 0; i < 3; i++ {
+			if err := driver.VerifyConnectivity(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+
+	t.Run("Should reject unsupported schemes", func(t *testing.T) {
+		if _, err := NewDriver("neo4j://localhost", NoAuth()); err == nil {
+			t.Errorf("NewDriver() error = nil, want an error")
+		}
+	})
+
+	t.Run("Should fail to reach a closed port", func(t *testing.T) {
+		srv, err := boltstub.NewServer()
+		if err != nil {
+			t.Fatal(err)
+		}
+		srv.Close()
+		driver, _ := NewDriver(srv.URL(), NoAuth())
+		if _, _, err := driver.ServerVersion(); !IsConnectivity(err) {
+			t.Errorf("Driver.ServerVersion() error = %v, want a connectivity error", err)
+		}
+	})
+}
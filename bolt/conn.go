@@ -0,0 +1,345 @@
+package bolt
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"time"
+
+	"github.com/syllabix/neox/packstream"
+)
+
+// tags of the request and response messages
+const (
+	msgHello    = 0x01
+	msgGoodbye  = 0x02
+	msgReset    = 0x0F
+	msgRun      = 0x10
+	msgBegin    = 0x11
+	msgCommit   = 0x12
+	msgRollback = 0x13
+	msgPull     = 0x3F
+	msgSuccess  = 0x70
+	msgRecord   = 0x71
+	msgIgnored  = 0x7E
+	msgFailure  = 0x7F
+)
+
+// preamble identifies the Bolt protocol at the start of the handshake
+var preamble = []byte{0x60, 0x60, 0xB0, 0x17}
+
+// versions are the protocol versions proposed during the handshake, in order of
+// preference. Each holds a range, a minor and a major version, the range
+// allowing the minor versions below the proposed one to be agreed on too
+var versions = [4][4]byte{
+	{0x00, 0x02, 0x04, 0x04},
+	{0x00, 0x00, 0x01, 0x04},
+	{0x00, 0x00, 0x00, 0x04},
+	{0x00, 0x00, 0x00, 0x03},
+}
+
+// Version is a Bolt protocol version
+type Version struct {
+	Major int
+	Minor int
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// conn is a connection to a server, it is not safe for concurrent use
+type conn struct {
+	address string
+	nc      net.Conn
+	rd      *bufio.Reader
+	wr      *bufio.Writer
+	version Version
+	agent   string
+	created time.Time
+
+	// pending is the number of requests sent for which no response was read
+	pending int
+
+	// failed is set once the server reported a failure, after which it ignores
+	// requests until the connection is reset
+	failed bool
+
+	// broken is set when the connection can no longer be used
+	broken bool
+
+	buf []byte
+	msg []byte
+}
+
+// dial opens a connection to the provided address and performs the handshake
+// and authentication
+func dial(ctx context.Context, address string, auth AuthToken, config *Config) (*conn, error) {
+	dialer := &net.Dialer{Timeout: config.SocketConnectTimeout, KeepAlive: 30 * time.Second}
+	if !config.SocketKeepalive {
+		dialer.KeepAlive = -1
+	}
+
+	var (
+		nc  net.Conn
+		err error
+	)
+	if config.Encrypted {
+		tlsConfig := config.TLSConfig
+		if tlsConfig == nil {
+			host, _, _ := net.SplitHostPort(address)
+			tlsConfig = &tls.Config{ServerName: host}
+		}
+		nc, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", address)
+	} else {
+		nc, err = dialer.DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		return nil, &ConnectivityError{Address: address, Err: err}
+	}
+
+	c := &conn{
+		address: address,
+		nc:      nc,
+		rd:      bufio.NewReader(nc),
+		wr:      bufio.NewWriter(nc),
+		created: time.Now(),
+	}
+	if err := c.handshake(); err != nil {
+		nc.Close()
+		return nil, err
+	}
+	if err := c.hello(auth, config.UserAgent); err != nil {
+		nc.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *conn) handshake() error {
+	if _, err := c.wr.Write(preamble); err != nil {
+		return c.connectivity(err)
+	}
+	for _, v := range versions {
+		if _, err := c.wr.Write(v[:]); err != nil {
+			return c.connectivity(err)
+		}
+	}
+	if err := c.wr.Flush(); err != nil {
+		return c.connectivity(err)
+	}
+
+	var agreed [4]byte
+	if _, err := io.ReadFull(c.rd, agreed[:]); err != nil {
+		return c.connectivity(err)
+	}
+	c.version = Version{Major: int(agreed[3]), Minor: int(agreed[2])}
+	if c.version.Major < 3 || c.version.Major > 4 {
+		return &ConnectivityError{
+			Address: c.address,
+			Err:     fmt.Errorf("server does not support bolt 3 or 4, agreed on version %v", c.version),
+		}
+	}
+	return nil
+}
+
+func (c *conn) hello(auth AuthToken, agent string) error {
+	extra := auth.tokens()
+	extra["user_agent"] = agent
+	meta, err := c.request(msgHello, extra)
+	if err != nil {
+		return err
+	}
+	c.agent, _ = meta["server"].(string)
+	return nil
+}
+
+// connectivity marks the connection as broken and wraps the provided network failure
+func (c *conn) connectivity(err error) error {
+	c.broken = true
+	return &ConnectivityError{Address: c.address, Err: err}
+}
+
+// send buffers a request message, it is sent on the next flush
+func (c *conn) send(tag byte, fields ...interface{}) error {
+	var err error
+	c.buf, err = packstream.Append(c.buf[:0], packstream.Structure{Tag: tag, Fields: fields})
+	if err != nil {
+		return err
+	}
+
+	for data := c.buf; len(data) > 0; {
+		size := len(data)
+		if size > math.MaxUint16 {
+			size = math.MaxUint16
+		}
+		var header [2]byte
+		binary.BigEndian.PutUint16(header[:], uint16(size))
+		if _, err := c.wr.Write(header[:]); err != nil {
+			return c.connectivity(err)
+		}
+		if _, err := c.wr.Write(data[:size]); err != nil {
+			return c.connectivity(err)
+		}
+		data = data[size:]
+	}
+	if _, err := c.wr.Write([]byte{0, 0}); err != nil {
+		return c.connectivity(err)
+	}
+	c.pending++
+	return nil
+}
+
+func (c *conn) flush() error {
+	if err := c.wr.Flush(); err != nil {
+		return c.connectivity(err)
+	}
+	return nil
+}
+
+// receive reads the next response message
+func (c *conn) receive() (packstream.Structure, error) {
+	c.msg = c.msg[:0]
+	for {
+		var header [2]byte
+		if _, err := io.ReadFull(c.rd, header[:]); err != nil {
+			return packstream.Structure{}, c.connectivity(err)
+		}
+		size := int(binary.BigEndian.Uint16(header[:]))
+		if size == 0 {
+			if len(c.msg) == 0 {
+				// a no-op chunk, sent by servers to keep the connection alive
+				continue
+			}
+			break
+		}
+		start := len(c.msg)
+		c.msg = append(c.msg, make([]byte, size)...)
+		if _, err := io.ReadFull(c.rd, c.msg[start:]); err != nil {
+			return packstream.Structure{}, c.connectivity(err)
+		}
+	}
+
+	d := packstream.NewDecoder(c.msg)
+	d.Hydrate = packstream.Hydrate
+	v, err := d.Decode()
+	if err != nil {
+		c.broken = true
+		return packstream.Structure{}, err
+	}
+	msg, ok := v.(packstream.Structure)
+	if !ok {
+		c.broken = true
+		return packstream.Structure{}, &ProtocolError{Message: fmt.Sprintf("received a %T instead of a message", v)}
+	}
+	return msg, nil
+}
+
+// response reads the response to a request that is not expected to stream records,
+// returning the metadata of a SUCCESS message or the failure reported by the server
+func (c *conn) response() (map[string]interface{}, error) {
+	msg, err := c.receive()
+	if err != nil {
+		return nil, err
+	}
+	return c.summary(msg)
+}
+
+// summary interprets a message ending the response to a request
+func (c *conn) summary(msg packstream.Structure) (map[string]interface{}, error) {
+	c.pending--
+	switch msg.Tag {
+	case msgSuccess:
+		meta := map[string]interface{}{}
+		if len(msg.Fields) > 0 {
+			if m, ok := msg.Fields[0].(map[string]interface{}); ok {
+				meta = m
+			}
+		}
+		return meta, nil
+	case msgFailure:
+		c.failed = true
+		var meta map[string]interface{}
+		if len(msg.Fields) > 0 {
+			meta, _ = msg.Fields[0].(map[string]interface{})
+		}
+		code, _ := meta["code"].(string)
+		message, _ := meta["message"].(string)
+		return nil, NewError(code, message)
+	case msgIgnored:
+		c.failed = true
+		return nil, &ProtocolError{Message: "request ignored by the server after a failure"}
+	}
+	c.broken = true
+	return nil, &ProtocolError{Message: fmt.Sprintf("unexpected message 0x%02X", msg.Tag)}
+}
+
+// request sends a single request and reads its response
+func (c *conn) request(tag byte, fields ...interface{}) (map[string]interface{}, error) {
+	if err := c.send(tag, fields...); err != nil {
+		return nil, err
+	}
+	if err := c.flush(); err != nil {
+		return nil, err
+	}
+	return c.response()
+}
+
+// drain reads the responses of the requests that were sent, discarding
+// records. It returns the first failure reported by the server
+func (c *conn) drain() error {
+	var first error
+	for c.pending > 0 {
+		msg, err := c.receive()
+		if err != nil {
+			return err
+		}
+		if msg.Tag == msgRecord {
+			continue
+		}
+		if _, err := c.summary(msg); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// reset drains outstanding responses and returns the connection to a clean state
+func (c *conn) reset() error {
+	if err := c.flush(); err != nil {
+		return err
+	}
+	c.drain()
+	if c.broken {
+		return &ConnectivityError{Address: c.address, Err: io.ErrUnexpectedEOF}
+	}
+	if _, err := c.request(msgReset); err != nil {
+		c.broken = true
+		return err
+	}
+	c.failed = false
+	return nil
+}
+
+// pull sends a request for all the records of the last statement
+func (c *conn) pull() error {
+	if c.version.Major >= 4 {
+		return c.send(msgPull, map[string]interface{}{"n": int64(-1)})
+	}
+	return c.send(msgPull)
+}
+
+// close says goodbye to the server and closes the connection
+func (c *conn) close() error {
+	if !c.broken {
+		c.nc.SetWriteDeadline(time.Now().Add(time.Second))
+		c.send(msgGoodbye)
+		c.flush()
+	}
+	return c.nc.Close()
+}
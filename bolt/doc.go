@@ -0,0 +1,27 @@
+// Package bolt is a pure Go client for the Bolt protocol, versions 3 and 4, used to run
+// cypher statements against a single Neo4j server without cgo.
+//
+// It covers the subset of the protocol needed by neox: the handshake, authentication,
+// auto-commit statements, explicit and retried transactions, bookmarks and a connection
+// pool per driver. Routing to a causal cluster is not supported.
+//
+//	driver, err := bolt.NewDriver("bolt://localhost:7687", bolt.BasicAuth("neo4j", "password", ""))
+//	if err != nil {
+//		return err
+//	}
+//	defer driver.Close()
+//
+//	session, err := driver.Session(bolt.AccessModeRead)
+//	if err != nil {
+//		return err
+//	}
+//	defer session.Close()
+//
+//	result, err := session.Run("match (u:User) return u.name as name", nil)
+//	for result.Next() {
+//		name, _ := result.Record().Get("name")
+//	}
+//
+// The package does not depend on the neo4j driver, neox.NewBoltDriver adapts it
+// to the neox.Driver type
+package bolt
@@ -0,0 +1,314 @@
+package bolt
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// AccessMode is the access mode of a session
+type AccessMode int
+
+const (
+	// AccessModeWrite is the access mode of sessions that write to the database
+	AccessModeWrite AccessMode = 0
+	// AccessModeRead is the access mode of sessions that only read from the database
+	AccessModeRead AccessMode = 1
+)
+
+// DefaultPort is the port used when the target of a driver does not have one
+const DefaultPort = "7687"
+
+// AuthToken holds the credentials sent to the server
+type AuthToken struct {
+	Scheme      string
+	Principal   string
+	Credentials string
+	Realm       string
+	Parameters  map[string]interface{}
+}
+
+// NoAuth returns a token for servers with authentication disabled
+func NoAuth() AuthToken {
+	return AuthToken{Scheme: "none"}
+}
+
+// BasicAuth returns a token authenticating with a username and password
+func BasicAuth(username, password, realm string) AuthToken {
+	return AuthToken{Scheme: "basic", Principal: username, Credentials: password, Realm: realm}
+}
+
+// KerberosAuth returns a token authenticating with a base64 encoded kerberos ticket
+func KerberosAuth(ticket string) AuthToken {
+	return AuthToken{Scheme: "kerberos", Credentials: ticket}
+}
+
+func (a AuthToken) tokens() map[string]interface{} {
+	tokens := map[string]interface{}{"scheme": a.Scheme}
+	if a.Scheme == "" {
+		tokens["scheme"] = "none"
+	}
+	if a.Principal != "" {
+		tokens["principal"] = a.Principal
+	}
+	if a.Credentials != "" {
+		tokens["credentials"] = a.Credentials
+	}
+	if a.Realm != "" {
+		tokens["realm"] = a.Realm
+	}
+	if a.Parameters != nil {
+		tokens["parameters"] = a.Parameters
+	}
+	return tokens
+}
+
+// Config holds the settings of a driver
+type Config struct {
+	// Encrypted enables TLS, it is set for targets with the bolt+s scheme
+	Encrypted bool
+
+	// TLSConfig is the configuration of encrypted connections, the system
+	// roots and the host of the target are used when nil
+	TLSConfig *tls.Config
+
+	// UserAgent identifies the client to the server
+	UserAgent string
+
+	// Database is the database statements run against, on servers supporting bolt 4.
+	// The default database of the server is used when empty
+	Database string
+
+	// MaxConnectionPoolSize is the maximum number of connections to the server
+	MaxConnectionPoolSize int
+
+	// MaxConnectionLifetime is the maximum age of a pooled connection, older
+	// connections are closed instead of being reused. Zero disables the limit
+	MaxConnectionLifetime time.Duration
+
+	// ConnectionAcquisitionTimeout is the maximum time spent waiting for a
+	// connection when the pool is full
+	ConnectionAcquisitionTimeout time.Duration
+
+	// SocketConnectTimeout is the timeout for establishing connections
+	SocketConnectTimeout time.Duration
+
+	// SocketKeepalive enables TCP keep alive probes on connections
+	SocketKeepalive bool
+
+	// MaxTransactionRetryTime is the maximum time transaction functions
+	// spend retrying a unit of work failing with a transient error
+	MaxTransactionRetryTime time.Duration
+
+	// RetryDelay is the delay before the first retry of a transaction function,
+	// it is doubled after every attempt
+	RetryDelay time.Duration
+}
+
+func defaultConfig() *Config {
+	return &Config{
+		UserAgent:                    "neox/bolt",
+		MaxConnectionPoolSize:        100,
+		MaxConnectionLifetime:        time.Hour,
+		ConnectionAcquisitionTimeout: time.Minute,
+		SocketConnectTimeout:         5 * time.Second,
+		SocketKeepalive:              true,
+		MaxTransactionRetryTime:      30 * time.Second,
+		RetryDelay:                   time.Second,
+	}
+}
+
+// Driver is a pool of connections to a Neo4j server. It's safe for concurrent use
+type Driver struct {
+	target url.URL
+	auth   AuthToken
+	config *Config
+	pool   *pool
+}
+
+// NewDriver returns a driver connecting to the server at the provided target, with the
+// bolt or bolt+s scheme, ie: bolt://localhost:7687. Connections are opened lazily
+func NewDriver(target string, auth AuthToken, configurers ...func(*Config)) (*Driver, error) {
+	parsed, err := url.Parse(target)
+	if err != nil {
+		return nil, err
+	}
+
+	config := defaultConfig()
+	switch parsed.Scheme {
+	case "bolt":
+	case "bolt+s":
+		config.Encrypted = true
+	default:
+		return nil, fmt.Errorf("bolt: url scheme %s is not supported", parsed.Scheme)
+	}
+	for _, configurer := range configurers {
+		configurer(config)
+	}
+	if config.MaxConnectionPoolSize <= 0 {
+		return nil, fmt.Errorf("bolt: invalid connection pool size %d", config.MaxConnectionPoolSize)
+	}
+
+	address := parsed.Host
+	if parsed.Port() == "" {
+		address = net.JoinHostPort(parsed.Hostname(), DefaultPort)
+	}
+
+	d := &Driver{target: *parsed, auth: auth, config: config}
+	d.pool = newPool(config, func(ctx context.Context) (*conn, error) {
+		return dial(ctx, address, auth, config)
+	})
+	return d, nil
+}
+
+// Target returns the url the driver connects to
+func (d *Driver) Target() url.URL {
+	return d.target
+}
+
+// Session returns a session with the provided access mode. The bookmarks,
+// if any, are waited for before the first transaction of the session
+func (d *Driver) Session(mode AccessMode, bookmarks ...string) (*Session, error) {
+	if d.pool.isClosed() {
+		return nil, ErrClosed
+	}
+	return &Session{
+		driver:    d,
+		mode:      mode,
+		bookmarks: bookmarks,
+	}, nil
+}
+
+// VerifyConnectivity acquires a connection to check that the server can be reached
+func (d *Driver) VerifyConnectivity() error {
+	c, err := d.pool.acquire(context.Background())
+	if err != nil {
+		return err
+	}
+	d.pool.release(c)
+	return nil
+}
+
+// ServerVersion returns the version of the protocol and the agent of the server
+func (d *Driver) ServerVersion() (Version, string, error) {
+	c, err := d.pool.acquire(context.Background())
+	if err != nil {
+		return Version{}, "", err
+	}
+	defer d.pool.release(c)
+	return c.version, c.agent, nil
+}
+
+// Close closes the idle connections of the driver, connections in use are
+// closed once released. Further sessions can not be opened
+func (d *Driver) Close() error {
+	d.pool.close()
+	return nil
+}
+
+// pool holds the idle connections to a server
+type pool struct {
+	config *Config
+	dial   func(ctx context.Context) (*conn, error)
+	slots  chan struct{}
+
+	mu     sync.Mutex
+	idle   []*conn
+	closed bool
+}
+
+func newPool(config *Config, dial func(ctx context.Context) (*conn, error)) *pool {
+	return &pool{
+		config: config,
+		dial:   dial,
+		slots:  make(chan struct{}, config.MaxConnectionPoolSize),
+	}
+}
+
+// acquire returns an idle connection or opens a new one
+func (p *pool) acquire(ctx context.Context) (*conn, error) {
+	if p.config.ConnectionAcquisitionTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.config.ConnectionAcquisitionTimeout)
+		defer cancel()
+	}
+
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ErrPoolFull
+	}
+
+	for {
+		p.mu.Lock()
+		if p.closed {
+			p.mu.Unlock()
+			<-p.slots
+			return nil, ErrClosed
+		}
+		if len(p.idle) == 0 {
+			p.mu.Unlock()
+			break
+		}
+		c := p.idle[len(p.idle)-1]
+		p.idle = p.idle[:len(p.idle)-1]
+		p.mu.Unlock()
+
+		if p.expired(c) {
+			c.close()
+			continue
+		}
+		return c, nil
+	}
+
+	c, err := p.dial(ctx)
+	if err != nil {
+		<-p.slots
+		return nil, err
+	}
+	return c, nil
+}
+
+// release returns a connection to the pool, resetting it if it was left in a
+// failed state. Broken and expired connections are closed
+func (p *pool) release(c *conn) {
+	defer func() { <-p.slots }()
+
+	if !c.broken && (c.failed || c.pending > 0) {
+		c.reset()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if c.broken || p.closed || p.expired(c) {
+		c.close()
+		return
+	}
+	p.idle = append(p.idle, c)
+}
+
+func (p *pool) expired(c *conn) bool {
+	return p.config.MaxConnectionLifetime > 0 && time.Since(c.created) > p.config.MaxConnectionLifetime
+}
+
+func (p *pool) isClosed() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.closed
+}
+
+func (p *pool) close() {
+	p.mu.Lock()
+	idle := p.idle
+	p.idle = nil
+	p.closed = true
+	p.mu.Unlock()
+
+	for _, c := range idle {
+		c.close()
+	}
+}
@@ -0,0 +1,101 @@
+package bolt
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrClosed is returned when using a closed driver, session or transaction
+	ErrClosed = errors.New("bolt: already closed")
+
+	// ErrPoolFull is returned when no connection could be acquired from
+	// the pool within the connection acquisition timeout
+	ErrPoolFull = errors.New("bolt: connection pool is full")
+
+	// ErrOpenTransaction is returned when running an auto-commit statement,
+	// or beginning a transaction, while a transaction is open on the session
+	ErrOpenTransaction = errors.New("bolt: there's already an open transaction on this session")
+)
+
+// Error is a failure reported by the server
+type Error struct {
+	code    string
+	message string
+}
+
+// NewError returns a failure with the provided neo4j status code and message
+func NewError(code, message string) *Error {
+	return &Error{code: code, message: message}
+}
+
+// BoltError marks the error as a failure of the Bolt protocol
+func (e *Error) BoltError() bool {
+	return true
+}
+
+// Code returns the neo4j status code of the failure, ie: Neo.ClientError.Statement.SyntaxError
+func (e *Error) Code() string {
+	return e.code
+}
+
+// Classification returns the classification of the status code, ie: ClientError
+func (e *Error) Classification() string {
+	if parts := strings.Split(e.code, "."); len(parts) >= 2 {
+		return parts[1]
+	}
+	return ""
+}
+
+// Message returns the message of the failure
+func (e *Error) Message() string {
+	return e.message
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("database returned error [%s]: %s", e.code, e.message)
+}
+
+// ConnectivityError is a failure to reach, or to talk to, the server
+type ConnectivityError struct {
+	Address string
+	Err     error
+}
+
+func (e *ConnectivityError) Error() string {
+	return fmt.Sprintf("bolt: connection to %s failed: %v", e.Address, e.Err)
+}
+
+func (e *ConnectivityError) Unwrap() error {
+	return e.Err
+}
+
+// ProtocolError is returned when the server sends an unexpected message
+type ProtocolError struct {
+	Message string
+}
+
+func (e *ProtocolError) Error() string {
+	return "bolt: protocol violation: " + e.Message
+}
+
+// IsTransient reports whether the provided error is a temporary failure
+// that may be worked around by retrying
+func IsTransient(err error) bool {
+	var failure *Error
+	if !errors.As(err, &failure) || failure.Classification() != "TransientError" {
+		return false
+	}
+	switch failure.code {
+	case "Neo.TransientError.Transaction.Terminated", "Neo.TransientError.Transaction.LockClientStopped":
+		return false
+	}
+	return true
+}
+
+// IsConnectivity reports whether the provided error is a connectivity failure
+func IsConnectivity(err error) bool {
+	var failure *ConnectivityError
+	return errors.As(err, &failure)
+}
@@ -0,0 +1,286 @@
+package bolt
+
+import (
+	"time"
+)
+
+// StatementType is the type of a statement, as reported by the server
+type StatementType int
+
+const (
+	StatementTypeUnknown     StatementType = 0
+	StatementTypeReadOnly    StatementType = 1
+	StatementTypeReadWrite   StatementType = 2
+	StatementTypeWriteOnly   StatementType = 3
+	StatementTypeSchemaWrite StatementType = 4
+)
+
+// Record is a row of a result
+type Record struct {
+	keys   []string
+	values []interface{}
+}
+
+// Keys returns the keys of the record
+func (r *Record) Keys() []string {
+	return r.keys
+}
+
+// Values returns the values of the record, in the order of its keys
+func (r *Record) Values() []interface{} {
+	return r.values
+}
+
+// Get returns the value of the provided key, if the record has it
+func (r *Record) Get(key string) (interface{}, bool) {
+	for i, k := range r.keys {
+		if k == key && i < len(r.values) {
+			return r.values[i], true
+		}
+	}
+	return nil, false
+}
+
+// GetByIndex returns the value at the provided index, or nil when out of range
+func (r *Record) GetByIndex(index int) interface{} {
+	if index < 0 || index >= len(r.values) {
+		return nil
+	}
+	return r.values[index]
+}
+
+// Counters are the statistics of the updates performed by a statement
+type Counters struct {
+	NodesCreated         int
+	NodesDeleted         int
+	RelationshipsCreated int
+	RelationshipsDeleted int
+	PropertiesSet        int
+	LabelsAdded          int
+	LabelsRemoved        int
+	IndexesAdded         int
+	IndexesRemoved       int
+	ConstraintsAdded     int
+	ConstraintsRemoved   int
+	SystemUpdates        int
+}
+
+// ContainsUpdates reports whether the statement updated the database
+func (c Counters) ContainsUpdates() bool {
+	return c != Counters{}
+}
+
+// ServerInfo describes the server a statement ran on
+type ServerInfo struct {
+	Address string
+	Agent   string
+	Version Version
+}
+
+// Summary describes the execution of a statement
+type Summary struct {
+	Statement            string
+	Params               map[string]interface{}
+	StatementType        StatementType
+	Counters             Counters
+	Server               ServerInfo
+	Bookmark             string
+	Database             string
+	ResultAvailableAfter time.Duration
+	ResultConsumedAfter  time.Duration
+}
+
+// Result is the stream of records of a statement. Records are read from the connection
+// as they are requested and buffered when another statement is run on the connection
+type Result struct {
+	conn      *conn
+	keys      []string
+	records   []*Record
+	current   *Record
+	summary   *Summary
+	err       error
+	streaming bool
+	done      func(meta map[string]interface{})
+}
+
+// run sends a statement along with a request for all of its
+// records, and reads the response to the statement
+func run(c *conn, cypher string, args map[string]interface{}, extra map[string]interface{}) (*Result, error) {
+	if err := c.send(msgRun, cypher, params(args), extra); err != nil {
+		return nil, err
+	}
+	if err := c.pull(); err != nil {
+		return nil, err
+	}
+	if err := c.flush(); err != nil {
+		return nil, err
+	}
+
+	meta, err := c.response()
+	if err != nil {
+		c.drain()
+		return nil, err
+	}
+
+	res := &Result{
+		conn:      c,
+		streaming: true,
+		summary: &Summary{
+			Statement: cypher,
+			Params:    args,
+			Server: ServerInfo{
+				Address: c.address,
+				Agent:   c.agent,
+				Version: c.version,
+			},
+			ResultAvailableAfter: millis(meta["t_first"]),
+		},
+	}
+	fields, _ := meta["fields"].([]interface{})
+	for _, field := range fields {
+		key, _ := field.(string)
+		res.keys = append(res.keys, key)
+	}
+	return res, nil
+}
+
+// Keys returns the keys of the records of the result
+func (r *Result) Keys() ([]string, error) {
+	return r.keys, nil
+}
+
+// Next advances to the next record, returning false when the
+// result is exhausted or failed
+func (r *Result) Next() bool {
+	if len(r.records) > 0 {
+		r.current = r.records[0]
+		r.records = r.records[1:]
+		return true
+	}
+	if !r.streaming {
+		r.current = nil
+		return false
+	}
+
+	r.current = r.fetch()
+	return r.current != nil
+}
+
+// Record returns the current record
+func (r *Result) Record() *Record {
+	return r.current
+}
+
+// Err returns the failure of the statement, if any
+func (r *Result) Err() error {
+	return r.err
+}
+
+// Summary buffers the remaining records and returns the summary of the statement
+func (r *Result) Summary() (*Summary, error) {
+	r.buffer()
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.summary, nil
+}
+
+// Consume discards the remaining records and returns the summary of the statement
+func (r *Result) Consume() (*Summary, error) {
+	for r.streaming {
+		r.fetch()
+	}
+	r.records = nil
+	r.current = nil
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.summary, nil
+}
+
+// buffer reads the remaining records of the result into memory
+func (r *Result) buffer() {
+	for r.streaming {
+		if rec := r.fetch(); rec != nil {
+			r.records = append(r.records, rec)
+		}
+	}
+}
+
+// fetch reads the next record from the connection, it returns nil
+// once the summary of the statement is received
+func (r *Result) fetch() *Record {
+	msg, err := r.conn.receive()
+	if err != nil {
+		r.finish(nil, err)
+		return nil
+	}
+	if msg.Tag == msgRecord {
+		var values []interface{}
+		if len(msg.Fields) > 0 {
+			values, _ = msg.Fields[0].([]interface{})
+		}
+		return &Record{keys: r.keys, values: values}
+	}
+
+	meta, err := r.conn.summary(msg)
+	r.finish(meta, err)
+	return nil
+}
+
+func (r *Result) finish(meta map[string]interface{}, err error) {
+	r.streaming = false
+	if err != nil {
+		r.err = err
+	} else {
+		r.summary.ResultConsumedAfter = millis(meta["t_last"])
+		r.summary.StatementType = statementType(meta["type"])
+		r.summary.Counters = counters(meta["stats"])
+		r.summary.Bookmark, _ = meta["bookmark"].(string)
+		r.summary.Database, _ = meta["db"].(string)
+	}
+	if r.done != nil {
+		r.done(meta)
+	}
+}
+
+func millis(v interface{}) time.Duration {
+	ms, _ := v.(int64)
+	return time.Duration(ms) * time.Millisecond
+}
+
+func statementType(v interface{}) StatementType {
+	switch v {
+	case "r":
+		return StatementTypeReadOnly
+	case "rw":
+		return StatementTypeReadWrite
+	case "w":
+		return StatementTypeWriteOnly
+	case "s":
+		return StatementTypeSchemaWrite
+	}
+	return StatementTypeUnknown
+}
+
+func counters(v interface{}) Counters {
+	stats, _ := v.(map[string]interface{})
+	count := func(key string) int {
+		n, _ := stats[key].(int64)
+		return int(n)
+	}
+	return Counters{
+		NodesCreated:         count("nodes-created"),
+		NodesDeleted:         count("nodes-deleted"),
+		RelationshipsCreated: count("relationships-created"),
+		RelationshipsDeleted: count("relationships-deleted"),
+		PropertiesSet:        count("properties-set"),
+		LabelsAdded:          count("labels-added"),
+		LabelsRemoved:        count("labels-removed"),
+		IndexesAdded:         count("indexes-added"),
+		IndexesRemoved:       count("indexes-removed"),
+		ConstraintsAdded:     count("constraints-added"),
+		ConstraintsRemoved:   count("constraints-removed"),
+		SystemUpdates:        count("system-updates"),
+	}
+}
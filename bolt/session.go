@@ -0,0 +1,328 @@
+package bolt
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// TransactionConfig holds the settings of a transaction
+type TransactionConfig struct {
+	// Timeout is the time after which the server terminates the transaction
+	Timeout time.Duration
+
+	// Metadata is attached to the transaction and visible in the query log and listings of the server
+	Metadata map[string]interface{}
+}
+
+// WithTxTimeout returns a configurer setting the timeout of a transaction
+func WithTxTimeout(timeout time.Duration) func(*TransactionConfig) {
+	return func(config *TransactionConfig) {
+		config.Timeout = timeout
+	}
+}
+
+// WithTxMetadata returns a configurer attaching metadata to a transaction
+func WithTxMetadata(metadata map[string]interface{}) func(*TransactionConfig) {
+	return func(config *TransactionConfig) {
+		config.Metadata = metadata
+	}
+}
+
+// TransactionWork is a unit of work run by the transaction functions of a session
+type TransactionWork func(tx *Transaction) (interface{}, error)
+
+// Session is a sequence of causally chained transactions. It is not safe for concurrent use
+type Session struct {
+	driver       *Driver
+	mode         AccessMode
+	bookmarks    []string
+	lastBookmark string
+
+	conn   *conn
+	result *Result
+	tx     *Transaction
+	closed bool
+}
+
+// LastBookmark returns the bookmark of the last transaction committed by the session
+func (s *Session) LastBookmark() string {
+	return s.lastBookmark
+}
+
+// Run runs an auto-commit statement. The records of the previous result of the session
+// are buffered, so they remain available, before the statement is sent
+func (s *Session) Run(cypher string, params map[string]interface{}, configurers ...func(*TransactionConfig)) (*Result, error) {
+	if s.closed {
+		return nil, ErrClosed
+	}
+	if s.tx != nil {
+		return nil, ErrOpenTransaction
+	}
+	s.settle()
+
+	c, err := s.acquire()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := run(c, cypher, params, s.extra(c, s.mode, configurers))
+	if err != nil {
+		s.release()
+		return nil, err
+	}
+	res.done = func(meta map[string]interface{}) {
+		s.bookmark(meta)
+		s.result = nil
+		s.release()
+	}
+	s.result = res
+	return res, nil
+}
+
+// BeginTransaction starts an explicit transaction with the access mode of the session
+func (s *Session) BeginTransaction(configurers ...func(*TransactionConfig)) (*Transaction, error) {
+	return s.begin(s.mode, configurers)
+}
+
+// ReadTransaction runs the unit of work in a read transaction, retrying it while it fails with
+// a transient error or the server can not be reached, for up to the maximum retry time of the driver
+func (s *Session) ReadTransaction(work TransactionWork, configurers ...func(*TransactionConfig)) (interface{}, error) {
+	return s.retry(AccessModeRead, work, configurers)
+}
+
+// WriteTransaction runs the unit of work in a write transaction, retrying it while it fails with
+// a transient error or the server can not be reached, for up to the maximum retry time of the driver
+func (s *Session) WriteTransaction(work TransactionWork, configurers ...func(*TransactionConfig)) (interface{}, error) {
+	return s.retry(AccessModeWrite, work, configurers)
+}
+
+// Close rolls back the open transaction, if any, discards the open result and closes the session
+func (s *Session) Close() error {
+	if s.closed {
+		return nil
+	}
+	if s.tx != nil {
+		s.tx.Close()
+	}
+	if s.result != nil {
+		s.result.Consume()
+	}
+	s.release()
+	s.closed = true
+	return nil
+}
+
+func (s *Session) retry(mode AccessMode, work TransactionWork, configurers []func(*TransactionConfig)) (interface{}, error) {
+	config := s.driver.config
+	deadline := time.Now().Add(config.MaxTransactionRetryTime)
+	delay := config.RetryDelay
+
+	for {
+		res, err := s.attempt(mode, work, configurers)
+		if err == nil {
+			return res, nil
+		}
+		if !IsTransient(err) && !IsConnectivity(err) || time.Now().After(deadline) {
+			return nil, err
+		}
+
+		// a jitter of 20% spreads the retries of concurrent sessions
+		jitter := time.Duration((rand.Float64()*0.4 - 0.2) * float64(delay))
+		time.Sleep(delay + jitter)
+		delay *= 2
+	}
+}
+
+func (s *Session) attempt(mode AccessMode, work TransactionWork, configurers []func(*TransactionConfig)) (interface{}, error) {
+	tx, err := s.begin(mode, configurers)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Close()
+
+	res, err := work(tx)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *Session) begin(mode AccessMode, configurers []func(*TransactionConfig)) (*Transaction, error) {
+	if s.closed {
+		return nil, ErrClosed
+	}
+	if s.tx != nil {
+		return nil, ErrOpenTransaction
+	}
+	s.settle()
+
+	c, err := s.acquire()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := c.request(msgBegin, s.extra(c, mode, configurers)); err != nil {
+		s.release()
+		return nil, err
+	}
+	s.tx = &Transaction{session: s, conn: c}
+	return s.tx, nil
+}
+
+// settle buffers the records of the open result, releasing its connection
+func (s *Session) settle() {
+	if s.result != nil {
+		s.result.buffer()
+	}
+}
+
+func (s *Session) acquire() (*conn, error) {
+	if s.conn != nil {
+		return s.conn, nil
+	}
+	c, err := s.driver.pool.acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	s.conn = c
+	return c, nil
+}
+
+func (s *Session) release() {
+	if s.conn == nil {
+		return
+	}
+	s.driver.pool.release(s.conn)
+	s.conn = nil
+}
+
+// bookmark records the bookmark returned by the server, the following
+// transactions of the session are chained to it
+func (s *Session) bookmark(meta map[string]interface{}) {
+	if bookmark, ok := meta["bookmark"].(string); ok && bookmark != "" {
+		s.lastBookmark = bookmark
+		s.bookmarks = []string{bookmark}
+	}
+}
+
+// extra returns the metadata of a RUN or BEGIN request
+func (s *Session) extra(c *conn, mode AccessMode, configurers []func(*TransactionConfig)) map[string]interface{} {
+	var config TransactionConfig
+	for _, configurer := range configurers {
+		configurer(&config)
+	}
+
+	extra := map[string]interface{}{}
+	if len(s.bookmarks) > 0 {
+		extra["bookmarks"] = s.bookmarks
+	}
+	if config.Timeout > 0 {
+		extra["tx_timeout"] = config.Timeout.Milliseconds()
+	}
+	if len(config.Metadata) > 0 {
+		extra["tx_metadata"] = params(config.Metadata)
+	}
+	if mode == AccessModeRead {
+		extra["mode"] = "r"
+	}
+	if db := s.driver.config.Database; db != "" && c.version.Major >= 4 {
+		extra["db"] = db
+	}
+	return extra
+}
+
+// Transaction is an explicit transaction
+type Transaction struct {
+	session *Session
+	conn    *conn
+	result  *Result
+	err     error
+	done    bool
+}
+
+// Run runs a statement within the transaction. The records of the previous result
+// of the transaction are buffered, so they remain available, before the statement is sent
+func (t *Transaction) Run(cypher string, params map[string]interface{}) (*Result, error) {
+	if t.done {
+		return nil, ErrClosed
+	}
+	if t.err != nil {
+		return nil, t.err
+	}
+	if t.result != nil {
+		t.result.buffer()
+	}
+
+	res, err := run(t.conn, cypher, params, map[string]interface{}{})
+	if err != nil {
+		t.err = err
+		return nil, err
+	}
+	res.done = func(map[string]interface{}) {
+		if err := res.Err(); err != nil && t.err == nil {
+			t.err = err
+		}
+		t.result = nil
+	}
+	t.result = res
+	return res, nil
+}
+
+// Commit commits the transaction. It returns the failure of the statements
+// of the transaction, if any, in which case the transaction is rolled back
+func (t *Transaction) Commit() error {
+	if t.done {
+		return ErrClosed
+	}
+	if res := t.result; res != nil {
+		res.buffer()
+		if err := res.Err(); err != nil && t.err == nil {
+			t.err = err
+		}
+	}
+	if t.err != nil {
+		t.finish()
+		return t.err
+	}
+
+	meta, err := t.conn.request(msgCommit)
+	if err == nil {
+		t.session.bookmark(meta)
+	}
+	t.finish()
+	return err
+}
+
+// Rollback rolls the transaction back
+func (t *Transaction) Rollback() error {
+	if t.done {
+		return ErrClosed
+	}
+	if t.result != nil {
+		t.result.Consume()
+	}
+
+	var err error
+	if t.err == nil && !t.conn.failed {
+		_, err = t.conn.request(msgRollback)
+	}
+	t.finish()
+	return err
+}
+
+// Close rolls the transaction back if it was not committed or rolled back
+func (t *Transaction) Close() error {
+	if t.done {
+		return nil
+	}
+	return t.Rollback()
+}
+
+func (t *Transaction) finish() {
+	t.done = true
+	t.session.tx = nil
+	t.session.release()
+}
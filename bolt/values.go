@@ -0,0 +1,44 @@
+package bolt
+
+import "github.com/syllabix/neox/packstream"
+
+// The values exchanged with the server are those of the packstream package
+type (
+	// Node is a node returned by the server
+	Node = packstream.Node
+
+	// Relationship is a relationship returned by the server
+	Relationship = packstream.Relationship
+
+	// Path is a path returned by the server
+	Path = packstream.Path
+
+	// Date is a date without a time zone
+	Date = packstream.Date
+
+	// Time is a time of day with a zone offset
+	Time = packstream.Time
+
+	// LocalTime is a time of day without a time zone
+	LocalTime = packstream.LocalTime
+
+	// LocalDateTime is a date and time without a time zone
+	LocalDateTime = packstream.LocalDateTime
+
+	// Duration is a temporal amount
+	Duration = packstream.Duration
+
+	// Point2D is a point in a two dimensional coordinate reference system
+	Point2D = packstream.Point2D
+
+	// Point3D is a point in a three dimensional coordinate reference system
+	Point3D = packstream.Point3D
+)
+
+// params returns the params of a statement, which must be sent as a map even when empty
+func params(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return map[string]interface{}{}
+	}
+	return m
+}
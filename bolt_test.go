@@ -0,0 +1,164 @@
+package neox
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+	"github.com/syllabix/neox/bolt"
+	"github.com/syllabix/neox/boltstub"
+)
+
+func TestBolt_Values(t *testing.T) {
+	t.Parallel()
+
+	instant := time.Date(2019, 7, 14, 13, 45, 2, 500, time.FixedZone("Offset", 3600))
+
+	tests := []struct {
+		name     string
+		neo4j    interface{}
+		bolt     interface{}
+		fromBolt bool
+	}{
+		{name: "date", neo4j: neo4j.DateOf(instant), bolt: bolt.Date(instant)},
+		{name: "local date time", neo4j: neo4j.LocalDateTimeOf(instant), bolt: bolt.LocalDateTime(instant)},
+		{name: "duration", neo4j: neo4j.DurationOf(1, 2, 3, 4), bolt: bolt.Duration{Months: 1, Days: 2, Seconds: 3, Nanos: 4}},
+		{name: "3d point", neo4j: neo4j.NewPoint3D(9157, 1, 2, 3), bolt: bolt.Point3D{SRID: 9157, X: 1, Y: 2, Z: 3}},
+		{name: "list", neo4j: []interface{}{neo4j.DurationOf(0, 1, 0, 0), "a"}, bolt: []interface{}{bolt.Duration{Days: 1}, "a"}},
+		{name: "map", neo4j: map[string]interface{}{"d": neo4j.DateOf(instant)}, bolt: map[string]interface{}{"d": bolt.Date(instant)}, fromBolt: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fromBolt(tt.bolt); !reflect.DeepEqual(got, tt.neo4j) {
+				t.Errorf("fromBolt() got = %#v, want %#v", got, tt.neo4j)
+			}
+			if tt.fromBolt {
+				return
+			}
+			if got := fromBolt(toBolt(tt.neo4j)); !reflect.DeepEqual(got, tt.neo4j) {
+				t.Errorf("toBolt() did not round trip, got = %#v, want %#v", got, tt.neo4j)
+			}
+		})
+	}
+
+	t.Run("Should convert 2d points", func(t *testing.T) {
+		p := toBolt(neo4j.NewPoint2D(7203, 1, 2))
+		if p != (bolt.Point2D{SRID: 7203, X: 1, Y: 2}) {
+			t.Errorf("toBolt() got = %#v", p)
+		}
+	})
+
+	t.Run("Should convert graph values", func(t *testing.T) {
+		yolanda := &bolt.Node{ID: 1, Labels: []string{"User"}, Props: map[string]interface{}{"born": bolt.Date(instant)}}
+		jordan := &bolt.Node{ID: 2, Labels: []string{"User"}}
+		follows := &bolt.Relationship{ID: 3, StartID: 1, EndID: 2, Type: "FOLLOWS"}
+
+		p, ok := fromBolt(&bolt.Path{Nodes: []*bolt.Node{yolanda, jordan}, Relationships: []*bolt.Relationship{follows}}).(neo4j.Path)
+		if !ok {
+			t.Fatalf("fromBolt() did not return a neo4j.Path")
+		}
+		if len(p.Nodes()) != 2 || p.Nodes()[0].Props()["born"] != neo4j.DateOf(instant) {
+			t.Errorf("fromBolt() nodes = %v", p.Nodes())
+		}
+		if r := p.Relationships()[0]; r.Type() != "FOLLOWS" || r.StartId() != 1 || r.EndId() != 2 {
+			t.Errorf("fromBolt() relationship = %v", r)
+		}
+	})
+}
+
+func TestNewBoltDriver(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewBoltDriver("http://localhost", bolt.NoAuth()); err == nil {
+		t.Errorf("NewBoltDriver() error = nil, want an error for unsupported schemes")
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	driver, err := NewBoltDriver("bolt://"+addr, bolt.NoAuth())
+	if err != nil {
+		t.Fatalf("NewBoltDriver() error = %v", err)
+	}
+	defer driver.Close()
+
+	session, err := driver.Sessionx(neo4j.AccessModeRead)
+	if err != nil {
+		t.Fatalf("Driver.Sessionx() error = %v", err)
+	}
+	defer session.Close()
+
+	if _, err := session.Runx("return 1", nil); !errors.Is(err, ErrServiceUnavailable) {
+		t.Errorf("Session.Runx() error = %v, want %v", err, ErrServiceUnavailable)
+	}
+}
+
+func TestNewBoltDriver_Stub(t *testing.T) {
+	t.Parallel()
+
+	server, err := boltstub.NewServer(boltstub.MustParse(`
+		!: BOLT 4.4
+		!: AUTO HELLO
+		!: AUTO RESET
+
+		C: BEGIN {}
+		S: SUCCESS {}
+		C: RUN "merge (u:User {id: $id}) return u.name as user_name" {"id": 1} {}
+		   PULL {"n": -1}
+		S: FAILURE {"code": "Neo.TransientError.Transaction.DeadlockDetected", "message": "deadlock"}
+		   IGNORED
+		C: BEGIN {}
+		S: SUCCESS {}
+		C: RUN "merge (u:User {id: $id}) return u.name as user_name" {"id": 1} {}
+		   PULL {"n": -1}
+		S: SUCCESS {"fields": ["user_name"]}
+		   RECORD ["Yolanda Erasmus"]
+		   SUCCESS {}
+		C: COMMIT
+		S: SUCCESS {"bookmark": "bookmark:1"}
+	`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	driver, err := NewBoltDriver(server.URL(), bolt.NoAuth())
+	if err != nil {
+		t.Fatalf("NewBoltDriver() error = %v", err)
+	}
+	session, _ := driver.Sessionx(neo4j.AccessModeWrite)
+
+	name, err := session.WriteTransactionx(func(tx *Transaction) (interface{}, error) {
+		res, err := tx.Runx("merge (u:User {id: $id}) return u.name as user_name", Args{"id": 1})
+		if err != nil {
+			return nil, err
+		}
+		var u struct {
+			Name string `db:"user_name"`
+		}
+		for res.Next() {
+			if err := res.ToStruct(&u); err != nil {
+				return nil, err
+			}
+		}
+		return u.Name, res.Err()
+	})
+	if err != nil || name != "Yolanda Erasmus" {
+		t.Errorf("Session.WriteTransactionx() = %v, %v, want the deadlock to be retried", name, err)
+	}
+	if session.LastBookmark() != "bookmark:1" {
+		t.Errorf("Session.LastBookmark() = %q", session.LastBookmark())
+	}
+
+	session.Close()
+	driver.Close()
+	if err := server.Close(); err != nil {
+		t.Errorf("Server.Close() error = %v", err)
+	}
+}
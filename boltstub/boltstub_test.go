@@ -0,0 +1,223 @@
+package boltstub
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/syllabix/neox/bolt"
+)
+
+func start(t *testing.T, scripts ...*Script) (*Server, *bolt.Driver) {
+	t.Helper()
+	server, err := NewServer(scripts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	driver, err := bolt.NewDriver(server.URL(), bolt.NoAuth(), func(c *bolt.Config) {
+		c.MaxTransactionRetryTime = 0
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { driver.Close() })
+	return server, driver
+}
+
+func TestServer_Run(t *testing.T) {
+	t.Parallel()
+
+	server, driver := start(t, MustParse(`
+		!: BOLT 4.4
+		!: AUTO HELLO
+		!: AUTO RESET
+
+		C: RUN "match (u:User) return u.name as name" {} {}
+		   PULL {"n": -1}
+		S: SUCCESS {"fields": ["name"]}
+		   RECORD ["Yolanda Erasmus"]
+		   RECORD ["Jordan Ames"]
+		   SUCCESS {"type": "r"}
+		C: RUN "create (u:User {id: $id})" {"id": "*"} {}
+		   PULL *
+		S: FAILURE {"code": "Neo.ClientError.Schema.ConstraintValidationFailed", "message": "already exists"}
+		   IGNORED
+	`))
+
+	session, err := driver.Session(bolt.AccessModeWrite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	res, err := session.Run("match (u:User) return u.name as name", nil)
+	if err != nil {
+		t.Fatalf("Session.Run() error = %v", err)
+	}
+	var names []string
+	for res.Next() {
+		name, _ := res.Record().Get("name")
+		names = append(names, name.(string))
+	}
+	if len(names) != 2 || names[0] != "Yolanda Erasmus" || res.Err() != nil {
+		t.Errorf("Result records = %v, %v", names, res.Err())
+	}
+
+	_, err = session.Run("create (u:User {id: $id})", map[string]interface{}{"id": 7})
+	var failure *bolt.Error
+	if !errors.As(err, &failure) || failure.Code() != "Neo.ClientError.Schema.ConstraintValidationFailed" {
+		t.Errorf("Session.Run() error = %v, want the scripted failure", err)
+	}
+
+	session.Close()
+	driver.Close()
+	if err := server.Close(); err != nil {
+		t.Errorf("Server.Close() error = %v", err)
+	}
+}
+
+func TestServer_Disconnect(t *testing.T) {
+	t.Parallel()
+
+	server, driver := start(t,
+		MustParse(`
+			!: AUTO HELLO
+			C: BEGIN *
+			S: <EXIT>
+		`),
+		MustParse(`
+			!: AUTO HELLO
+			!: AUTO RESET
+			!: ALLOW RESTART
+			C: BEGIN {}
+			S: SUCCESS {}
+			C: RUN "merge (u:User {id: 1})" {} {}
+			   PULL {"n": -1}
+			S: SUCCESS {"fields": []}
+			   SUCCESS {}
+			C: COMMIT
+			S: SUCCESS {"bookmark": "bookmark:2"}
+		`),
+	)
+
+	session, _ := driver.Session(bolt.AccessModeWrite)
+	_, err := session.WriteTransaction(func(tx *bolt.Transaction) (interface{}, error) {
+		return tx.Run("merge (u:User {id: 1})", nil)
+	})
+	if !bolt.IsConnectivity(err) {
+		t.Fatalf("Session.WriteTransaction() error = %v, want a connectivity error", err)
+	}
+
+	_, err = session.WriteTransaction(func(tx *bolt.Transaction) (interface{}, error) {
+		return tx.Run("merge (u:User {id: 1})", nil)
+	})
+	if err != nil || session.LastBookmark() != "bookmark:2" {
+		t.Errorf("Session.WriteTransaction() = %q, %v", session.LastBookmark(), err)
+	}
+
+	session.Close()
+	driver.Close()
+	if err := server.Close(); err != nil {
+		t.Errorf("Server.Close() error = %v", err)
+	}
+}
+
+func TestServer_Load(t *testing.T) {
+	t.Parallel()
+
+	script, err := Load("testdata/read.script")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, driver := start(t, script)
+
+	session, _ := driver.Session(bolt.AccessModeRead)
+	name, err := session.ReadTransaction(func(tx *bolt.Transaction) (interface{}, error) {
+		res, err := tx.Run("match (u:User) return u.name as name", nil)
+		if err != nil || !res.Next() {
+			return nil, err
+		}
+		return res.Record().GetByIndex(0), nil
+	})
+	if err != nil || name != "Yolanda Erasmus" {
+		t.Errorf("Session.ReadTransaction() = %v, %v", name, err)
+	}
+
+	version, _, err := driver.ServerVersion()
+	if err != nil || version.Major != 3 {
+		t.Errorf("Driver.ServerVersion() = %v, %v", version, err)
+	}
+
+	session.Close()
+	driver.Close()
+	if err := server.Close(); err != nil {
+		t.Errorf("Server.Close() error = %v", err)
+	}
+}
+
+func TestServer_Errors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should report unexpected messages", func(t *testing.T) {
+		server, driver := start(t, MustParse(`
+			!: AUTO HELLO
+			C: RUN "return 1" {} {}
+			   PULL {"n": -1}
+			S: SUCCESS {"fields": []}
+			   SUCCESS {}
+		`))
+		session, _ := driver.Session(bolt.AccessModeWrite)
+		session.Run("return 2", nil)
+		session.Close()
+
+		var mismatch *MismatchError
+		if err := server.Close(); !errors.As(err, &mismatch) || mismatch.Line != 3 || !strings.Contains(mismatch.Got, `"return 2"`) {
+			t.Errorf("Server.Close() error = %v, want a mismatch on line 3", err)
+		}
+	})
+
+	t.Run("Should report scripts not played to the end", func(t *testing.T) {
+		server, _ := start(t, MustParse(`
+			C: HELLO *
+			S: SUCCESS {}
+		`))
+		if err := server.Close(); !errors.Is(err, ErrIncomplete) {
+			t.Errorf("Server.Close() error = %v, want %v", err, ErrIncomplete)
+		}
+	})
+
+	t.Run("Should report unexpected connections", func(t *testing.T) {
+		server, driver := start(t)
+		if err := driver.VerifyConnectivity(); err == nil {
+			t.Errorf("Driver.VerifyConnectivity() error = nil, want an error")
+		}
+		if err := server.Close(); !errors.Is(err, ErrUnexpectedConnection) {
+			t.Errorf("Server.Close() error = %v, want %v", err, ErrUnexpectedConnection)
+		}
+	})
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		script string
+		want   string
+	}{
+		{"Should reject unknown messages", "C: FETCH {}", `line 1: unknown message "FETCH"`},
+		{"Should reject invalid fields", "C: RUN {", "line 1: invalid fields of RUN"},
+		{"Should reject unknown directives", "!: ROUTING", `line 1: unknown directive "ROUTING"`},
+		{"Should reject invalid versions", "!: BOLT x", `line 1: invalid bolt version "x"`},
+		{"Should reject orphan lines", "\n   PULL {}", "line 2: expected a directive"},
+		{"Should reject unknown actions", "S: <PAUSE>", `line 1: unknown action "<PAUSE>"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.script)
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("Parse() error = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+// Package boltstub provides a Bolt server playing scripted conversations, to test
+// connection handling, failures and retries without a running neo4j server.
+//
+// Scripts follow the format of the stub scripts of the official drivers, a server
+// playing them listens on a local port and reports the messages the client sent
+// that the scripts did not expect
+//
+//	server, err := boltstub.NewServer(boltstub.MustParse(`
+//		!: BOLT 4.4
+//		!: AUTO HELLO
+//		!: AUTO RESET
+//
+//		C: RUN "return 1 as n" {} {}
+//		   PULL {"n": -1}
+//		S: SUCCESS {"fields": ["n"]}
+//		   RECORD [1]
+//		   SUCCESS {}
+//	`))
+//	...
+//	driver, err := neox.NewBoltDriver(server.URL(), bolt.NoAuth())
+//	...
+//	if err := server.Close(); err != nil {
+//		t.Error(err)
+//	}
+package boltstub
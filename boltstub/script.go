@@ -0,0 +1,397 @@
+package boltstub
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// message tags of the Bolt protocol, by name
+var tags = map[string]byte{
+	"INIT":        0x01,
+	"HELLO":       0x01,
+	"GOODBYE":     0x02,
+	"ACK_FAILURE": 0x0E,
+	"RESET":       0x0F,
+	"RUN":         0x10,
+	"BEGIN":       0x11,
+	"COMMIT":      0x12,
+	"ROLLBACK":    0x13,
+	"DISCARD_ALL": 0x2F,
+	"DISCARD":     0x2F,
+	"PULL_ALL":    0x3F,
+	"PULL":        0x3F,
+	"ROUTE":       0x66,
+	"SUCCESS":     0x70,
+	"RECORD":      0x71,
+	"IGNORED":     0x7E,
+	"FAILURE":     0x7F,
+}
+
+const (
+	tagHello   = 0x01
+	tagGoodbye = 0x02
+	tagSuccess = 0x70
+)
+
+// name returns the name of a message tag, preferring the Bolt 3 and later names
+func name(tag byte) string {
+	switch tag {
+	case 0x01:
+		return "HELLO"
+	case 0x2F:
+		return "DISCARD"
+	case 0x3F:
+		return "PULL"
+	}
+	for name, t := range tags {
+		if t == tag {
+			return name
+		}
+	}
+	return fmt.Sprintf("0x%02X", tag)
+}
+
+// actions a server line can take in place of sending a message
+const (
+	actionSend = iota
+	actionExit
+	actionSleep
+	actionRaw
+)
+
+// Script is a parsed Bolt conversation, played by a Server for a single connection.
+//
+// A script is made of directives, client lines starting with C: and server lines starting
+// with S:. Lines indented under a client or server line belong to it, blank lines and lines
+// starting with # are ignored
+//
+//	!: BOLT 4.4
+//	!: AUTO HELLO
+//	!: AUTO RESET
+//
+//	C: RUN "match (u:User) return u.name as name" {} {}
+//	   PULL {"n": -1}
+//	S: SUCCESS {"fields": ["name"]}
+//	   RECORD ["Yolanda Erasmus"]
+//	   SUCCESS {}
+//	C: RUN "match (n) return n" {} {}
+//	S: FAILURE {"code": "Neo.TransientError.Transaction.DeadlockDetected", "message": "deadlock"}
+//	C: PULL {"n": -1}
+//	S: IGNORED
+//	S: <EXIT>
+//
+// Messages are written as their name followed by their fields as JSON values. A client
+// message with a single * in place of its fields matches any fields, and a "*" string matches
+// any value. Integers and floats match each other when they are equal.
+//
+// Server lines may also be one of the following actions
+//
+//	<EXIT>         closes the connection
+//	<SLEEP 50ms>   pauses before the next line, the duration is parsed by time.ParseDuration
+//	<RAW> 00 02 B0 7E 00 00
+//	               writes the hex encoded bytes as is
+//
+// The supported directives are
+//
+//	!: BOLT 4.4         the protocol version agreed on during the handshake, 4.4 by default
+//	!: AUTO RESET       answers the named message with SUCCESS {} wherever the client sends it,
+//	                    a HELLO is answered with the server agent and a connection id
+//	!: ALLOW RESTART    plays the script again for each subsequent connection
+//
+// A GOODBYE is always accepted and ends the conversation
+type Script struct {
+	// Name identifies the script in errors, it is the path of scripts loaded from a file
+	Name string
+
+	version [2]int
+	auto    map[byte]bool
+	restart bool
+	lines   []line
+}
+
+// line is a client message expected from the client, or a server action
+type line struct {
+	number int
+	client bool
+	text   string
+
+	tag      byte
+	fields   []interface{}
+	wildcard bool
+
+	action int
+	sleep  time.Duration
+	raw    []byte
+}
+
+// Parse parses a script
+func Parse(script string) (*Script, error) {
+	return ReadScript(strings.NewReader(script))
+}
+
+// Load reads a script from the file at the provided path
+func Load(path string) (*Script, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	s, err := ReadScript(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	s.Name = path
+	return s, nil
+}
+
+// MustParse is like Parse but panics if the script can not be parsed. It simplifies
+// declaring scripts in tests
+func MustParse(script string) *Script {
+	s, err := Parse(script)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// maxLine is the maximum length of a script line
+const maxLine = 16 << 20
+
+// ReadScript parses a script from the provided reader
+func ReadScript(r io.Reader) (*Script, error) {
+	s := &Script{
+		Name:    "script",
+		version: [2]int{4, 4},
+		auto:    make(map[byte]bool),
+	}
+
+	var (
+		scanner = bufio.NewScanner(r)
+		number  = 0
+		client  *bool
+	)
+	// lines may hold large values, such as strings spanning several chunks
+	scanner.Buffer(nil, maxLine)
+	for scanner.Scan() {
+		number++
+		raw := scanner.Text()
+		text := strings.TrimSpace(raw)
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(text, "!:"):
+			client = nil
+			if err := s.directive(strings.TrimSpace(text[2:])); err != nil {
+				return nil, fmt.Errorf("line %d: %w", number, err)
+			}
+			continue
+		case strings.HasPrefix(text, "C:"):
+			client = new(bool)
+			*client = true
+			text = strings.TrimSpace(text[2:])
+		case strings.HasPrefix(text, "S:"):
+			client = new(bool)
+			text = strings.TrimSpace(text[2:])
+		case raw[0] != ' ' && raw[0] != '\t' || client == nil:
+			return nil, fmt.Errorf("line %d: expected a directive, a C: or an S: line, got %q", number, text)
+		}
+
+		l, err := parseLine(text, *client)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", number, err)
+		}
+		l.number = number
+		s.lines = append(s.lines, l)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Script) directive(text string) error {
+	fields := strings.Fields(text)
+	switch {
+	case len(fields) == 2 && fields[0] == "BOLT":
+		major, minor, err := parseVersion(fields[1])
+		if err != nil {
+			return err
+		}
+		s.version = [2]int{major, minor}
+	case len(fields) == 2 && fields[0] == "AUTO":
+		tag, ok := tags[fields[1]]
+		if !ok {
+			return fmt.Errorf("unknown message %q", fields[1])
+		}
+		s.auto[tag] = true
+	case len(fields) == 2 && fields[0] == "ALLOW" && fields[1] == "RESTART":
+		s.restart = true
+	default:
+		return fmt.Errorf("unknown directive %q", text)
+	}
+	return nil
+}
+
+func parseVersion(v string) (major, minor int, err error) {
+	parts := strings.SplitN(v, ".", 2)
+	major, err = strconv.Atoi(parts[0])
+	if err == nil && len(parts) == 2 {
+		minor, err = strconv.Atoi(parts[1])
+	}
+	if err != nil || major < 0 || major > 0xFF || minor < 0 || minor > 0xFF {
+		return 0, 0, fmt.Errorf("invalid bolt version %q", v)
+	}
+	return major, minor, nil
+}
+
+func parseLine(text string, client bool) (line, error) {
+	l := line{client: client, text: text}
+
+	if !client && strings.HasPrefix(text, "<") {
+		end := strings.Index(text, ">")
+		if end < 0 {
+			return l, fmt.Errorf("unterminated action %q", text)
+		}
+		action := strings.Fields(text[1:end])
+		arg := strings.TrimSpace(text[end+1:])
+		switch {
+		case len(action) == 1 && action[0] == "EXIT" && arg == "":
+			l.action = actionExit
+		case len(action) == 2 && action[0] == "SLEEP" && arg == "":
+			d, err := time.ParseDuration(action[1])
+			if err != nil {
+				return l, err
+			}
+			l.action, l.sleep = actionSleep, d
+		case len(action) == 1 && action[0] == "RAW":
+			for _, b := range strings.Fields(arg) {
+				n, err := strconv.ParseUint(b, 16, 8)
+				if err != nil {
+					return l, fmt.Errorf("invalid raw byte %q", b)
+				}
+				l.raw = append(l.raw, byte(n))
+			}
+			l.action = actionRaw
+		default:
+			return l, fmt.Errorf("unknown action %q", text)
+		}
+		return l, nil
+	}
+
+	name, rest := text, ""
+	if i := strings.IndexAny(text, " \t"); i >= 0 {
+		name, rest = text[:i], strings.TrimSpace(text[i:])
+	}
+	tag, ok := tags[name]
+	if !ok {
+		return l, fmt.Errorf("unknown message %q", name)
+	}
+	l.tag = tag
+
+	if client && rest == "*" {
+		l.wildcard = true
+		return l, nil
+	}
+
+	dec := json.NewDecoder(strings.NewReader(rest))
+	dec.UseNumber()
+	for dec.More() {
+		var field interface{}
+		if err := dec.Decode(&field); err != nil {
+			return l, fmt.Errorf("invalid fields of %s: %w", name, err)
+		}
+		field, err := normalize(field)
+		if err != nil {
+			return l, err
+		}
+		l.fields = append(l.fields, field)
+	}
+	return l, nil
+}
+
+// normalize replaces the json numbers of a decoded value with an int64 or a float64
+func normalize(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, nil
+		}
+		return v.Float64()
+	case []interface{}:
+		for i := range v {
+			n, err := normalize(v[i])
+			if err != nil {
+				return nil, err
+			}
+			v[i] = n
+		}
+	case map[string]interface{}:
+		for k := range v {
+			n, err := normalize(v[k])
+			if err != nil {
+				return nil, err
+			}
+			v[k] = n
+		}
+	}
+	return v, nil
+}
+
+// match reports whether a received value matches the one expected by a script
+func match(want, got interface{}) bool {
+	switch want := want.(type) {
+	case string:
+		if want == "*" {
+			return true
+		}
+	case int64:
+		switch got := got.(type) {
+		case int64:
+			return got == want
+		case float64:
+			return got == float64(want)
+		}
+		return false
+	case float64:
+		switch got := got.(type) {
+		case int64:
+			return float64(got) == want
+		case float64:
+			return got == want
+		}
+		return false
+	case []interface{}:
+		list, ok := got.([]interface{})
+		if !ok || len(list) != len(want) {
+			return false
+		}
+		for i := range want {
+			if !match(want[i], list[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		m, ok := got.(map[string]interface{})
+		if !ok || len(m) != len(want) {
+			return false
+		}
+		for k, v := range want {
+			value, ok := m[k]
+			if !ok || !match(v, value) {
+				return false
+			}
+		}
+		return true
+	}
+	return want == got
+}
@@ -0,0 +1,366 @@
+package boltstub
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/syllabix/neox/packstream"
+)
+
+var (
+	// ErrIncomplete is matched by the errors of scripts the client disconnected from,
+	// or never connected for, before reaching their end
+	ErrIncomplete = errors.New("the script was not played to the end")
+
+	// ErrUnexpectedConnection is reported when a client connects once every script was played
+	ErrUnexpectedConnection = errors.New("no script left to play for a new connection")
+)
+
+// preamble identifies the Bolt protocol at the start of the handshake
+var preamble = []byte{0x60, 0x60, 0xB0, 0x17}
+
+// MismatchError is reported when the client sends a message the script does not expect
+type MismatchError struct {
+	// Script is the name of the script
+	Script string
+
+	// Line is the line of the script expecting another message
+	Line int
+
+	// Want is the expected message as written in the script
+	Want string
+
+	// Got is the received message
+	Got string
+}
+
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf("%s:%d: expected %s, got %s", e.Script, e.Line, e.Want, e.Got)
+}
+
+// Server is a Bolt server listening on a local port, playing a script for each
+// connection it accepts. It is meant to test clients without a running neo4j server
+type Server struct {
+	ln      net.Listener
+	scripts []*Script
+	wg      sync.WaitGroup
+
+	mu     sync.Mutex
+	next   int
+	conns  map[net.Conn]bool
+	errs   []error
+	closed bool
+}
+
+// NewServer starts a server listening on a random local port. The scripts are played in
+// order, one for each connection accepted. Once every script was played, the last script
+// is played again for each new connection if it allows restarts
+func NewServer(scripts ...*Script) (*Server, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		ln:      ln,
+		scripts: scripts,
+		conns:   make(map[net.Conn]bool),
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Addr returns the address the server listens on
+func (s *Server) Addr() string {
+	return s.ln.Addr().String()
+}
+
+// URL returns the bolt url of the server
+func (s *Server) URL() string {
+	return "bolt://" + s.Addr()
+}
+
+// Err returns the failures of the conversations that ended so far
+func (s *Server) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return errors.Join(s.errs...)
+}
+
+// Close stops the server and closes its open connections. It returns the failures of
+// the conversations, including those of the scripts that were not played to the end
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	for nc := range s.conns {
+		nc.Close()
+	}
+	s.mu.Unlock()
+
+	s.ln.Close()
+	s.wg.Wait()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, script := range s.scripts[s.next:] {
+		s.errs = append(s.errs, fmt.Errorf("%s: %w", script.Name, ErrIncomplete))
+	}
+	s.next = len(s.scripts)
+	return errors.Join(s.errs...)
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		nc, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			nc.Close()
+			return
+		}
+		script := s.script()
+		if script == nil {
+			s.errs = append(s.errs, ErrUnexpectedConnection)
+			s.mu.Unlock()
+			nc.Close()
+			continue
+		}
+		s.conns[nc] = true
+		s.wg.Add(1)
+		s.mu.Unlock()
+
+		go func() {
+			defer s.wg.Done()
+			err := s.play(nc, script)
+			nc.Close()
+
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			delete(s.conns, nc)
+			if err != nil {
+				s.errs = append(s.errs, err)
+			}
+		}()
+	}
+}
+
+// script returns the script to play for a new connection
+func (s *Server) script() *Script {
+	if s.next < len(s.scripts) {
+		s.next++
+		return s.scripts[s.next-1]
+	}
+	if n := len(s.scripts); n > 0 && s.scripts[n-1].restart {
+		return s.scripts[n-1]
+	}
+	return nil
+}
+
+// conversation is a script played on a connection
+type conversation struct {
+	script *Script
+	nc     net.Conn
+	rd     *bufio.Reader
+	pos    int
+}
+
+func (s *Server) play(nc net.Conn, script *Script) error {
+	c := &conversation{script: script, nc: nc, rd: bufio.NewReader(nc)}
+	if err := c.handshake(); err != nil {
+		return err
+	}
+
+	for c.pos < len(script.lines) {
+		l := script.lines[c.pos]
+		if l.client {
+			if err := c.expect(l); err != nil {
+				return err
+			}
+			c.pos++
+			continue
+		}
+
+		switch l.action {
+		case actionExit:
+			return nil
+		case actionSleep:
+			time.Sleep(l.sleep)
+		case actionRaw:
+			if _, err := nc.Write(l.raw); err != nil {
+				return c.incomplete()
+			}
+		default:
+			if err := c.send(packstream.Structure{Tag: l.tag, Fields: l.fields}); err != nil {
+				return c.incomplete()
+			}
+		}
+		c.pos++
+	}
+
+	// the script is over, the client may still reset the connection or say goodbye
+	for {
+		msg, err := c.receive()
+		if err != nil || msg.Tag == tagGoodbye {
+			return nil
+		}
+		if !script.auto[msg.Tag] {
+			return &MismatchError{Script: script.Name, Line: c.line(), Want: "the end of the script", Got: describe(msg)}
+		}
+		if err := c.answer(msg); err != nil {
+			return nil
+		}
+	}
+}
+
+func (c *conversation) handshake() error {
+	var handshake [20]byte
+	if _, err := io.ReadFull(c.rd, handshake[:]); err != nil {
+		return c.incomplete()
+	}
+	if !bytes.Equal(handshake[:4], preamble) {
+		return fmt.Errorf("%s: invalid handshake preamble % X", c.script.Name, handshake[:4])
+	}
+
+	major, minor := c.script.version[0], c.script.version[1]
+	for i := 4; i < len(handshake); i += 4 {
+		proposal := handshake[i : i+4]
+		top, span := int(proposal[2]), int(proposal[1])
+		if int(proposal[3]) == major && minor <= top && minor >= top-span {
+			if _, err := c.nc.Write([]byte{0, 0, byte(minor), byte(major)}); err != nil {
+				return c.incomplete()
+			}
+			return nil
+		}
+	}
+
+	c.nc.Write([]byte{0, 0, 0, 0})
+	return fmt.Errorf("%s: the client did not propose bolt %d.%d", c.script.Name, major, minor)
+}
+
+// expect reads the next message the script expects, answering the messages sent
+// automatically in between
+func (c *conversation) expect(l line) error {
+	for {
+		msg, err := c.receive()
+		if err != nil {
+			return c.incomplete()
+		}
+		if msg.Tag == l.tag && (l.wildcard || match(l.fields, msg.Fields)) {
+			return nil
+		}
+		if msg.Tag == tagGoodbye {
+			return c.incomplete()
+		}
+		if !c.script.auto[msg.Tag] {
+			return &MismatchError{Script: c.script.Name, Line: l.number, Want: l.text, Got: describe(msg)}
+		}
+		if err := c.answer(msg); err != nil {
+			return c.incomplete()
+		}
+	}
+}
+
+// answer replies to a message sent automatically
+func (c *conversation) answer(msg packstream.Structure) error {
+	meta := map[string]interface{}{}
+	if msg.Tag == tagHello {
+		meta["server"] = fmt.Sprintf("Neo4j/%d.%d.0", c.script.version[0], c.script.version[1])
+		meta["connection_id"] = "bolt-stub"
+	}
+	return c.send(packstream.Structure{Tag: tagSuccess, Fields: []interface{}{meta}})
+}
+
+func (c *conversation) receive() (packstream.Structure, error) {
+	var msg []byte
+	for {
+		var header [2]byte
+		if _, err := io.ReadFull(c.rd, header[:]); err != nil {
+			return packstream.Structure{}, err
+		}
+		size := binary.BigEndian.Uint16(header[:])
+		if size == 0 {
+			if len(msg) == 0 {
+				// a noop chunk between messages
+				continue
+			}
+			break
+		}
+		chunk := make([]byte, size)
+		if _, err := io.ReadFull(c.rd, chunk); err != nil {
+			return packstream.Structure{}, err
+		}
+		msg = append(msg, chunk...)
+	}
+
+	var req packstream.Structure
+	if err := packstream.Unmarshal(msg, &req); err != nil {
+		return packstream.Structure{}, err
+	}
+	if req.Fields == nil {
+		req.Fields = []interface{}{}
+	}
+	return req, nil
+}
+
+func (c *conversation) send(msg packstream.Structure) error {
+	b, err := packstream.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	var out []byte
+	for len(b) > 0 {
+		n := len(b)
+		if n > 0xFFFF {
+			n = 0xFFFF
+		}
+		out = append(out, byte(n>>8), byte(n))
+		out = append(out, b[:n]...)
+		b = b[n:]
+	}
+	out = append(out, 0, 0)
+	_, err = c.nc.Write(out)
+	return err
+}
+
+// line returns the line of the script the conversation is at
+func (c *conversation) line() int {
+	if c.pos < len(c.script.lines) {
+		return c.script.lines[c.pos].number
+	}
+	if n := len(c.script.lines); n > 0 {
+		return c.script.lines[n-1].number
+	}
+	return 0
+}
+
+func (c *conversation) incomplete() error {
+	return fmt.Errorf("%s:%d: %w", c.script.Name, c.line(), ErrIncomplete)
+}
+
+// describe renders a received message the way it would be written in a script
+func describe(msg packstream.Structure) string {
+	parts := []string{name(msg.Tag)}
+	for _, field := range msg.Fields {
+		b, err := json.Marshal(field)
+		if err != nil {
+			parts = append(parts, fmt.Sprint(field))
+			continue
+		}
+		parts = append(parts, string(b))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,18 @@
+// Package bulk provides a pipeline importing large amounts of items into neo4j. Items are
+// read from a Source, grouped in batches and written by concurrent workers, each holding
+// its own session of a neox.Driver, with a backoff on transient failures
+//
+//	im := bulk.New(driver, bulk.Config{
+//		Cypher: `
+//			unwind $batch as row
+//			merge (u:User {id: row.id})
+//			set u.name = row.name`,
+//		BatchSize: 1000,
+//		Workers:   4,
+//		Progress: func(p bulk.Progress) {
+//			log.Printf("batch %d: %d nodes created, %d items written", p.Batch, p.Counters.NodesCreated, p.Written)
+//		},
+//	})
+//
+//	report, err := im.Run(ctx, bulk.FromChannel(users))
+package bulk
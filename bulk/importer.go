@@ -0,0 +1,300 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+	"github.com/syllabix/neox"
+)
+
+// defaults of a Config
+const (
+	DefaultBatchSize  = 1000
+	DefaultWorkers    = 4
+	DefaultMaxRetries = 5
+	DefaultBackoff    = 100 * time.Millisecond
+	DefaultMaxBackoff = 5 * time.Second
+)
+
+// Session is the part of a neox.Session used to write batches, it allows
+// importers to be tested with a fake session
+type Session interface {
+	BatchWriteContext(ctx context.Context, cypher string, items interface{}, batchSize int) (neox.Counters, error)
+	Close() error
+}
+
+// Config configures an Importer, zero values are replaced by their defaults
+type Config struct {
+	// Cypher is the statement writing a batch, passed as the $batch list parameter
+	Cypher string
+
+	// BatchSize is the number of items written per transaction
+	BatchSize int
+
+	// Workers is the number of batches written concurrently, each worker holding a session
+	Workers int
+
+	// MaxRetries is the number of times a batch failing with a transient error is retried,
+	// on top of the retries of the driver. It is DefaultMaxRetries when zero, a negative
+	// value disables retries
+	MaxRetries int
+
+	// Backoff is the delay before the first retry of a batch, doubled on every retry up to
+	// MaxBackoff, with a random jitter
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+
+	// Progress, when set, is called after every batch written or failed. Calls are not
+	// concurrent and are made in the order batches complete. Batches skipped once the import
+	// stopped are not reported
+	Progress func(Progress)
+}
+
+// Progress reports a completed batch along with the progress of the import
+type Progress struct {
+	// Batch is the sequence number of the batch, starting at 1
+	Batch int
+
+	// Items is the number of items in the batch
+	Items int
+
+	// Counters are the changes made by the batch
+	Counters neox.Counters
+
+	// Attempts is the number of times the batch was written
+	Attempts int
+
+	// Err is the failure of the batch once its retries are exhausted
+	Err error
+
+	// Written is the number of items written so far
+	Written int
+
+	// Total sums up the counters of the batches written so far
+	Total neox.Counters
+}
+
+// Report summarizes an import
+type Report struct {
+	Batches int
+	Items   int
+	Retries int
+
+	// Skipped is the number of batches read from the source but not written, as the
+	// import stopped before their first attempt
+	Skipped int
+
+	Counters neox.Counters
+	Duration time.Duration
+}
+
+// BatchError is returned when a batch could not be written
+type BatchError struct {
+	Batch int
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("bulk: batch %d: %v", e.Batch, e.Err)
+}
+
+// Unwrap returns the failure of the batch
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// Importer writes the items of a source in concurrent batches. It is safe for concurrent
+// use, every Run opening its own sessions
+type Importer struct {
+	config Config
+	open   func() (Session, error)
+}
+
+// New returns an importer writing with sessions of the driver
+func New(driver *neox.Driver, config Config) *Importer {
+	return NewWithSessions(func() (Session, error) {
+		return driver.Sessionx(neo4j.AccessModeWrite)
+	}, config)
+}
+
+// NewWithSessions returns an importer writing with the sessions returned by open,
+// called once per worker
+func NewWithSessions(open func() (Session, error), config Config) *Importer {
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultBatchSize
+	}
+	if config.Workers <= 0 {
+		config.Workers = DefaultWorkers
+	}
+	if config.MaxRetries < 0 {
+		config.MaxRetries = 0
+	} else if config.MaxRetries == 0 {
+		config.MaxRetries = DefaultMaxRetries
+	}
+	if config.Backoff <= 0 {
+		config.Backoff = DefaultBackoff
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = DefaultMaxBackoff
+	}
+	return &Importer{config: config, open: open}
+}
+
+// batch is a chunk of items read from the source
+type batch struct {
+	seq   int
+	items []interface{}
+}
+
+// outcome is the result of writing a batch
+type outcome struct {
+	batch    batch
+	counters neox.Counters
+	attempts int
+	err      error
+}
+
+// Run imports the items of the source, returning once every item was written, a batch
+// failed after its retries or the context is done. The report covers the batches written
+// before a failure. A source blocking in Next when the import stops is left to return in
+// the background
+func (im *Importer) Run(ctx context.Context, source Source) (Report, error) {
+	start := time.Now()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		batches  = make(chan batch, im.config.Workers)
+		outcomes = make(chan outcome, im.config.Workers)
+		readErr  = make(chan error, 1)
+		wg       sync.WaitGroup
+	)
+
+	go func() {
+		readErr <- read(ctx, source, im.config.BatchSize, batches)
+	}()
+
+	sessions := make([]Session, 0, im.config.Workers)
+	var openErr error
+	for i := 0; i < im.config.Workers; i++ {
+		session, err := im.open()
+		if err != nil {
+			openErr = err
+			break
+		}
+		sessions = append(sessions, session)
+	}
+	defer func() {
+		for _, session := range sessions {
+			session.Close()
+		}
+	}()
+	if openErr != nil {
+		return Report{Duration: time.Since(start)}, openErr
+	}
+
+	for _, session := range sessions {
+		wg.Add(1)
+		go func(session Session) {
+			defer wg.Done()
+			for {
+				select {
+				case b, ok := <-batches:
+					if !ok {
+						return
+					}
+					outcomes <- im.write(ctx, session, b)
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(session)
+	}
+	go func() {
+		wg.Wait()
+		close(outcomes)
+	}()
+
+	var (
+		report Report
+		err    error
+	)
+	for o := range outcomes {
+		if o.attempts == 0 {
+			report.Skipped++
+			continue
+		}
+		report.Retries += o.attempts - 1
+		if o.err == nil {
+			report.Batches++
+			report.Items += len(o.batch.items)
+			report.Counters.Add(o.counters)
+		} else if err == nil {
+			err = &BatchError{Batch: o.batch.seq, Err: o.err}
+			cancel()
+		}
+		if im.config.Progress != nil {
+			im.config.Progress(Progress{
+				Batch:    o.batch.seq,
+				Items:    len(o.batch.items),
+				Counters: o.counters,
+				Attempts: o.attempts,
+				Err:      o.err,
+				Written:  report.Items,
+				Total:    report.Counters,
+			})
+		}
+	}
+	report.Duration = time.Since(start)
+
+	var rerr error
+	select {
+	case rerr = <-readErr:
+	case <-ctx.Done():
+		rerr = ctx.Err()
+	}
+	if err == nil {
+		err = rerr
+	}
+	return report, err
+}
+
+// write writes a batch, retrying it with a backoff while it fails with a transient error
+func (im *Importer) write(ctx context.Context, session Session, b batch) outcome {
+	o := outcome{batch: b}
+	delay := im.config.Backoff
+	for {
+		if err := ctx.Err(); err != nil {
+			o.err = err
+			return o
+		}
+		o.attempts++
+		o.counters, o.err = session.BatchWriteContext(ctx, im.config.Cypher, b.items, len(b.items))
+		if o.err == nil || !retriable(o.err) || o.attempts > im.config.MaxRetries {
+			return o
+		}
+
+		jitter := time.Duration(rand.Int63n(int64(delay)/2 + 1))
+		select {
+		case <-time.After(delay + jitter):
+		case <-ctx.Done():
+			o.err = ctx.Err()
+			return o
+		}
+		if delay *= 2; delay > im.config.MaxBackoff {
+			delay = im.config.MaxBackoff
+		}
+	}
+}
+
+// retriable reports whether a failed batch may succeed if written again
+func retriable(err error) bool {
+	return errors.Is(err, neox.ErrTransient) ||
+		errors.Is(err, neox.ErrDeadlock) ||
+		errors.Is(err, neox.ErrServiceUnavailable)
+}
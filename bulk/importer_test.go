@@ -0,0 +1,274 @@
+package bulk_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/syllabix/neox"
+	"github.com/syllabix/neox/bulk"
+	"github.com/syllabix/neox/neoxtest"
+)
+
+const cypher = "unwind $batch as row merge (u:User {id: row.id})"
+
+// fakeSession records the batches it writes, failing them as fail decides
+type fakeSession struct {
+	mu      sync.Mutex
+	batches [][]interface{}
+	closed  int
+	fail    func(attempt int, items []interface{}) error
+	attempt int
+}
+
+func (s *fakeSession) open() (bulk.Session, error) {
+	return s, nil
+}
+
+func (s *fakeSession) BatchWriteContext(ctx context.Context, cypher string, items interface{}, batchSize int) (neox.Counters, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := items.([]interface{})
+	if len(list) != batchSize {
+		return neox.Counters{}, fmt.Errorf("batch size = %d, want %d", batchSize, len(list))
+	}
+	s.attempt++
+	if s.fail != nil {
+		if err := s.fail(s.attempt, list); err != nil {
+			return neox.Counters{}, err
+		}
+	}
+	s.batches = append(s.batches, list)
+	return neox.Counters{NodesCreated: len(list)}, nil
+}
+
+func (s *fakeSession) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed++
+	return nil
+}
+
+func (s *fakeSession) written() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var ids []int
+	for _, b := range s.batches {
+		for _, item := range b {
+			ids = append(ids, item.(int))
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func items(n int) []int {
+	list := make([]int, n)
+	for i := range list {
+		list[i] = i
+	}
+	return list
+}
+
+func deadlock() error {
+	return neox.ClassifyError(neoxtest.DatabaseError("Neo.TransientError.Transaction.DeadlockDetected", "deadlock"))
+}
+
+func TestImporter_Run(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		items       int
+		fail        func(attempt int, items []interface{}) error
+		wantItems   int
+		wantRetries int
+		wantBatch   int
+		wantErr     error
+	}{
+		{
+			name:      "Should write every item in batches",
+			items:     23,
+			wantItems: 23,
+		},
+		{
+			name:      "Should write nothing for an empty source",
+			items:     0,
+			wantItems: 0,
+		},
+		{
+			name:  "Should retry batches failing with a transient error",
+			items: 10,
+			fail: func(attempt int, items []interface{}) error {
+				if attempt <= 2 {
+					return deadlock()
+				}
+				return nil
+			},
+			wantItems:   10,
+			wantRetries: 2,
+		},
+		{
+			name:  "Should fail once the retries are exhausted",
+			items: 5,
+			fail: func(attempt int, items []interface{}) error {
+				return deadlock()
+			},
+			wantRetries: 3,
+			wantBatch:   1,
+			wantErr:     neox.ErrDeadlock,
+		},
+		{
+			name:  "Should not retry other errors",
+			items: 5,
+			fail: func(attempt int, items []interface{}) error {
+				return neox.ClassifyError(neoxtest.DatabaseError("Neo.ClientError.Statement.SyntaxError", "invalid"))
+			},
+			wantBatch: 1,
+			wantErr:   neox.ErrSyntax,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := &fakeSession{fail: tt.fail}
+			var progress []bulk.Progress
+			im := bulk.NewWithSessions(session.open, bulk.Config{
+				Cypher:     cypher,
+				BatchSize:  5,
+				Workers:    3,
+				MaxRetries: 3,
+				Backoff:    time.Millisecond,
+				Progress:   func(p bulk.Progress) { progress = append(progress, p) },
+			})
+
+			report, err := im.Run(context.Background(), bulk.FromSlice(items(tt.items)))
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("Importer.Run() error = %v, want %v", err, tt.wantErr)
+			}
+			var batchErr *bulk.BatchError
+			if tt.wantErr != nil && (!errors.As(err, &batchErr) || batchErr.Batch != tt.wantBatch) {
+				t.Errorf("Importer.Run() error = %v, want a failure of batch %d", err, tt.wantBatch)
+			}
+			if report.Items != tt.wantItems || report.Counters.NodesCreated != tt.wantItems {
+				t.Errorf("Importer.Run() got = %+v, want %d items", report, tt.wantItems)
+			}
+			if report.Retries != tt.wantRetries {
+				t.Errorf("Importer.Run() retries = %d, want %d", report.Retries, tt.wantRetries)
+			}
+			if got := session.written(); len(got) != tt.wantItems || (tt.wantItems > 0 && !reflect.DeepEqual(got, items(tt.wantItems))) {
+				t.Errorf("written items = %v, want %d items", got, tt.wantItems)
+			}
+			if session.closed != 3 {
+				t.Errorf("closed sessions = %d, want 3", session.closed)
+			}
+
+			written := 0
+			for _, p := range progress {
+				if p.Err == nil {
+					written += p.Items
+				}
+				if p.Written != written {
+					t.Errorf("Progress.Written = %d, want %d", p.Written, written)
+				}
+			}
+			if last := len(progress); tt.wantErr == nil && last > 0 && progress[last-1].Total != report.Counters {
+				t.Errorf("Progress.Total = %+v, want %+v", progress[last-1].Total, report.Counters)
+			}
+		})
+	}
+}
+
+func TestImporter_Run_Channel(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should stop when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		users := make(chan interface{})
+		session := &fakeSession{}
+		im := bulk.NewWithSessions(session.open, bulk.Config{Cypher: cypher, BatchSize: 2, Workers: 2})
+
+		go func() {
+			users <- 1
+			users <- 2
+			cancel()
+		}()
+		_, err := im.Run(ctx, bulk.FromChannel(users))
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Importer.Run() error = %v, want %v", err, context.Canceled)
+		}
+	})
+
+	t.Run("Should skip the batches left once the context is done", func(t *testing.T) {
+		for i := 0; i < 20; i++ {
+			ctx, cancel := context.WithCancel(context.Background())
+			session := &fakeSession{fail: func(attempt int, items []interface{}) error {
+				cancel()
+				return nil
+			}}
+			var progress []bulk.Progress
+			im := bulk.NewWithSessions(session.open, bulk.Config{
+				Cypher:    cypher,
+				BatchSize: 2,
+				Workers:   1,
+				Progress:  func(p bulk.Progress) { progress = append(progress, p) },
+			})
+
+			report, err := im.Run(ctx, bulk.FromSlice(items(20)))
+			if !errors.Is(err, context.Canceled) {
+				t.Fatalf("Importer.Run() error = %v, want %v", err, context.Canceled)
+			}
+			if report.Batches != 1 || report.Retries != 0 || report.Batches+report.Skipped > 10 {
+				t.Fatalf("Importer.Run() got = %+v, want 1 batch without retries", report)
+			}
+			if len(progress) != 1 || progress[0].Attempts != 1 || progress[0].Err != nil {
+				t.Fatalf("Progress got = %+v, want the written batch only", progress)
+			}
+		}
+	})
+
+	t.Run("Should fail on source errors", func(t *testing.T) {
+		failure := errors.New("broken source")
+		session := &fakeSession{}
+		im := bulk.NewWithSessions(session.open, bulk.Config{Cypher: cypher})
+
+		_, err := im.Run(context.Background(), bulk.SourceFunc(func() (interface{}, error) {
+			return nil, failure
+		}))
+		if !errors.Is(err, failure) {
+			t.Errorf("Importer.Run() error = %v, want %v", err, failure)
+		}
+	})
+
+	t.Run("Should write with the sessions of a driver", func(t *testing.T) {
+		fake := neoxtest.NewDriver()
+		fake.On(cypher).WithSummary(neoxtest.Summary{Counters: neoxtest.Counters{NodesCreated: 2}}).Times(2)
+
+		users := make(chan interface{})
+		go func() {
+			defer close(users)
+			for _, id := range []string{"a", "b", "c", "d"} {
+				users <- neox.Args{"id": id}
+			}
+		}()
+
+		im := bulk.New(fake.Neox(), bulk.Config{Cypher: cypher, BatchSize: 2, Workers: 2})
+		report, err := im.Run(context.Background(), bulk.FromChannel(users))
+		if err != nil {
+			t.Fatalf("Importer.Run() error = %v", err)
+		}
+		if report.Batches != 2 || report.Counters.NodesCreated != 4 {
+			t.Errorf("Importer.Run() got = %+v, want 2 batches creating 4 nodes", report)
+		}
+		if unmet := fake.Unmet(); len(unmet) > 0 {
+			t.Errorf("Unmet() = %v", unmet)
+		}
+		if open := fake.OpenSessions(); open != 0 {
+			t.Errorf("OpenSessions() = %d, want 0", open)
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package bulk
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// Source is an iterator over the items to import. Next returns io.EOF once every
+// item was returned. Items are structs, pointers to structs, neox.Args or maps, as
+// accepted by neox.ToArgs
+type Source interface {
+	Next() (interface{}, error)
+}
+
+// SourceFunc adapts a function to a Source
+type SourceFunc func() (interface{}, error)
+
+// Next calls f
+func (f SourceFunc) Next() (interface{}, error) {
+	return f()
+}
+
+// FromChannel returns a Source reading items from the channel until it is closed
+func FromChannel(items <-chan interface{}) Source {
+	return SourceFunc(func() (interface{}, error) {
+		item, ok := <-items
+		if !ok {
+			return nil, io.EOF
+		}
+		return item, nil
+	})
+}
+
+// FromSlice returns a Source iterating over the elements of a slice
+func FromSlice(items interface{}) Source {
+	list := reflect.ValueOf(items)
+	if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+		return SourceFunc(func() (interface{}, error) {
+			return nil, fmt.Errorf("bulk: items must be a slice, got %T", items)
+		})
+	}
+	i := 0
+	return SourceFunc(func() (interface{}, error) {
+		if i >= list.Len() {
+			return nil, io.EOF
+		}
+		i++
+		return list.Index(i - 1).Interface(), nil
+	})
+}
+
+// read sends the items of the source to the batches channel in batches of the
+// provided size, until the source is exhausted or the context is done
+func read(ctx context.Context, source Source, size int, batches chan<- batch) error {
+	defer close(batches)
+
+	var (
+		items []interface{}
+		seq   int
+	)
+	send := func() bool {
+		seq++
+		select {
+		case batches <- batch{seq: seq, items: items}:
+			items = nil
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	for {
+		item, err := source.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("bulk: reading item: %w", err)
+		}
+		items = append(items, item)
+		if len(items) == size && !send() {
+			return ctx.Err()
+		}
+	}
+	if len(items) > 0 && !send() {
+		return ctx.Err()
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+// Command neox runs the migrations of the neox/migrate package from the command line,
+// so they can be applied by deploy pipelines without writing Go
+//
+//	neox migrate up [-to version]
+//	neox migrate down [-steps n | -to version]
+//	neox migrate status
+//	neox migrate create <name>
+//	neox migrate unlock
+//
+// Migrations are read from the directory set by -dir, or the NEOX_MIGRATIONS environment
+// variable, ./migrations by default. The database is set by the -uri, -user and -password
+// flags, which default to the NEO4J_URI, NEO4J_USER and NEO4J_PASSWORD environment variables.
+// Passing the password through the environment keeps it out of the process list.
+//
+// bolt:// and bolt+s:// uris connect directly to a single server, bolt+routing:// and
+// neo4j:// uris route statements to the members of a cluster. Other schemes are rejected
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+	"github.com/syllabix/neox"
+	"github.com/syllabix/neox/bolt"
+)
+
+func main() {
+	c := &cli{
+		stdout:  os.Stdout,
+		stderr:  os.Stderr,
+		getenv:  os.Getenv,
+		now:     time.Now,
+		connect: connect,
+	}
+	os.Exit(c.run(os.Args[1:]))
+}
+
+// exit codes of the command
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+// cli holds the dependencies of the command, replaced in tests
+type cli struct {
+	stdout  io.Writer
+	stderr  io.Writer
+	getenv  func(string) string
+	now     func() time.Time
+	connect func(config) (*neox.Driver, error)
+}
+
+const usage = `usage: neox <command> [arguments]
+
+commands:
+  migrate    applies, reverts and creates migrations, see neox migrate -h
+`
+
+func (c *cli) run(args []string) int {
+	if len(args) == 0 {
+		io.WriteString(c.stderr, usage)
+		return exitUsage
+	}
+	switch args[0] {
+	case "migrate":
+		return c.migrate(args[1:])
+	case "-h", "-help", "--help", "help":
+		io.WriteString(c.stdout, usage)
+		return exitOK
+	default:
+		c.errorf("unknown command %q\n\n%s", args[0], usage)
+		return exitUsage
+	}
+}
+
+// config is the database and directory the migrations run with
+type config struct {
+	uri      string
+	user     string
+	password string
+	dir      string
+	timeout  time.Duration
+	dryRun   bool
+	verbose  bool
+}
+
+// connect connects to single servers through the pure Go bolt package, and to clusters
+// through the routing driver, which knows neo4j uris as bolt+routing ones
+func connect(conf config) (*neox.Driver, error) {
+	target, err := url.Parse(conf.uri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid uri %q: %w", conf.uri, err)
+	}
+
+	switch target.Scheme {
+	case "bolt", "bolt+s":
+		auth := bolt.NoAuth()
+		if conf.user != "" {
+			auth = bolt.BasicAuth(conf.user, conf.password, "")
+		}
+		return neox.NewBoltDriver(conf.uri, auth)
+	case "bolt+routing", "neo4j":
+		auth := neo4j.NoAuth()
+		if conf.user != "" {
+			auth = neo4j.BasicAuth(conf.user, conf.password, "")
+		}
+		target.Scheme = "bolt+routing"
+		return neox.NewDriver(target.String(), auth)
+	}
+	return nil, fmt.Errorf("unsupported uri scheme %q, use bolt, bolt+s, bolt+routing or neo4j", target.Scheme)
+}
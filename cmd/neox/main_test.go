@@ -0,0 +1,353 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/syllabix/neox"
+	"github.com/syllabix/neox/migrate"
+	"github.com/syllabix/neox/neoxtest"
+)
+
+// newCLI returns a cli connecting to the fake driver, with the provided environment
+func newCLI(fake *neoxtest.Driver, env map[string]string) (*cli, *bytes.Buffer, *bytes.Buffer, *config) {
+	var (
+		stdout, stderr bytes.Buffer
+		conf           config
+	)
+	c := &cli{
+		stdout: &stdout,
+		stderr: &stderr,
+		getenv: func(name string) string { return env[name] },
+		now:    func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) },
+		connect: func(c config) (*neox.Driver, error) {
+			conf = c
+			return fake.Neox(), nil
+		},
+	}
+	return c, &stdout, &stderr, &conf
+}
+
+func writeMigrations(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+var migrations = map[string]string{
+	"1_users.up.cypher":    "create index users for (u:User) on (u.id);",
+	"1_users.down.cypher":  "drop index users;",
+	"2_emails.up.cypher":   "create index emails for (u:User) on (u.email);",
+	"2_emails.down.cypher": "drop index emails;",
+}
+
+// checksum returns the checksum of the migration of the version in migrations
+func checksum(t *testing.T, version int64) string {
+	fsys := fstest.MapFS{}
+	for name, content := range migrations {
+		fsys[name] = &fstest.MapFile{Data: []byte(content)}
+	}
+	list, err := migrate.Load(fsys, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range list {
+		if m.Version == version {
+			return m.Checksum()
+		}
+	}
+	return ""
+}
+
+func TestCLI_Run(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    int
+		wantErr string
+	}{
+		{name: "Should print the usage without a command", args: nil, want: exitUsage, wantErr: "usage: neox"},
+		{name: "Should reject unknown commands", args: []string{"serve"}, want: exitUsage, wantErr: `unknown command "serve"`},
+		{name: "Should reject unknown migrate commands", args: []string{"migrate", "redo"}, want: exitUsage, wantErr: `unknown migrate command "redo"`},
+		{name: "Should reject unknown flags", args: []string{"migrate", "up", "-force"}, want: exitUsage, wantErr: "flag provided but not defined: -force"},
+		{name: "Should reject extra arguments", args: []string{"migrate", "status", "now"}, want: exitUsage, wantErr: `unexpected arguments ["now"]`},
+		{name: "Should reject steps along with a version", args: []string{"migrate", "down", "-steps", "2", "-to", "1"}, want: exitUsage, wantErr: "-steps and -to can not be used together"},
+		{name: "Should require the name of a migration", args: []string{"migrate", "create"}, want: exitUsage, wantErr: "unexpected arguments []"},
+		{name: "Should report missing directories", args: []string{"migrate", "status", "-dir", "/does/not/exist"}, want: exitError, wantErr: "no such file or directory"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _, stderr, _ := newCLI(neoxtest.NewDriver(), nil)
+			if got := c.run(tt.args); got != tt.want {
+				t.Errorf("cli.run() got = %v, want %v", got, tt.want)
+			}
+			if !strings.Contains(stderr.String(), tt.wantErr) {
+				t.Errorf("cli.run() stderr = %q, want %q", stderr.String(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCLI_Config(t *testing.T) {
+	t.Parallel()
+
+	dir := writeMigrations(t, migrations)
+	env := map[string]string{
+		"NEO4J_URI":       "neo4j://db:7687",
+		"NEO4J_USER":      "admin",
+		"NEO4J_PASSWORD":  "secret",
+		"NEOX_MIGRATIONS": dir,
+	}
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		args []string
+		want config
+	}{
+		{
+			name: "Should default to the environment",
+			env:  env,
+			args: []string{"migrate", "status"},
+			want: config{uri: "neo4j://db:7687", user: "admin", password: "secret", dir: dir},
+		},
+		{
+			name: "Should prefer flags over the environment",
+			env:  env,
+			args: []string{"migrate", "status", "-uri", "bolt://localhost:7688", "-user", "", "-timeout", "1m"},
+			want: config{uri: "bolt://localhost:7688", password: "secret", dir: dir, timeout: time.Minute},
+		},
+		{
+			name: "Should default to a local database",
+			env:  map[string]string{},
+			args: []string{"migrate", "status", "-dir", dir},
+			want: config{uri: "bolt://localhost:7687", user: "neo4j", dir: dir},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := neoxtest.NewDriver()
+			fake.OnRegexp(`^match \(m:__NeoxMigration\)`).Return([]string{"version", "name", "checksum", "applied_at"})
+
+			c, _, stderr, got := newCLI(fake, tt.env)
+			if code := c.run(tt.args); code != exitOK {
+				t.Fatalf("cli.run() got = %v, want %v: %s", code, exitOK, stderr)
+			}
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("cli.run() config = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCLI_Migrate(t *testing.T) {
+	t.Parallel()
+
+	var (
+		appliedAt = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		users     = checksum(t, 1)
+		emails    = checksum(t, 2)
+	)
+	applied := func(fake *neoxtest.Driver, rows ...[]interface{}) {
+		fake.OnRegexp(`^match \(m:__NeoxMigration\)`).Return([]string{"version", "name", "checksum", "applied_at"}, rows...)
+	}
+	locked := func(fake *neoxtest.Driver) {
+		fake.OnRegexp(`^create constraint`).Times(2)
+		fake.OnRegexp(`^merge \(l:__NeoxMigrationLock`).Return([]string{"owner"}, []interface{}{"owner"})
+		fake.OnRegexp(`^match \(l:__NeoxMigrationLock`)
+	}
+
+	tests := []struct {
+		name  string
+		args  []string
+		setup func(*neoxtest.Driver)
+		want  string
+	}{
+		{
+			name: "Should apply the pending migrations",
+			args: []string{"migrate", "up"},
+			setup: func(fake *neoxtest.Driver) {
+				locked(fake)
+				applied(fake)
+				fake.OnRegexp(`^create index`).Times(2)
+				fake.OnRegexp(`^create \(m:__NeoxMigration`).Times(2)
+			},
+			want: "applied 1_users\napplied 2_emails\n",
+		},
+		{
+			name: "Should list the migrations to apply on dry runs",
+			args: []string{"migrate", "up", "-dry-run", "-to", "1"},
+			setup: func(fake *neoxtest.Driver) {
+				applied(fake)
+			},
+			want: "would apply 1_users\n",
+		},
+		{
+			name: "Should report when nothing is pending",
+			args: []string{"migrate", "up", "-to", "1"},
+			setup: func(fake *neoxtest.Driver) {
+				locked(fake)
+				applied(fake, []interface{}{int64(1), "users", users, appliedAt})
+			},
+			want: "no migration to apply\n",
+		},
+		{
+			name: "Should revert the latest migration",
+			args: []string{"migrate", "down"},
+			setup: func(fake *neoxtest.Driver) {
+				locked(fake)
+				applied(fake, []interface{}{int64(1), "users", users, appliedAt}, []interface{}{int64(2), "emails", emails, appliedAt})
+				fake.On("drop index emails")
+				fake.OnRegexp(`^match \(m:__NeoxMigration \{version: \$version\}\) delete m`).WithArgs(neox.Args{"version": int64(2)})
+			},
+			want: "reverted 2_emails\n",
+		},
+		{
+			name: "Should release the lock",
+			args: []string{"migrate", "unlock"},
+			setup: func(fake *neoxtest.Driver) {
+				fake.OnRegexp(`^match \(l:__NeoxMigrationLock \{name: "migrate"\}\)`)
+			},
+			want: "released the migration lock\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := neoxtest.NewDriver()
+			tt.setup(fake)
+
+			c, stdout, stderr, _ := newCLI(fake, map[string]string{"NEOX_MIGRATIONS": writeMigrations(t, migrations)})
+			if code := c.run(tt.args); code != exitOK {
+				t.Fatalf("cli.run() got = %v, want %v: %s", code, exitOK, stderr)
+			}
+			if got := stdout.String(); got != tt.want {
+				t.Errorf("cli.run() stdout = %q, want %q", got, tt.want)
+			}
+			if unmet := fake.Unmet(); len(unmet) > 0 {
+				t.Errorf("Unmet() = %v", unmet)
+			}
+		})
+	}
+}
+
+func TestCLI_Status(t *testing.T) {
+	t.Parallel()
+
+	fake := neoxtest.NewDriver()
+	fake.OnRegexp(`^match \(m:__NeoxMigration\)`).Return([]string{"version", "name", "checksum", "applied_at"},
+		[]interface{}{int64(1), "users", "changed", time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)},
+		[]interface{}{int64(3), "gone", "", time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)},
+	)
+
+	c, stdout, stderr, _ := newCLI(fake, map[string]string{"NEOX_MIGRATIONS": writeMigrations(t, migrations)})
+	if code := c.run([]string{"migrate", "status"}); code != exitOK {
+		t.Fatalf("cli.run() got = %v, want %v: %s", code, exitOK, stderr)
+	}
+	want := "" +
+		"VERSION  NAME    STATUS   APPLIED AT\n" +
+		"1        users   changed  2024-01-01T12:00:00Z\n" +
+		"2        emails  pending  \n" +
+		"3        gone    missing  2024-01-02T12:00:00Z\n"
+	if got := stdout.String(); got != want {
+		t.Errorf("cli.run() stdout = %q, want %q", got, want)
+	}
+}
+
+func TestCLI_Create(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		existing map[string]string
+		arg      string
+		want     []string
+	}{
+		{
+			name: "Should version migrations by their creation time",
+			arg:  "Add user email",
+			want: []string{"20240102030405_add_user_email.down.cypher", "20240102030405_add_user_email.up.cypher"},
+		},
+		{
+			name:     "Should stay ahead of existing migrations",
+			existing: map[string]string{"20240102030405_users.up.cypher": "create index users for (u:User) on (u.id)"},
+			arg:      "emails",
+			want: []string{
+				"20240102030405_users.up.cypher",
+				"20240102030406_emails.down.cypher", "20240102030406_emails.up.cypher",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(writeMigrations(t, tt.existing), "migrations")
+			if len(tt.existing) > 0 {
+				dir = filepath.Dir(dir)
+			}
+
+			c, stdout, stderr, _ := newCLI(nil, nil)
+			if code := c.run([]string{"migrate", "create", "-dir", dir, tt.arg}); code != exitOK {
+				t.Fatalf("cli.run() got = %v, want %v: %s", code, exitOK, stderr)
+			}
+
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, entry := range entries {
+				got = append(got, entry.Name())
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("cli.run() files = %v, want %v", got, tt.want)
+			}
+			if lines := strings.Count(stdout.String(), "\n"); lines != 2 {
+				t.Errorf("cli.run() stdout = %q, want the 2 created files", stdout)
+			}
+		})
+	}
+}
+
+func TestConnect(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		uri     string
+		wantErr string
+	}{
+		{name: "Should connect to a single server", uri: "bolt://localhost:7687"},
+		{name: "Should connect to a single server over tls", uri: "bolt+s://localhost:7687"},
+		{name: "Should reject self signed certificates", uri: "bolt+ssc://localhost:7687", wantErr: `unsupported uri scheme "bolt+ssc", use bolt, bolt+s, bolt+routing or neo4j`},
+		{name: "Should reject other schemes", uri: "http://localhost:7474", wantErr: `unsupported uri scheme "http"`},
+		{name: "Should reject invalid uris", uri: "bolt://local host", wantErr: "invalid uri"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			driver, err := connect(config{uri: tt.uri, user: "neo4j", password: "secret"})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("connect() error = %v", err)
+				}
+				driver.Close()
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("connect() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/syllabix/neox/migrate"
+)
+
+const migrateUsage = `usage: neox migrate <command> [flags]
+
+commands:
+  up        applies the pending migrations
+  down      reverts the latest applied migration, or those set by -steps or -to
+  status    lists the migrations and whether they are applied
+  create    writes the up and down files of a new migration, ie: neox migrate create add_user_email
+  unlock    releases the migration lock left by a runner that did not stop cleanly
+
+Run neox migrate <command> -h for the flags of a command
+`
+
+// errUsage is returned when the command line is invalid, its usage was already printed
+var errUsage = errors.New("invalid usage")
+
+func (c *cli) migrate(args []string) int {
+	if len(args) == 0 {
+		io.WriteString(c.stderr, migrateUsage)
+		return exitUsage
+	}
+
+	var cmd func(config, []string) error
+	switch args[0] {
+	case "up":
+		cmd = c.up
+	case "down":
+		cmd = c.down
+	case "status":
+		cmd = c.status
+	case "create":
+		cmd = c.create
+	case "unlock":
+		cmd = c.unlock
+	case "-h", "-help", "--help", "help":
+		io.WriteString(c.stdout, migrateUsage)
+		return exitOK
+	default:
+		c.errorf("unknown migrate command %q\n\n%s", args[0], migrateUsage)
+		return exitUsage
+	}
+
+	err := cmd(config{}, args[1:])
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.Is(err, errUsage):
+		return exitUsage
+	default:
+		c.errorf("%v\n", err)
+		return exitError
+	}
+}
+
+// flags returns the flag set of a migrate command, with the flags shared by every
+// command set on conf. Database flags are only added when the command connects
+func (c *cli) flags(name string, conf *config, database bool) *flag.FlagSet {
+	fs := flag.NewFlagSet("neox migrate "+name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.StringVar(&conf.dir, "dir", c.env("NEOX_MIGRATIONS", "migrations"), "directory of the migration files, or $NEOX_MIGRATIONS")
+	if !database {
+		return fs
+	}
+	fs.StringVar(&conf.uri, "uri", c.env("NEO4J_URI", "bolt://localhost:7687"), "uri of the database, or $NEO4J_URI")
+	fs.StringVar(&conf.user, "user", c.env("NEO4J_USER", "neo4j"), "user to authenticate as, or $NEO4J_USER, none when empty")
+	fs.StringVar(&conf.password, "password", c.env("NEO4J_PASSWORD", ""), "password of the user, or $NEO4J_PASSWORD")
+	fs.DurationVar(&conf.timeout, "timeout", 0, "time allowed to run the command, checked between statements, unlimited when 0")
+	fs.BoolVar(&conf.verbose, "v", false, "logs every statement run")
+	return fs
+}
+
+// parse parses the flags of a command, failing when positional arguments are left
+// beyond the expected count
+func (c *cli) parse(fs *flag.FlagSet, args []string, positional int) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	if fs.NArg() != positional {
+		c.errorf("%s: unexpected arguments %q\n", fs.Name(), fs.Args())
+		fs.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+func (c *cli) up(conf config, args []string) error {
+	fs := c.flags("up", &conf, true)
+	fs.BoolVar(&conf.dryRun, "dry-run", false, "lists the migrations to apply without applying them")
+	to := fs.Int64("to", 0, "applies the pending migrations up to this version included, all when 0")
+	if err := c.parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	return c.withMigrator(conf, func(ctx context.Context, m *migrate.Migrator) error {
+		version := *to
+		if version == 0 {
+			version = math.MaxInt64
+		}
+		applied, err := m.UpTo(ctx, version)
+		if err == nil || len(applied) > 0 {
+			c.report(conf, "apply", "applied", applied)
+		}
+		return err
+	})
+}
+
+func (c *cli) down(conf config, args []string) error {
+	fs := c.flags("down", &conf, true)
+	fs.BoolVar(&conf.dryRun, "dry-run", false, "lists the migrations to revert without reverting them")
+	steps := fs.Int("steps", 1, "number of applied migrations to revert, the latest first")
+	to := fs.Int64("to", -1, "reverts the applied migrations newer than this version, all when 0")
+	if err := c.parse(fs, args, 0); err != nil {
+		return err
+	}
+	var stepsSet bool
+	fs.Visit(func(f *flag.Flag) {
+		stepsSet = stepsSet || f.Name == "steps"
+	})
+	if stepsSet && *to >= 0 {
+		c.errorf("%s: -steps and -to can not be used together\n", fs.Name())
+		return errUsage
+	}
+
+	return c.withMigrator(conf, func(ctx context.Context, m *migrate.Migrator) error {
+		var (
+			reverted []migrate.Migration
+			err      error
+		)
+		if *to >= 0 {
+			reverted, err = m.DownTo(ctx, *to)
+		} else {
+			reverted, err = m.Down(ctx, *steps)
+		}
+		if err == nil || len(reverted) > 0 {
+			c.report(conf, "revert", "reverted", reverted)
+		}
+		return err
+	})
+}
+
+func (c *cli) status(conf config, args []string) error {
+	fs := c.flags("status", &conf, true)
+	if err := c.parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	return c.withMigrator(conf, func(ctx context.Context, m *migrate.Migrator) error {
+		status, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, s := range status {
+			appliedAt := ""
+			if s.Applied {
+				appliedAt = s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", s.Migration.Version, s.Migration.Name, state(s), appliedAt)
+		}
+		return w.Flush()
+	})
+}
+
+// state describes the status of a migration
+func state(s migrate.Status) string {
+	switch {
+	case s.Missing:
+		return "missing"
+	case s.Applied && s.Checksum != s.Migration.Checksum():
+		return "changed"
+	case s.Applied:
+		return "applied"
+	default:
+		return "pending"
+	}
+}
+
+func (c *cli) unlock(conf config, args []string) error {
+	fs := c.flags("unlock", &conf, true)
+	if err := c.parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	return c.withMigrator(conf, func(ctx context.Context, m *migrate.Migrator) error {
+		if err := m.ForceUnlock(ctx); err != nil {
+			return err
+		}
+		fmt.Fprintln(c.stdout, "released the migration lock")
+		return nil
+	})
+}
+
+// nonword matches the characters replaced when turning a name into a file name
+var nonword = regexp.MustCompile(`[^a-z0-9]+`)
+
+// versionLayout is the layout of the time a created migration is versioned with
+const versionLayout = "20060102150405"
+
+func (c *cli) create(conf config, args []string) error {
+	fs := c.flags("create", &conf, false)
+	if err := c.parse(fs, args, 1); err != nil {
+		return err
+	}
+	name := strings.Trim(nonword.ReplaceAllString(strings.ToLower(fs.Arg(0)), "_"), "_")
+	if name == "" {
+		c.errorf("%s: invalid migration name %q\n", fs.Name(), fs.Arg(0))
+		return errUsage
+	}
+
+	if err := os.MkdirAll(conf.dir, 0o755); err != nil {
+		return err
+	}
+	migrations, err := migrate.Load(os.DirFS(conf.dir), ".")
+	if err != nil {
+		return err
+	}
+
+	// versions are the creation time, bumped to stay ahead of existing migrations
+	// when clocks disagree or several migrations are created within a second
+	var version int64
+	fmt.Sscan(c.now().UTC().Format(versionLayout), &version)
+	if n := len(migrations); n > 0 && migrations[n-1].Version >= version {
+		version = migrations[n-1].Version + 1
+	}
+
+	for _, direction := range []string{"up", "down"} {
+		path := filepath.Join(conf.dir, fmt.Sprintf("%d_%s.%s.cypher", version, name, direction))
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(f, "// %s %s\n", strings.ToUpper(direction[:1])+direction[1:], name)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(c.stdout, path)
+	}
+	return nil
+}
+
+// withMigrator loads the migrations of the directory and runs fn with a migrator
+// connected to the database
+func (c *cli) withMigrator(conf config, fn func(context.Context, *migrate.Migrator) error) error {
+	migrations, err := migrate.Load(os.DirFS(conf.dir), ".")
+	if err != nil {
+		return err
+	}
+	driver, err := c.connect(conf)
+	if err != nil {
+		return err
+	}
+	defer driver.Close()
+
+	m, err := migrate.New(driver, migrations...)
+	if err != nil {
+		return err
+	}
+	m.DryRun = conf.dryRun
+	if conf.verbose {
+		m.Logger = log.New(c.stderr, "", log.LstdFlags)
+	}
+
+	ctx := context.Background()
+	if conf.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, conf.timeout)
+		defer cancel()
+	}
+	return fn(ctx, m)
+}
+
+// report prints the migrations that were run, or would have been on a dry run
+func (c *cli) report(conf config, verb, done string, migrations []migrate.Migration) {
+	if len(migrations) == 0 {
+		fmt.Fprintf(c.stdout, "no migration to %s\n", verb)
+		return
+	}
+	if conf.dryRun {
+		done = "would " + verb
+	}
+	for _, m := range migrations {
+		fmt.Fprintf(c.stdout, "%s %s\n", done, m)
+	}
+}
+
+// env returns the value of the environment variable, or def when it is not set
+func (c *cli) env(name, def string) string {
+	if v := c.getenv(name); v != "" {
+		return v
+	}
+	return def
+}
+
+func (c *cli) errorf(format string, args ...interface{}) {
+	fmt.Fprintf(c.stderr, "neox: "+format, args...)
+}
@@ -0,0 +1,37 @@
+// Package cypher provides a builder for cypher statements, binding values to
+// generated parameters instead of concatenating them into the statement.
+//
+// Patterns, predicates and projections are written as cypher, with a ? placeholder
+// wherever a value is used. Placeholders inside quoted strings and escaped identifiers
+// are left as is
+//
+//	q := cypher.Match("(u:User)").
+//		OptionalMatch("(u)-[:FOLLOWS]->(f:User)")
+//
+//	if name != "" {
+//		q.Where("u.name starts with ?", name)
+//	}
+//	if len(ids) > 0 {
+//		q.Where("u.id in ?", ids)
+//	}
+//
+//	q.With("u, count(f) as follows").
+//		Return("u.name as user_name", "follows").
+//		OrderBy("follows desc").
+//		Skip(20).
+//		Limit(10)
+//
+//	// match (u:User) optional match (u)-[:FOLLOWS]->(f:User)
+//	// where (u.name starts with $p1) and (u.id in $p2)
+//	// with u, count(f) as follows return u.name as user_name, follows
+//	// order by follows desc skip $p3 limit $p4
+//	res, err := session.Runx(q.Build())
+//
+// Labels, relationship types and property keys can not be passed as parameters. Escape
+// validates and quotes them so they can be written into a statement, and Template
+// substitutes them into {{name}} placeholders, leaving values to parameters
+//
+//	label, err := cypher.Escape(kind)
+//	...
+//	q := cypher.Match("(n:" + label + ")").Return("n")
+package cypher
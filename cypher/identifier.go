@@ -0,0 +1,117 @@
+package cypher
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/syllabix/neox"
+	"github.com/syllabix/neox/internal/identifier"
+)
+
+// ErrInvalidIdentifier is matched by the errors returned for names that can not be
+// safely used as a label, relationship type, property key or variable
+var ErrInvalidIdentifier = identifier.ErrInvalid
+
+// Escape validates a label, relationship type, property key or variable name, and quotes
+// it with backticks unless it is a plain identifier, ie: User stays User while
+// first name becomes `first name`. Backticks within the name are doubled.
+//
+// Names must be valid non empty UTF-8 without control characters or backslashes, the
+// latter being rejected as some servers interpret unicode escapes within quoted names
+func Escape(name string) (string, error) {
+	return identifier.Escape(name)
+}
+
+// Labels escapes each of the provided labels and joins them for use in a node
+// pattern, ie: Labels("User", "Team Lead") returns :User:`Team Lead`
+func Labels(names ...string) (string, error) {
+	var b strings.Builder
+	for _, name := range names {
+		label, err := Escape(name)
+		if err != nil {
+			return "", err
+		}
+		b.WriteByte(':')
+		b.WriteString(label)
+	}
+	return b.String(), nil
+}
+
+// Identifiers are the names substituted into a template, by placeholder
+type Identifiers map[string]string
+
+// Template substitutes the {{name}} placeholders of a statement with the escaped
+// identifiers, leaving values to be passed as parameters. Placeholders within quoted
+// strings are left as is. An error is returned when an identifier is invalid or missing
+//
+//	stmt, args, err := cypher.Template(
+//		"match (n:{{label}}) where n.{{key}} = $value return n",
+//		cypher.Identifiers{"label": label, "key": key},
+//		neox.Args{"value": value},
+//	)
+//	...
+//	res, err := session.Runx(stmt, args)
+func Template(stmt string, identifiers Identifiers, args neox.Args) (string, neox.Args, error) {
+	var (
+		b    strings.Builder
+		scan scanner
+	)
+	for i := 0; i < len(stmt); {
+		r, size := utf8.DecodeRuneInString(stmt[i:])
+		if scan.quoted(r) || !strings.HasPrefix(stmt[i:], "{{") {
+			b.WriteString(stmt[i : i+size])
+			i += size
+			continue
+		}
+
+		end := strings.Index(stmt[i:], "}}")
+		if end < 0 {
+			return "", nil, fmt.Errorf("cypher: unterminated placeholder in %q", stmt)
+		}
+		placeholder := strings.TrimSpace(stmt[i+2 : i+end])
+		name, ok := identifiers[placeholder]
+		if !ok {
+			return "", nil, fmt.Errorf("cypher: no identifier for placeholder %q", placeholder)
+		}
+		escaped, err := Escape(name)
+		if err != nil {
+			return "", nil, err
+		}
+		b.WriteString(escaped)
+		i += end + 2
+	}
+
+	copied := make(neox.Args, len(args))
+	for k, v := range args {
+		copied[k] = v
+	}
+	return b.String(), copied, nil
+}
+
+// scanner tracks whether the runes of a statement are part of a quoted
+// string or identifier
+type scanner struct {
+	quote   rune
+	escaped bool
+}
+
+// quoted reports whether r is part of a quoted string or identifier,
+// including its quotes
+func (s *scanner) quoted(r rune) bool {
+	switch {
+	case s.escaped:
+		s.escaped = false
+	case s.quote != 0 && s.quote != '`' && r == '\\':
+		s.escaped = true
+	case s.quote != 0:
+		if r == s.quote {
+			s.quote = 0
+		}
+	case r == '\'' || r == '"' || r == '`':
+		s.quote = r
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,123 @@
+package cypher
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/syllabix/neox"
+)
+
+func TestEscape(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{name: "User", want: "User"},
+		{name: "_private2", want: "_private2"},
+		{name: "first name", want: "`first name`"},
+		{name: "2fa", want: "`2fa`"},
+		{name: "Üser", want: "`Üser`"},
+		{name: "User`) detach delete (n", want: "`User``) detach delete (n`"},
+		{name: "", wantErr: true},
+		{name: "User\\u0060", wantErr: true},
+		{name: "User\x00", wantErr: true},
+		{name: "User\nName", wantErr: true},
+		{name: "\xff", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Escape(tt.name)
+			if (err != nil) != tt.wantErr || tt.wantErr && !errors.Is(err, ErrInvalidIdentifier) {
+				t.Fatalf("Escape() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Escape() got = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("Should join labels", func(t *testing.T) {
+		got, err := Labels("User", "Team Lead")
+		if err != nil || got != ":User:`Team Lead`" {
+			t.Errorf("Labels() got = %s, %v", got, err)
+		}
+		if _, err := Labels("User", ""); !errors.Is(err, ErrInvalidIdentifier) {
+			t.Errorf("Labels() error = %v, want %v", err, ErrInvalidIdentifier)
+		}
+	})
+
+	t.Run("Should reject invalid props keys", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Merge() did not panic")
+			}
+		}()
+		Merge("(u:User ?)", Props{"id\\u0060": 1})
+	})
+}
+
+func TestTemplate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		stmt        string
+		identifiers Identifiers
+		want        string
+		wantErr     error
+	}{
+		{
+			name:        "Should substitute escaped identifiers",
+			stmt:        "match (n:{{label}})-[:{{ type }}]->(m) where n.{{key}} = $value return n",
+			identifiers: Identifiers{"label": "Team Lead", "type": "MANAGES", "key": "id"},
+			want:        "match (n:`Team Lead`)-[:MANAGES]->(m) where n.id = $value return n",
+		},
+		{
+			name:        "Should leave quoted placeholders",
+			stmt:        "match (n:{{label}}) where n.name = '{{label}}' return n",
+			identifiers: Identifiers{"label": "User"},
+			want:        "match (n:User) where n.name = '{{label}}' return n",
+		},
+		{
+			name:        "Should reject invalid identifiers",
+			stmt:        "match (n:{{label}}) return n",
+			identifiers: Identifiers{"label": "User\\u0060"},
+			wantErr:     ErrInvalidIdentifier,
+		},
+		{
+			name:    "Should reject missing identifiers",
+			stmt:    "match (n:{{label}}) return n",
+			wantErr: errors.New("cypher: no identifier for placeholder \"label\""),
+		},
+		{
+			name:    "Should reject unterminated placeholders",
+			stmt:    "match (n:{{label) return n",
+			wantErr: errors.New("cypher: unterminated placeholder in \"match (n:{{label) return n\""),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := neox.Args{"value": 1}
+			got, gotArgs, err := Template(tt.stmt, tt.identifiers, args)
+			if tt.wantErr != nil {
+				if err == nil || !errors.Is(err, tt.wantErr) && err.Error() != tt.wantErr.Error() {
+					t.Errorf("Template() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Template() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Template() got = %s, want %s", got, tt.want)
+			}
+			if !reflect.DeepEqual(gotArgs, args) {
+				t.Errorf("Template() args = %v, want %v", gotArgs, args)
+			}
+		})
+	}
+}
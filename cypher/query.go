@@ -0,0 +1,250 @@
+package cypher
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/syllabix/neox"
+)
+
+// Props are the properties of a node or relationship pattern. Bound to a placeholder
+// they are written as a map literal, with a parameter for each value, which is what
+// patterns in match, merge and create clauses require
+//
+//	cypher.Merge("(u:User ?)", cypher.Props{"id": id})
+//	// merge (u:User {id: $p1})
+type Props map[string]interface{}
+
+// clause is a keyword followed by its items
+type clause struct {
+	keyword string
+	items   []string
+	sep     string
+}
+
+// Query is a cypher statement built clause by clause. Clauses are written in the
+// order they are added, and every value bound to a ? placeholder is passed as a
+// parameter with a generated name. A Query is not safe for concurrent use
+//
+//	q := cypher.Match("(u:User)").
+//		Where("u.age >= ?", 18).
+//		Return("u.name as user_name").
+//		OrderBy("u.name").
+//		Limit(10)
+//
+//	res, err := session.Runx(q.Build())
+type Query struct {
+	clauses []clause
+	args    neox.Args
+}
+
+// Match starts a query with a match clause
+func Match(pattern string, values ...interface{}) *Query {
+	return new(Query).Match(pattern, values...)
+}
+
+// OptionalMatch starts a query with an optional match clause
+func OptionalMatch(pattern string, values ...interface{}) *Query {
+	return new(Query).OptionalMatch(pattern, values...)
+}
+
+// Merge starts a query with a merge clause
+func Merge(pattern string, values ...interface{}) *Query {
+	return new(Query).Merge(pattern, values...)
+}
+
+// Create starts a query with a create clause
+func Create(pattern string, values ...interface{}) *Query {
+	return new(Query).Create(pattern, values...)
+}
+
+// Unwind starts a query with an unwind clause
+func Unwind(expr string, values ...interface{}) *Query {
+	return new(Query).Unwind(expr, values...)
+}
+
+// Match adds a match clause
+func (q *Query) Match(pattern string, values ...interface{}) *Query {
+	return q.add("match", q.bind(pattern, values), ", ", false)
+}
+
+// OptionalMatch adds an optional match clause
+func (q *Query) OptionalMatch(pattern string, values ...interface{}) *Query {
+	return q.add("optional match", q.bind(pattern, values), ", ", false)
+}
+
+// Where adds a predicate to the preceding match, optional match or with clause.
+// Predicates added one after another are combined with and
+func (q *Query) Where(predicate string, values ...interface{}) *Query {
+	return q.add("where", q.bind(predicate, values), " and ", true)
+}
+
+// With adds a with clause projecting the provided items
+func (q *Query) With(items ...string) *Query {
+	return q.add("with", strings.Join(items, ", "), ", ", false)
+}
+
+// Return adds a return clause projecting the provided items
+func (q *Query) Return(items ...string) *Query {
+	return q.add("return", strings.Join(items, ", "), ", ", false)
+}
+
+// OrderBy adds an order by clause sorting on the provided items, ie: u.name desc
+func (q *Query) OrderBy(items ...string) *Query {
+	return q.add("order by", strings.Join(items, ", "), ", ", true)
+}
+
+// Skip adds a skip clause, passing the count as a parameter
+func (q *Query) Skip(n int) *Query {
+	return q.add("skip", q.param(int64(n)), "", false)
+}
+
+// Limit adds a limit clause, passing the count as a parameter
+func (q *Query) Limit(n int) *Query {
+	return q.add("limit", q.param(int64(n)), "", false)
+}
+
+// Merge adds a merge clause
+func (q *Query) Merge(pattern string, values ...interface{}) *Query {
+	return q.add("merge", q.bind(pattern, values), "", false)
+}
+
+// OnCreateSet adds assignments made when the preceding merge creates its pattern
+func (q *Query) OnCreateSet(assignment string, values ...interface{}) *Query {
+	return q.add("on create set", q.bind(assignment, values), ", ", true)
+}
+
+// OnMatchSet adds assignments made when the preceding merge matches its pattern
+func (q *Query) OnMatchSet(assignment string, values ...interface{}) *Query {
+	return q.add("on match set", q.bind(assignment, values), ", ", true)
+}
+
+// Create adds a create clause
+func (q *Query) Create(pattern string, values ...interface{}) *Query {
+	return q.add("create", q.bind(pattern, values), ", ", false)
+}
+
+// Set adds an assignment, ie: u.name = ?. Assignments added one after another
+// are combined in a single set clause
+func (q *Query) Set(assignment string, values ...interface{}) *Query {
+	return q.add("set", q.bind(assignment, values), ", ", true)
+}
+
+// Delete adds a delete clause for the provided variables
+func (q *Query) Delete(variables ...string) *Query {
+	return q.add("delete", strings.Join(variables, ", "), ", ", false)
+}
+
+// DetachDelete adds a detach delete clause for the provided variables
+func (q *Query) DetachDelete(variables ...string) *Query {
+	return q.add("detach delete", strings.Join(variables, ", "), ", ", false)
+}
+
+// Unwind adds an unwind clause, ie: ? as row
+func (q *Query) Unwind(expr string, values ...interface{}) *Query {
+	return q.add("unwind", q.bind(expr, values), "", false)
+}
+
+// Build returns the cypher statement and its args, ready to be passed to Session.Runx
+func (q *Query) Build() (string, neox.Args) {
+	args := make(neox.Args, len(q.args))
+	for k, v := range q.args {
+		args[k] = v
+	}
+	return q.String(), args
+}
+
+// String returns the cypher statement
+func (q *Query) String() string {
+	var b strings.Builder
+	for i, c := range q.clauses {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(c.keyword)
+		b.WriteByte(' ')
+		items := c.items
+		if c.keyword == "where" && len(items) > 1 {
+			items = make([]string, len(c.items))
+			for i, item := range c.items {
+				items[i] = "(" + item + ")"
+			}
+		}
+		b.WriteString(strings.Join(items, c.sep))
+	}
+	return b.String()
+}
+
+// add appends an item to the last clause when it has the same keyword and
+// merge is set, otherwise it appends a new clause
+func (q *Query) add(keyword, item, sep string, merge bool) *Query {
+	if n := len(q.clauses); merge && n > 0 && q.clauses[n-1].keyword == keyword {
+		q.clauses[n-1].items = append(q.clauses[n-1].items, item)
+		return q
+	}
+	q.clauses = append(q.clauses, clause{keyword: keyword, items: []string{item}, sep: sep})
+	return q
+}
+
+// param adds a parameter holding the value and returns its placeholder
+func (q *Query) param(value interface{}) string {
+	if q.args == nil {
+		q.args = make(neox.Args)
+	}
+	name := "p" + strconv.Itoa(len(q.args)+1)
+	q.args[name] = value
+	return "$" + name
+}
+
+// bind replaces the ? placeholders of expr outside of quoted strings and
+// identifiers with parameters holding the values. It panics when the number of
+// placeholders does not match the number of values, as that is a programming error
+func (q *Query) bind(expr string, values []interface{}) string {
+	var (
+		b    strings.Builder
+		scan scanner
+		n    int
+	)
+	for _, r := range expr {
+		if !scan.quoted(r) && r == '?' {
+			if n < len(values) {
+				b.WriteString(q.value(values[n]))
+			}
+			n++
+			continue
+		}
+		b.WriteRune(r)
+	}
+	if n != len(values) {
+		panic(fmt.Sprintf("cypher: %q has %d placeholders, got %d values", expr, n, len(values)))
+	}
+	return b.String()
+}
+
+// value returns the placeholder of a parameter holding v, or a map literal
+// of parameters when v holds Props. Keys of Props that are not valid identifiers
+// cause a panic, they are expected to be written in code rather than received
+func (q *Query) value(v interface{}) string {
+	props, ok := v.(Props)
+	if !ok {
+		return q.param(v)
+	}
+
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	entries := make([]string, len(keys))
+	for i, k := range keys {
+		key, err := Escape(k)
+		if err != nil {
+			panic("cypher: " + err.Error())
+		}
+		entries[i] = key + ": " + q.param(props[k])
+	}
+	return "{" + strings.Join(entries, ", ") + "}"
+}
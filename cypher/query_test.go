@@ -0,0 +1,110 @@
+package cypher
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+	"github.com/syllabix/neox"
+	"github.com/syllabix/neox/neoxtest"
+)
+
+func TestQuery_Build(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		query    *Query
+		want     string
+		wantArgs neox.Args
+	}{
+		{
+			name: "Should build match queries",
+			query: Match("(u:User)").
+				OptionalMatch("(u)-[:FOLLOWS]->(f:User)").
+				Where("u.name starts with ?", "Yo").
+				Where("u.age >= ? or u.admin", 18).
+				With("u", "count(f) as follows").
+				Where("follows > ?", 2).
+				Return("u.name as user_name", "follows").
+				OrderBy("follows desc", "u.name").
+				Skip(20).
+				Limit(10),
+			want: "match (u:User) optional match (u)-[:FOLLOWS]->(f:User) " +
+				"where (u.name starts with $p1) and (u.age >= $p2 or u.admin) " +
+				"with u, count(f) as follows where follows > $p3 " +
+				"return u.name as user_name, follows order by follows desc, u.name skip $p4 limit $p5",
+			wantArgs: neox.Args{"p1": "Yo", "p2": 18, "p3": 2, "p4": int64(20), "p5": int64(10)},
+		},
+		{
+			name: "Should write props as map literals",
+			query: Merge("(u:User ?)", Props{"id": 7, "e-mail": "ya@cool.com"}).
+				OnCreateSet("u.created = timestamp()").
+				OnMatchSet("u.seen = ?", 3).
+				OnMatchSet("u += ?", map[string]interface{}{"name": "Yolanda"}),
+			want:     "merge (u:User {`e-mail`: $p1, id: $p2}) on create set u.created = timestamp() on match set u.seen = $p3, u += $p4",
+			wantArgs: neox.Args{"p1": "ya@cool.com", "p2": 7, "p3": 3, "p4": map[string]interface{}{"name": "Yolanda"}},
+		},
+		{
+			name: "Should build write queries",
+			query: Unwind("? as row", []interface{}{1, 2}).
+				Match("(u:User {id: row})").
+				Set("u.name = ?", "Jordan").
+				Set("u:Admin").
+				Create("(u)-[:OWNS]->(:Account ?)", Props{"active": true}).
+				With("u").
+				Match("(u)-[r:FOLLOWS]->()").
+				Delete("r").
+				DetachDelete("u"),
+			want: "unwind $p1 as row match (u:User {id: row}) set u.name = $p2, u:Admin " +
+				"create (u)-[:OWNS]->(:Account {active: $p3}) with u match (u)-[r:FOLLOWS]->() delete r detach delete u",
+			wantArgs: neox.Args{"p1": []interface{}{1, 2}, "p2": "Jordan", "p3": true},
+		},
+		{
+			name:     "Should ignore placeholders in strings and identifiers",
+			query:    Match("(u:`User?`)").Where("u.name = 'who\\'s?' and u.bio <> \"why?\" and u.id = ?", 1).Return("u"),
+			want:     "match (u:`User?`) where u.name = 'who\\'s?' and u.bio <> \"why?\" and u.id = $p1 return u",
+			wantArgs: neox.Args{"p1": 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, args := tt.query.Build()
+			if got != tt.want {
+				t.Errorf("Query.Build() got = %s, want %s", got, tt.want)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("Query.Build() args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+
+	t.Run("Should panic on mismatched placeholders", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Query.Where() did not panic")
+			}
+		}()
+		Match("(u:User)").Where("u.id = ? or u.id = ?", 1)
+	})
+}
+
+func TestQuery_Runx(t *testing.T) {
+	t.Parallel()
+
+	fake := neoxtest.NewDriver()
+	fake.On("match (u:User) where u.id = $p1 return u.name as user_name").
+		WithArgs(neox.Args{"p1": 7}).
+		Return([]string{"user_name"}, []interface{}{"Yolanda Erasmus"})
+
+	session, _ := fake.Neox().Sessionx(neo4j.AccessModeRead)
+	defer session.Close()
+
+	res, err := session.Runx(Match("(u:User)").Where("u.id = ?", 7).Return("u.name as user_name").Build())
+	if err != nil {
+		t.Fatalf("Session.Runx() error = %v", err)
+	}
+	if !res.Next() || res.Record().GetByIndex(0) != "Yolanda Erasmus" {
+		t.Errorf("Session.Runx() did not return the expected record")
+	}
+}
@@ -6,16 +6,36 @@ import "github.com/neo4j/neo4j-go-driver/neo4j"
 // to a neo4j server or cluster. It's safe for concurrent use.
 type Driver struct {
 	neo4j.Driver
+	instr instrumentation
+}
+
+// Use registers hooks that observe every statement run through the sessions
+// returned by Sessionx. Hooks are notified in the order they were registered.
+// Use is not safe for concurrent use and should be called while setting up the driver
+func (d *Driver) Use(hooks ...Hook) {
+	d.instr.hooks = append(d.instr.hooks, hooks...)
+}
+
+// Redact registers a Redactor that is applied to the args of every statement
+// before they are handed to hooks. Redact is not safe for concurrent use and should
+// be called while setting up the driver
+func (d *Driver) Redact(redactor Redactor) {
+	d.instr.redact = redactor
 }
 
 // Sessionx is an extension method that returns an instance of a neox.Session
 func (d *Driver) Sessionx(accessMode neo4j.AccessMode, bookmarks ...string) (*Session, error) {
 	s, err := d.Session(accessMode, bookmarks...)
 	if err != nil {
-		return nil, err
+		return nil, ClassifyError(err)
 	}
 
-	return &Session{s}, nil
+	d.instr.sessionOpened(accessMode)
+	return &Session{
+		Session: s,
+		mode:    accessMode,
+		instr:   d.instr,
+	}, nil
 }
 
 // NewDriver tries to construct an instance of a neox.Driver, returning a non nil error if something
@@ -23,8 +43,8 @@ func (d *Driver) Sessionx(accessMode neo4j.AccessMode, bookmarks ...string) (*Se
 func NewDriver(target string, auth neo4j.AuthToken, configurers ...func(*neo4j.Config)) (*Driver, error) {
 	d, err := neo4j.NewDriver(target, auth, configurers...)
 	if err != nil {
-		return nil, err
+		return nil, ClassifyError(err)
 	}
 
-	return &Driver{d}, nil
+	return &Driver{Driver: d}, nil
 }
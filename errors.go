@@ -0,0 +1,241 @@
+package neox
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+	"github.com/syllabix/neox/bolt"
+)
+
+// neo4j status codes that are given a dedicated error value
+const (
+	codeConstraintViolation = "Neo.ClientError.Schema.ConstraintValidationFailed"
+	codeSyntax              = "Neo.ClientError.Statement.SyntaxError"
+	codeDeadlock            = "Neo.TransientError.Transaction.DeadlockDetected"
+	codeNotALeader          = "Neo.ClientError.Cluster.NotALeader"
+	codeSecurityPrefix      = "Neo.ClientError.Security."
+)
+
+var (
+	// ErrConstraintViolation is matched by errors caused by a statement violating
+	// a schema constraint, ie: a uniqueness or property existence constraint
+	ErrConstraintViolation = errors.New("constraint validation failed")
+
+	// ErrSyntax is matched by errors caused by invalid cypher
+	ErrSyntax = errors.New("invalid cypher syntax")
+
+	// ErrDeadlock is matched by errors caused by the server detecting a deadlock
+	// between concurrent transactions
+	ErrDeadlock = errors.New("deadlock detected")
+
+	// ErrNotALeader is matched by errors caused by sending a write to a cluster member
+	// that is not the leader
+	ErrNotALeader = errors.New("cluster member is not a leader")
+
+	// ErrAuth is matched by errors caused by failed authentication or authorization
+	ErrAuth = errors.New("authentication failed")
+
+	// ErrServiceUnavailable is matched by errors caused by the server or cluster
+	// not being reachable
+	ErrServiceUnavailable = errors.New("service unavailable")
+
+	// ErrTransient is matched by all errors that neo4j reports as transient,
+	// meaning the failed operation may succeed if retried
+	ErrTransient = errors.New("transient failure")
+)
+
+// databaseError mirrors the failures reported by the server through the driver
+type databaseError interface {
+	Classification() string
+	Code() string
+	Message() string
+}
+
+// connectorError mirrors the failures raised by the driver's connector
+type connectorError interface {
+	State() int
+	Code() int
+	Context() string
+	Description() string
+}
+
+// Error is a neo4j failure classified by neox. It exposes the neo4j status code
+// reported by the server, when there is one, and can be matched against the package
+// level error values using errors.Is
+type Error struct {
+	// Code is the neo4j status code, ie: Neo.ClientError.Statement.SyntaxError.
+	// It is empty for failures that did not originate from the server
+	Code string
+
+	// Classification is the classification part of the status code, ie: ClientError
+	Classification string
+
+	// Message is the failure message
+	Message string
+
+	kind      error
+	transient bool
+	cause     error
+}
+
+// Error returns the message of the underlying driver error
+func (e *Error) Error() string {
+	return e.cause.Error()
+}
+
+// Unwrap returns the underlying driver error
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is reports whether the error matches the provided target
+func (e *Error) Is(target error) bool {
+	if target == nil {
+		return false
+	}
+	if target == ErrTransient {
+		return e.transient
+	}
+	return target == e.kind
+}
+
+// ConstraintError is returned when a statement violates a schema constraint.
+// The label, property and conflicting value are extracted from the server message
+// when it can be parsed, otherwise they are left empty. For node key constraints
+// spanning several properties only the first property and its value are extracted
+type ConstraintError struct {
+	// Err is the classified failure
+	Err *Error
+
+	// Label is the label the violated constraint is defined on
+	Label string
+
+	// Property is the property key the violated constraint is defined on
+	Property string
+
+	// Value is the conflicting value as rendered by the server, string values
+	// are unquoted
+	Value string
+
+	// Unique is true when the violation was caused by a node that already exists
+	// with the same value, rather than a missing property
+	Unique bool
+}
+
+// Error returns the message of the underlying driver error
+func (e *ConstraintError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the classified neox.Error
+func (e *ConstraintError) Unwrap() error {
+	return e.Err
+}
+
+// ClassifyError wraps errors reported by neo4j into a neox.Error, or a neox.ConstraintError
+// for constraint violations. Errors that did not originate from neo4j, or have already
+// been classified, are returned as is
+func ClassifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var (
+		classified *Error
+		constraint *ConstraintError
+	)
+	if errors.As(err, &classified) || errors.As(err, &constraint) {
+		return err
+	}
+
+	switch failure := err.(type) {
+	case databaseError:
+		return classifyDatabaseError(failure, err)
+	case connectorError:
+		return classifyConnectorError(err)
+	}
+
+	if bolt.IsConnectivity(err) || errors.Is(err, bolt.ErrPoolFull) {
+		return &Error{
+			Message: err.Error(),
+			kind:    ErrServiceUnavailable,
+			cause:   err,
+		}
+	}
+
+	return err
+}
+
+func classifyDatabaseError(failure databaseError, err error) error {
+	e := &Error{
+		Code:           failure.Code(),
+		Classification: failure.Classification(),
+		Message:        failure.Message(),
+		transient:      neo4j.IsTransientError(err),
+		cause:          err,
+	}
+
+	switch {
+	case e.Code == codeConstraintViolation:
+		e.kind = ErrConstraintViolation
+		return newConstraintError(e)
+	case e.Code == codeSyntax:
+		e.kind = ErrSyntax
+	case e.Code == codeDeadlock:
+		e.kind = ErrDeadlock
+	case e.Code == codeNotALeader:
+		e.kind = ErrNotALeader
+	case strings.HasPrefix(e.Code, codeSecurityPrefix):
+		e.kind = ErrAuth
+	}
+
+	return e
+}
+
+func classifyConnectorError(err error) error {
+	e := &Error{
+		Message: err.Error(),
+		cause:   err,
+	}
+
+	switch {
+	case neo4j.IsServiceUnavailable(err):
+		e.kind = ErrServiceUnavailable
+	case neo4j.IsAuthenticationError(err):
+		e.kind = ErrAuth
+	default:
+		return err
+	}
+
+	return e
+}
+
+var (
+	constraintLabel    = regexp.MustCompile("label `([^`]+)`|label (\\w+)")
+	constraintProperty = regexp.MustCompile("propert(?:y|ies) `([^`]+)`|property \"([^\"]+)\"")
+	constraintValue    = regexp.MustCompile("propert(?:y|ies) `[^`]+` = (?:'(.*?)'(?:, `|$)|([^,]+))|property \"[^\"]+\"=\\[(.*)\\]")
+)
+
+func newConstraintError(e *Error) *ConstraintError {
+	return &ConstraintError{
+		Err:      e,
+		Label:    firstSubmatch(constraintLabel, e.Message),
+		Property: firstSubmatch(constraintProperty, e.Message),
+		Value:    firstSubmatch(constraintValue, e.Message),
+		Unique:   strings.Contains(e.Message, "already exists"),
+	}
+}
+
+// firstSubmatch returns the first non empty capture group of the
+// leftmost match of expr in s
+func firstSubmatch(expr *regexp.Regexp, s string) string {
+	match := expr.FindStringSubmatch(s)
+	for i := 1; i < len(match); i++ {
+		if match[i] != "" {
+			return match[i]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,231 @@
+package neox
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// mocked gobolt.DatabaseError
+type mdberr struct {
+	code    string
+	message string
+}
+
+func (m *mdberr) BoltError() bool { return true }
+
+func (m *mdberr) Classification() string {
+	if parts := strings.Split(m.code, "."); len(parts) >= 2 {
+		return parts[1]
+	}
+	return ""
+}
+
+func (m *mdberr) Code() string    { return m.code }
+func (m *mdberr) Message() string { return m.message }
+func (m *mdberr) Error() string {
+	return fmt.Sprintf("database returned error [%s]: %s", m.code, m.message)
+}
+
+// mocked gobolt.ConnectorError
+type mconnerr struct {
+	code int
+}
+
+func (m *mconnerr) BoltError() bool     { return true }
+func (m *mconnerr) State() int          { return 0 }
+func (m *mconnerr) Code() int           { return m.code }
+func (m *mconnerr) Context() string     { return "" }
+func (m *mconnerr) Description() string { return "" }
+func (m *mconnerr) Error() string       { return fmt.Sprintf("error: [%d]", m.code) }
+
+func TestClassifyError(t *testing.T) {
+	t.Parallel()
+
+	plain := errors.New("something else")
+
+	tests := []struct {
+		name      string
+		err       error
+		want      error
+		transient bool
+		code      string
+	}{
+		{
+			name: "Should classify syntax errors",
+			err:  &mdberr{"Neo.ClientError.Statement.SyntaxError", "Invalid input 'X'"},
+			want: ErrSyntax,
+			code: "Neo.ClientError.Statement.SyntaxError",
+		},
+		{
+			name:      "Should classify deadlocks as transient",
+			err:       &mdberr{"Neo.TransientError.Transaction.DeadlockDetected", "ForsetiClient[3] can't acquire"},
+			want:      ErrDeadlock,
+			transient: true,
+			code:      "Neo.TransientError.Transaction.DeadlockDetected",
+		},
+		{
+			name: "Should classify writes sent to a follower",
+			err:  &mdberr{"Neo.ClientError.Cluster.NotALeader", "No write operations are allowed"},
+			want: ErrNotALeader,
+			code: "Neo.ClientError.Cluster.NotALeader",
+		},
+		{
+			name: "Should classify authentication failures",
+			err:  &mdberr{"Neo.ClientError.Security.Unauthorized", "The client is unauthorized"},
+			want: ErrAuth,
+			code: "Neo.ClientError.Security.Unauthorized",
+		},
+		{
+			name: "Should classify constraint violations",
+			err:  &mdberr{"Neo.ClientError.Schema.ConstraintValidationFailed", "Node(0) already exists"},
+			want: ErrConstraintViolation,
+			code: "Neo.ClientError.Schema.ConstraintValidationFailed",
+		},
+		{
+			name:      "Should classify other transient errors",
+			err:       &mdberr{"Neo.TransientError.General.DatabaseUnavailable", "unavailable"},
+			want:      ErrTransient,
+			transient: true,
+			code:      "Neo.TransientError.General.DatabaseUnavailable",
+		},
+		{
+			name: "Should classify connection failures",
+			err:  &mconnerr{code: 11},
+			want: ErrServiceUnavailable,
+		},
+		{
+			name: "Should classify connector permission failures",
+			err:  &mconnerr{code: 7},
+			want: ErrAuth,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ClassifyError(tt.err)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("ClassifyError() = %v, want match for %v", err, tt.want)
+			}
+			if errors.Is(err, ErrTransient) != tt.transient {
+				t.Errorf("ClassifyError() transient = %v, want %v", !tt.transient, tt.transient)
+			}
+			if errors.Unwrap(err) == nil {
+				t.Errorf("ClassifyError() did not retain the driver error")
+			}
+
+			var classified *Error
+			if !errors.As(err, &classified) {
+				t.Fatalf("ClassifyError() = %T, want a *neox.Error", err)
+			}
+			if classified.Code != tt.code {
+				t.Errorf("ClassifyError() code = %v, want %v", classified.Code, tt.code)
+			}
+			if err.Error() != tt.err.Error() {
+				t.Errorf("ClassifyError() message = %v, want %v", err.Error(), tt.err.Error())
+			}
+		})
+	}
+
+	t.Run("Should return unrelated errors as is", func(t *testing.T) {
+		if err := ClassifyError(plain); err != plain {
+			t.Errorf("ClassifyError() = %v, want %v", err, plain)
+		}
+		if err := ClassifyError(nil); err != nil {
+			t.Errorf("ClassifyError() = %v, want nil", err)
+		}
+	})
+
+	t.Run("Should not classify an error twice", func(t *testing.T) {
+		err := ClassifyError(&mdberr{"Neo.ClientError.Statement.SyntaxError", "Invalid input"})
+		if again := ClassifyError(err); again != err {
+			t.Errorf("ClassifyError() = %#v, want %#v", again, err)
+		}
+	})
+}
+
+func TestClassifyError_Constraint(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		message      string
+		wantLabel    string
+		wantProperty string
+		wantValue    string
+		wantUnique   bool
+	}{
+		{
+			name:         "Should parse uniqueness violations",
+			message:      "Node(12) already exists with label `User` and property `email` = 'ya@cool.com'",
+			wantLabel:    "User",
+			wantProperty: "email",
+			wantValue:    "ya@cool.com",
+			wantUnique:   true,
+		},
+		{
+			name:         "Should parse numeric values",
+			message:      "Node(12) already exists with label `Account` and property `number` = 1234",
+			wantLabel:    "Account",
+			wantProperty: "number",
+			wantValue:    "1234",
+			wantUnique:   true,
+		},
+		{
+			name:         "Should keep quotes that are part of the value",
+			message:      "Node(12) already exists with label `User` and property `name` = 'O'Hara, Yolanda'",
+			wantLabel:    "User",
+			wantProperty: "name",
+			wantValue:    "O'Hara, Yolanda",
+			wantUnique:   true,
+		},
+		{
+			name:         "Should parse node key violations",
+			message:      "Node(4) already exists with label `Person` and properties `first` = 'Yolanda', `last` = 'Erasmus'",
+			wantLabel:    "Person",
+			wantProperty: "first",
+			wantValue:    "Yolanda",
+			wantUnique:   true,
+		},
+		{
+			name:         "Should parse property existence violations",
+			message:      "Node(3) with label `User` must have the property `username`",
+			wantLabel:    "User",
+			wantProperty: "username",
+		},
+		{
+			name:         "Should parse the legacy message format",
+			message:      "Node 7 already exists with label Account and property \"number\"=[12]",
+			wantLabel:    "Account",
+			wantProperty: "number",
+			wantValue:    "12",
+			wantUnique:   true,
+		},
+		{
+			name:    "Should leave unparseable details empty",
+			message: "the constraint was violated",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ClassifyError(&mdberr{"Neo.ClientError.Schema.ConstraintValidationFailed", tt.message})
+
+			var constraint *ConstraintError
+			if !errors.As(err, &constraint) {
+				t.Fatalf("ClassifyError() = %T, want a *neox.ConstraintError", err)
+			}
+			if constraint.Label != tt.wantLabel {
+				t.Errorf("ConstraintError.Label = %v, want %v", constraint.Label, tt.wantLabel)
+			}
+			if constraint.Property != tt.wantProperty {
+				t.Errorf("ConstraintError.Property = %v, want %v", constraint.Property, tt.wantProperty)
+			}
+			if constraint.Value != tt.wantValue {
+				t.Errorf("ConstraintError.Value = %v, want %v", constraint.Value, tt.wantValue)
+			}
+			if constraint.Unique != tt.wantUnique {
+				t.Errorf("ConstraintError.Unique = %v, want %v", constraint.Unique, tt.wantUnique)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package neox
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// WriteCSV writes the remaining records of the result to w as CSV, with the keys of the
+// result as the header. Strings, numbers and booleans are written as is, temporal values
+// in their ISO 8601 form, and nodes, relationships, paths, points, lists and maps as JSON.
+// It returns the number of records written, and fails on a record without one value per key
+func (r *Result) WriteCSV(w io.Writer) (int, error) {
+	keys, err := r.Keys()
+	if err != nil {
+		return 0, ClassifyError(err)
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(keys); err != nil {
+		return 0, err
+	}
+	n := 0
+	for r.Next() {
+		values, err := r.exportValues(keys, n)
+		if err != nil {
+			return n, err
+		}
+		row := make([]string, len(keys))
+		for i, v := range values {
+			cell, err := csvValue(v)
+			if err != nil {
+				return n, err
+			}
+			row[i] = cell
+		}
+		if err := cw.Write(row); err != nil {
+			return n, err
+		}
+		n++
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return n, err
+	}
+	return n, r.Err()
+}
+
+// WriteJSONL writes the remaining records of the result to w as JSON Lines, each record
+// as an object holding its values by key, in the order of the keys of the result. Values
+// are rendered as described by WriteJSON. It returns the number of records written
+func (r *Result) WriteJSONL(w io.Writer) (int, error) {
+	return r.writeJSON(w, nil, []byte("\n"), nil)
+}
+
+// WriteJSON writes the remaining records of the result to w as a JSON array of objects
+// holding their values by key, converted by ToJSONValue. It returns the number of records written
+func (r *Result) WriteJSON(w io.Writer) (int, error) {
+	return r.writeJSON(w, []byte("["), []byte(","), []byte("]\n"))
+}
+
+// writeJSON writes the records as JSON objects, opening with the prefix, separating records
+// with the separator, or ending them with it when there is no prefix, and closing with the suffix
+func (r *Result) writeJSON(w io.Writer, prefix, sep, suffix []byte) (int, error) {
+	keys, err := r.Keys()
+	if err != nil {
+		return 0, ClassifyError(err)
+	}
+	names := make([][]byte, len(keys))
+	for i, k := range keys {
+		if names[i], err = json.Marshal(k); err != nil {
+			return 0, err
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.Write(prefix)
+	n := 0
+	var buf bytes.Buffer
+	for r.Next() {
+		values, err := r.exportValues(keys, n)
+		if err != nil {
+			bw.Flush()
+			return n, err
+		}
+		buf.Reset()
+		buf.WriteByte('{')
+		for i, v := range values {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			b, err := json.Marshal(ToJSONValue(v))
+			if err != nil {
+				return n, err
+			}
+			buf.Write(names[i])
+			buf.WriteByte(':')
+			buf.Write(b)
+		}
+		buf.WriteByte('}')
+
+		if prefix != nil && n > 0 {
+			bw.Write(sep)
+		}
+		bw.Write(buf.Bytes())
+		if prefix == nil {
+			bw.Write(sep)
+		}
+		n++
+	}
+	if err := r.Err(); err != nil {
+		bw.Flush()
+		return n, err
+	}
+	bw.Write(suffix)
+	return n, bw.Flush()
+}
+
+// exportValues returns the values of the current record, the n-th written, failing
+// unless it holds one value per key
+func (r *Result) exportValues(keys []string, n int) ([]interface{}, error) {
+	values := r.Record().Values()
+	if len(values) != len(keys) {
+		return nil, fmt.Errorf("neox: record %d has %d values, want one per key of %v", n+1, len(values), keys)
+	}
+	return values, nil
+}
+
+// csvValue renders a value as a CSV cell
+func csvValue(v interface{}) (string, error) {
+	switch v := ToJSONValue(v).(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	default:
+		b, err := json.Marshal(v)
+		return string(b), err
+	}
+}
@@ -0,0 +1,173 @@
+package neox_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+	"github.com/syllabix/neox"
+	"github.com/syllabix/neox/neoxtest"
+)
+
+func exportResult() *neox.Result {
+	alice := neoxtest.NewNode(1, []string{"Person"}, map[string]interface{}{"name": "Alice"})
+	bob := neoxtest.NewNode(2, []string{"Person"}, map[string]interface{}{"name": "Bob"})
+	knows := neoxtest.NewRelationship(7, 2, 1, "KNOWS", map[string]interface{}{"since": int64(2019)})
+	keys := []string{"name", "age", "score", "person", "knows", "path", "born", "seen", "point", "tags"}
+
+	return neoxtest.NewResultx(
+		neoxtest.NewRecord(keys, []interface{}{
+			"Alice, \"Al\"", int64(42), 1.5, alice, knows,
+			neoxtest.NewPath([]neo4j.Node{alice, bob}, []neo4j.Relationship{knows}),
+			neo4j.DateOf(time.Date(1982, 3, 14, 0, 0, 0, 0, time.UTC)),
+			time.Date(2021, 6, 1, 10, 30, 0, 0, time.UTC),
+			neo4j.NewPoint2D(4326, 18.42, -33.92),
+			[]interface{}{"a", int64(1)},
+		}),
+		neoxtest.NewRecord(keys, []interface{}{
+			"Bob", nil, math.NaN(), bob, nil, nil,
+			nil, nil, neo4j.NewPoint3D(9157, 1, 2, 3), []interface{}{},
+		}),
+	)
+}
+
+func TestResult_WriteCSV(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	n, err := exportResult().WriteCSV(&buf)
+	if err != nil {
+		t.Fatalf("Result.WriteCSV() error = %v", err)
+	}
+	if n != 2 {
+		t.Errorf("Result.WriteCSV() got = %d, want %d", n, 2)
+	}
+
+	want := `name,age,score,person,knows,path,born,seen,point,tags
+"Alice, ""Al""",42,1.5,"{""id"":1,""labels"":[""Person""],""properties"":{""name"":""Alice""}}",` +
+		`"{""end"":1,""id"":7,""properties"":{""since"":2019},""start"":2,""type"":""KNOWS""}",` +
+		`"{""end"":{""id"":2,""labels"":[""Person""],""properties"":{""name"":""Bob""}},` +
+		`""segments"":[{""end"":{""id"":2,""labels"":[""Person""],""properties"":{""name"":""Bob""}},` +
+		`""relationship"":{""end"":1,""id"":7,""properties"":{""since"":2019},""start"":2,""type"":""KNOWS""},` +
+		`""start"":{""id"":1,""labels"":[""Person""],""properties"":{""name"":""Alice""}}}],` +
+		`""start"":{""id"":1,""labels"":[""Person""],""properties"":{""name"":""Alice""}}}",` +
+		`1982-03-14,2021-06-01T10:30:00Z,"{""srid"":4326,""x"":18.42,""y"":-33.92}","[""a"",1]"
+Bob,,NaN,"{""id"":2,""labels"":[""Person""],""properties"":{""name"":""Bob""}}",,,,,"{""srid"":9157,""x"":1,""y"":2,""z"":3}",[]
+`
+	if got := buf.String(); got != want {
+		t.Errorf("Result.WriteCSV() got = %v, want %v", got, want)
+	}
+}
+
+func TestResult_Write_Mismatch(t *testing.T) {
+	t.Parallel()
+
+	keys := []string{"name", "age"}
+	tests := []struct {
+		name   string
+		values []interface{}
+		write  func(r *neox.Result, buf *bytes.Buffer) (int, error)
+	}{
+		{
+			name:   "Should fail to write CSV with more values than keys",
+			values: []interface{}{"Bob", int64(7), true},
+			write:  func(r *neox.Result, buf *bytes.Buffer) (int, error) { return r.WriteCSV(buf) },
+		},
+		{
+			name:   "Should fail to write CSV with fewer values than keys",
+			values: []interface{}{"Bob"},
+			write:  func(r *neox.Result, buf *bytes.Buffer) (int, error) { return r.WriteCSV(buf) },
+		},
+		{
+			name:   "Should fail to write JSON with more values than keys",
+			values: []interface{}{"Bob", int64(7), true},
+			write:  func(r *neox.Result, buf *bytes.Buffer) (int, error) { return r.WriteJSON(buf) },
+		},
+		{
+			name:   "Should fail to write JSON Lines with fewer values than keys",
+			values: []interface{}{"Bob"},
+			write:  func(r *neox.Result, buf *bytes.Buffer) (int, error) { return r.WriteJSONL(buf) },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := neoxtest.NewResultx(
+				neoxtest.NewRecord(keys, []interface{}{"Alice", int64(42)}),
+				neoxtest.NewRecord(keys, tt.values),
+			)
+
+			var buf bytes.Buffer
+			n, err := tt.write(res, &buf)
+			if err == nil {
+				t.Fatalf("Result.Write() error = %v, want an error", err)
+			}
+			if n != 1 {
+				t.Errorf("Result.Write() got = %d, want %d", n, 1)
+			}
+		})
+	}
+}
+
+func TestResult_WriteJSON(t *testing.T) {
+	t.Parallel()
+
+	first := `{"name":"Alice, \"Al\"","age":42,"score":1.5,` +
+		`"person":{"id":1,"labels":["Person"],"properties":{"name":"Alice"}},` +
+		`"knows":{"end":1,"id":7,"properties":{"since":2019},"start":2,"type":"KNOWS"},` +
+		`"path":{"end":{"id":2,"labels":["Person"],"properties":{"name":"Bob"}},` +
+		`"segments":[{"end":{"id":2,"labels":["Person"],"properties":{"name":"Bob"}},` +
+		`"relationship":{"end":1,"id":7,"properties":{"since":2019},"start":2,"type":"KNOWS"},` +
+		`"start":{"id":1,"labels":["Person"],"properties":{"name":"Alice"}}}],` +
+		`"start":{"id":1,"labels":["Person"],"properties":{"name":"Alice"}}},` +
+		`"born":"1982-03-14","seen":"2021-06-01T10:30:00Z","point":{"srid":4326,"x":18.42,"y":-33.92},"tags":["a",1]}`
+	second := `{"name":"Bob","age":null,"score":"NaN",` +
+		`"person":{"id":2,"labels":["Person"],"properties":{"name":"Bob"}},` +
+		`"knows":null,"path":null,"born":null,"seen":null,"point":{"srid":9157,"x":1,"y":2,"z":3},"tags":[]}`
+
+	tests := []struct {
+		name  string
+		write func(r *neox.Result, buf *bytes.Buffer) (int, error)
+		want  string
+	}{
+		{
+			name:  "Should write a JSON array",
+			write: func(r *neox.Result, buf *bytes.Buffer) (int, error) { return r.WriteJSON(buf) },
+			want:  "[" + first + "," + second + "]\n",
+		},
+		{
+			name:  "Should write JSON Lines",
+			write: func(r *neox.Result, buf *bytes.Buffer) (int, error) { return r.WriteJSONL(buf) },
+			want:  first + "\n" + second + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			n, err := tt.write(exportResult(), &buf)
+			if err != nil {
+				t.Fatalf("Result.WriteJSON() error = %v", err)
+			}
+			if n != 2 {
+				t.Errorf("Result.WriteJSON() got = %d, want %d", n, 2)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Result.WriteJSON() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("Should write an empty array for empty results", func(t *testing.T) {
+		var buf bytes.Buffer
+		if _, err := neoxtest.NewResultx().WriteJSON(&buf); err != nil {
+			t.Fatalf("Result.WriteJSON() error = %v", err)
+		}
+		var got []interface{}
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil || !reflect.DeepEqual(got, []interface{}{}) {
+			t.Errorf("Result.WriteJSON() got = %v, want []", buf.String())
+		}
+	})
+}
@@ -0,0 +1,38 @@
+package neox
+
+import "github.com/neo4j/neo4j-go-driver/neo4j"
+
+// graphNode is an in-memory neo4j.Node, for nodes that do not come from the driver
+type graphNode struct {
+	id     int64
+	labels []string
+	props  map[string]interface{}
+}
+
+func (n *graphNode) Id() int64                     { return n.id }
+func (n *graphNode) Labels() []string              { return n.labels }
+func (n *graphNode) Props() map[string]interface{} { return n.props }
+
+// graphRelationship is an in-memory neo4j.Relationship
+type graphRelationship struct {
+	id      int64
+	startID int64
+	endID   int64
+	relType string
+	props   map[string]interface{}
+}
+
+func (r *graphRelationship) Id() int64                     { return r.id }
+func (r *graphRelationship) StartId() int64                { return r.startID }
+func (r *graphRelationship) EndId() int64                  { return r.endID }
+func (r *graphRelationship) Type() string                  { return r.relType }
+func (r *graphRelationship) Props() map[string]interface{} { return r.props }
+
+// graphPath is an in-memory neo4j.Path
+type graphPath struct {
+	nodes []neo4j.Node
+	rels  []neo4j.Relationship
+}
+
+func (p *graphPath) Nodes() []neo4j.Node                 { return p.nodes }
+func (p *graphPath) Relationships() []neo4j.Relationship { return p.rels }
@@ -0,0 +1,276 @@
+package neox
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+// Query describes a single cypher statement executed through
+// a neox.Session or neox.Transaction
+type Query struct {
+	// Cypher is the statement that was run
+	Cypher string
+
+	// Args are the arguments of the statement, redacted by the Redactor
+	// registered on the driver. They are shared with the caller and must not be modified
+	Args Args
+
+	// AccessMode is the access mode of the session or transaction the statement ran in
+	AccessMode neo4j.AccessMode
+
+	// Start is the time the statement was sent
+	Start time.Time
+
+	// Duration is the time elapsed between sending the statement and its result
+	// being fully consumed, or the statement failing. It is only set after the run
+	Duration time.Duration
+
+	// Summary is the summary of the result, it is only set after the run and
+	// is nil when the statement failed
+	Summary neo4j.ResultSummary
+
+	// Err is the classified failure of the statement, if any.
+	// It is only set after the run
+	Err error
+}
+
+// A Hook observes the statements executed by the sessions of a neox.Driver.
+//
+// BeforeRun is called before a statement is sent and may return a derived context
+// to carry state through to AfterRun. AfterRun is called once the result of the statement
+// has been fully consumed, or the statement failed. Results that are never consumed
+// through Next or Consume are not reported
+type Hook interface {
+	BeforeRun(ctx context.Context, q *Query) context.Context
+	AfterRun(ctx context.Context, q *Query)
+}
+
+// TransactionInfo describes a unit of work executed through the
+// transaction helpers of a neox.Session
+type TransactionInfo struct {
+	// AccessMode is the access mode of the transaction
+	AccessMode neo4j.AccessMode
+
+	// Start is the time the transaction helper was called
+	Start time.Time
+
+	// Attempts is the number of times the unit of work was run, including
+	// retries performed by the driver. It is only set after the transaction
+	Attempts int
+
+	// Duration is the time elapsed across all attempts. It is only set after the transaction
+	Duration time.Duration
+
+	// Err is the classified failure of the transaction, if any.
+	// It is only set after the transaction
+	Err error
+}
+
+// A TransactionHook is a Hook that is also notified of the transactions run
+// through the transaction helpers of a neox.Session. The context returned by
+// BeforeTransaction is the parent of the contexts passed to the hooks of the statements
+// run within the transaction
+type TransactionHook interface {
+	Hook
+	BeforeTransaction(ctx context.Context, tx *TransactionInfo) context.Context
+	AfterTransaction(ctx context.Context, tx *TransactionInfo)
+}
+
+// A SessionHook is a Hook that is also notified when sessions returned by
+// Sessionx are opened and closed
+type SessionHook interface {
+	Hook
+	SessionOpened(mode neo4j.AccessMode)
+	SessionClosed(mode neo4j.AccessMode)
+}
+
+// Hooks is a Hook built from optional callbacks
+type Hooks struct {
+	Before func(ctx context.Context, q *Query) context.Context
+	After  func(ctx context.Context, q *Query)
+}
+
+// BeforeRun calls the Before callback if set
+func (h Hooks) BeforeRun(ctx context.Context, q *Query) context.Context {
+	if h.Before == nil {
+		return ctx
+	}
+	return h.Before(ctx, q)
+}
+
+// AfterRun calls the After callback if set
+func (h Hooks) AfterRun(ctx context.Context, q *Query) {
+	if h.After != nil {
+		h.After(ctx, q)
+	}
+}
+
+// SlowQuery returns a Hook that calls fn for every statement that
+// took at least as long as the provided threshold to complete
+func SlowQuery(threshold time.Duration, fn func(ctx context.Context, q *Query)) Hook {
+	return Hooks{
+		After: func(ctx context.Context, q *Query) {
+			if q.Duration >= threshold {
+				fn(ctx, q)
+			}
+		},
+	}
+}
+
+// A Redactor returns the args that are safe to hand to hooks
+type Redactor func(Args) Args
+
+const redacted = "[redacted]"
+
+// RedactKeys returns a Redactor that masks the values of the provided keys
+func RedactKeys(keys ...string) Redactor {
+	return func(args Args) Args {
+		if args == nil {
+			return nil
+		}
+		safe := make(Args, len(args))
+		for k, v := range args {
+			safe[k] = v
+		}
+		for _, k := range keys {
+			if _, ok := safe[k]; ok {
+				safe[k] = redacted
+			}
+		}
+		return safe
+	}
+}
+
+// instrumentation holds the hooks registered on a neox.Driver
+type instrumentation struct {
+	hooks  []Hook
+	redact Redactor
+}
+
+// observe notifies the hooks that the provided statement is about to run, returning
+// the run to report the outcome on. It returns nil when there are no hooks to notify
+func (in instrumentation) observe(ctx context.Context, mode neo4j.AccessMode, cypher string, args Args) *run {
+	if len(in.hooks) == 0 {
+		return nil
+	}
+
+	if in.redact != nil {
+		args = in.redact(args)
+	}
+
+	r := &run{
+		hooks: in.hooks,
+		query: Query{
+			Cypher:     cypher,
+			Args:       args,
+			AccessMode: mode,
+			Start:      time.Now(),
+		},
+	}
+	for _, hook := range r.hooks {
+		ctx = hook.BeforeRun(ctx, &r.query)
+	}
+	r.ctx = ctx
+	return r
+}
+
+// observeTransaction notifies the transaction hooks that a transaction is about to start,
+// returning the context to run its statements with and the transaction to report the outcome on.
+// The returned transaction is nil when there are no transaction hooks to notify
+func (in instrumentation) observeTransaction(ctx context.Context, mode neo4j.AccessMode) (context.Context, *txrun) {
+	var hooks []TransactionHook
+	for _, hook := range in.hooks {
+		if txhook, ok := hook.(TransactionHook); ok {
+			hooks = append(hooks, txhook)
+		}
+	}
+	if len(hooks) == 0 {
+		return ctx, nil
+	}
+
+	tx := &txrun{
+		hooks: hooks,
+		info: TransactionInfo{
+			AccessMode: mode,
+			Start:      time.Now(),
+		},
+	}
+	for _, hook := range tx.hooks {
+		ctx = hook.BeforeTransaction(ctx, &tx.info)
+	}
+	tx.ctx = ctx
+	return ctx, tx
+}
+
+// sessionOpened notifies the session hooks that a session was opened
+func (in instrumentation) sessionOpened(mode neo4j.AccessMode) {
+	for _, hook := range in.hooks {
+		if shook, ok := hook.(SessionHook); ok {
+			shook.SessionOpened(mode)
+		}
+	}
+}
+
+// sessionClosed notifies the session hooks that a session was closed
+func (in instrumentation) sessionClosed(mode neo4j.AccessMode) {
+	for _, hook := range in.hooks {
+		if shook, ok := hook.(SessionHook); ok {
+			shook.SessionClosed(mode)
+		}
+	}
+}
+
+// run is a statement observed by hooks
+type run struct {
+	hooks []Hook
+	ctx   context.Context
+	query Query
+	once  sync.Once
+}
+
+// done reports the outcome of the run to the hooks, only
+// the first call has any effect. It is safe to call on a nil run
+func (r *run) done(summary neo4j.ResultSummary, err error) {
+	if r == nil {
+		return
+	}
+	r.once.Do(func() {
+		r.query.Duration = time.Since(r.query.Start)
+		r.query.Summary = summary
+		r.query.Err = err
+		for _, hook := range r.hooks {
+			hook.AfterRun(r.ctx, &r.query)
+		}
+	})
+}
+
+// txrun is a transaction observed by hooks
+type txrun struct {
+	hooks []TransactionHook
+	ctx   context.Context
+	info  TransactionInfo
+}
+
+// attempt records an attempt at running the unit of work of
+// the transaction. It is safe to call on a nil txrun
+func (tx *txrun) attempt() {
+	if tx != nil {
+		tx.info.Attempts++
+	}
+}
+
+// done reports the outcome of the transaction to the hooks.
+// It is safe to call on a nil txrun
+func (tx *txrun) done(err error) {
+	if tx == nil {
+		return
+	}
+	tx.info.Duration = time.Since(tx.info.Start)
+	tx.info.Err = err
+	for _, hook := range tx.hooks {
+		hook.AfterTransaction(tx.ctx, &tx.info)
+	}
+}
@@ -0,0 +1,189 @@
+package neox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+type ctxkey struct{}
+
+func TestSession_Runx_Hooks(t *testing.T) {
+	t.Parallel()
+
+	summary := &msum{available: 2 * time.Millisecond, consumed: 5 * time.Millisecond}
+
+	result := new(mres)
+	result.On("Next").Return(true).Once()
+	result.On("Next").Return(false)
+	result.On("Err").Return(nil)
+	result.On("Summary").Return(summary, nil)
+
+	failure := &mdberr{"Neo.ClientError.Statement.SyntaxError", "Invalid input 'X'"}
+
+	session := new(msess)
+	session.On("Run", "match (u:User {token: $token}) return u", map[string]interface{}(Args{"token": "s3cr3t", "id": 1})).
+		Return(result, nil)
+	session.On("Run", "matchx (u:User) return u", map[string]interface{}(nil)).
+		Return(nil, failure)
+
+	var (
+		before []Query
+		after  []Query
+		seen   []interface{}
+	)
+	d := &Driver{}
+	d.Redact(RedactKeys("token"))
+	d.Use(Hooks{
+		Before: func(ctx context.Context, q *Query) context.Context {
+			before = append(before, *q)
+			return context.WithValue(ctx, ctxkey{}, q.Cypher)
+		},
+		After: func(ctx context.Context, q *Query) {
+			after = append(after, *q)
+			seen = append(seen, ctx.Value(ctxkey{}))
+		},
+	})
+	s := &Session{Session: session, mode: neo4j.AccessModeRead, instr: d.instr}
+
+	res, err := s.Runx("match (u:User {token: $token}) return u", Args{"token": "s3cr3t", "id": 1})
+	if err != nil {
+		t.Fatalf("Session.Runx() error = %v", err)
+	}
+	if len(before) != 1 || len(after) != 0 {
+		t.Fatalf("hooks called before = %d, after = %d, want 1 and 0", len(before), len(after))
+	}
+	for res.Next() {
+	}
+	res.Next()
+	if len(after) != 1 {
+		t.Fatalf("AfterRun called %d times, want 1", len(after))
+	}
+
+	q := after[0]
+	if q.Args["token"] != redacted || q.Args["id"] != 1 {
+		t.Errorf("Query.Args = %v, want token redacted", q.Args)
+	}
+	if q.AccessMode != neo4j.AccessModeRead {
+		t.Errorf("Query.AccessMode = %v, want %v", q.AccessMode, neo4j.AccessModeRead)
+	}
+	if q.Summary != summary || q.Err != nil {
+		t.Errorf("Query.Summary = %v, Query.Err = %v", q.Summary, q.Err)
+	}
+	if seen[0] != q.Cypher {
+		t.Errorf("AfterRun context value = %v, want %v", seen[0], q.Cypher)
+	}
+
+	if _, err := s.Runx("matchx (u:User) return u", nil); !errors.Is(err, ErrSyntax) {
+		t.Fatalf("Session.Runx() error = %v, want %v", err, ErrSyntax)
+	}
+	if len(after) != 2 || !errors.Is(after[1].Err, ErrSyntax) || after[1].Summary != nil {
+		t.Errorf("AfterRun did not receive the classified failure: %+v", after)
+	}
+}
+
+func TestSlowQuery(t *testing.T) {
+	t.Parallel()
+
+	var got []string
+	hook := SlowQuery(time.Second, func(ctx context.Context, q *Query) {
+		got = append(got, q.Cypher)
+	})
+
+	hook.AfterRun(context.Background(), &Query{Cypher: "fast", Duration: time.Millisecond})
+	hook.AfterRun(context.Background(), &Query{Cypher: "slow", Duration: 2 * time.Second})
+
+	if len(got) != 1 || got[0] != "slow" {
+		t.Errorf("SlowQuery() reported %v, want [slow]", got)
+	}
+}
+
+func TestRedactKeys(t *testing.T) {
+	t.Parallel()
+
+	args := Args{"password": "hunter2", "name": "Yolanda"}
+	safe := RedactKeys("password", "missing")(args)
+
+	if safe["password"] != redacted || safe["name"] != "Yolanda" {
+		t.Errorf("RedactKeys() = %v", safe)
+	}
+	if _, ok := safe["missing"]; ok {
+		t.Errorf("RedactKeys() added a missing key: %v", safe)
+	}
+	if args["password"] != "hunter2" {
+		t.Errorf("RedactKeys() modified the provided args: %v", args)
+	}
+}
+
+// mocked neo4j.ResultSummary
+type msum struct {
+	neo4j.ResultSummary
+	available time.Duration
+	consumed  time.Duration
+}
+
+func (m *msum) ResultAvailableAfter() time.Duration { return m.available }
+func (m *msum) ResultConsumedAfter() time.Duration  { return m.consumed }
+
+func TestSession_WriteTransactionxContext_Hooks(t *testing.T) {
+	t.Parallel()
+
+	hook := &mtxhook{}
+	d := &Driver{}
+	d.Use(hook)
+	s := &Session{Session: &mretry{attempts: 3}, instr: d.instr}
+
+	ctx := context.WithValue(context.Background(), ctxkey{}, "request")
+	_, err := s.WriteTransactionxContext(ctx, func(tx *Transaction) (interface{}, error) {
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("Session.WriteTransactionxContext() error = %v", err)
+	}
+
+	if len(hook.txs) != 1 {
+		t.Fatalf("AfterTransaction called %d times, want 1", len(hook.txs))
+	}
+	if tx := hook.txs[0]; tx.Attempts != 3 || tx.AccessMode != neo4j.AccessModeWrite {
+		t.Errorf("TransactionInfo = %+v, want 3 write attempts", tx)
+	}
+	if hook.ctx.Value(ctxkey{}) != "request" {
+		t.Errorf("AfterTransaction context was not derived from the provided context")
+	}
+}
+
+// mocked neo4j.Session, running the provided work the configured number of attempts
+type mretry struct {
+	neo4j.Session
+	attempts int
+}
+
+func (m *mretry) WriteTransaction(work neo4j.TransactionWork, configurers ...func(*neo4j.TransactionConfig)) (interface{}, error) {
+	var (
+		res interface{}
+		err error
+	)
+	for i := 0; i < m.attempts; i++ {
+		res, err = work(nil)
+	}
+	return res, err
+}
+
+// mocked neox.TransactionHook
+type mtxhook struct {
+	Hooks
+	ctx context.Context
+	txs []TransactionInfo
+}
+
+func (m *mtxhook) BeforeTransaction(ctx context.Context, tx *TransactionInfo) context.Context {
+	return ctx
+}
+
+func (m *mtxhook) AfterTransaction(ctx context.Context, tx *TransactionInfo) {
+	m.ctx = ctx
+	m.txs = append(m.txs, *tx)
+}
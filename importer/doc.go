@@ -0,0 +1,31 @@
+// Package importer loads CSV and JSON Lines files into nodes and relationships. A Mapping
+// declares which fields of a record are written to which node labels, properties and
+// relationship endpoints, and how their values are coerced. Records are written in
+// batches with the bulk package, each batch by a single unwind statement
+//
+//	im, err := importer.New(driver, importer.Mapping{
+//		Nodes: []importer.Node{{
+//			Label: "User",
+//			Key:   importer.Property{Name: "id", Field: "user_id"},
+//			Props: []importer.Property{
+//				{Name: "name"},
+//				{Name: "age", Type: importer.Int},
+//				{Name: "born", Type: importer.Date},
+//			},
+//		}},
+//		Relationships: []importer.Relationship{{
+//			Type: "WORKS_AT",
+//			From: importer.Endpoint{Label: "User", Key: importer.Property{Name: "id", Field: "user_id"}},
+//			To:   importer.Endpoint{Label: "Company", Key: importer.Property{Name: "name", Field: "company"}},
+//			Props: []importer.Property{{Name: "since", Type: importer.Int}},
+//		}},
+//	}, bulk.Config{BatchSize: 5000, Workers: 4})
+//
+//	report, err := im.ImportCSV(ctx, file)
+//
+// Mappings can also be decoded from JSON, using the lowercase names of their fields.
+//
+// Nodes and relationship endpoints are merged on their key, so relationships may be
+// imported before or without the nodes they connect. When importing with several
+// workers, keys should be backed by uniqueness constraints to prevent duplicates
+package importer
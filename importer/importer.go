@@ -0,0 +1,270 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/syllabix/neox"
+	"github.com/syllabix/neox/bulk"
+	"github.com/syllabix/neox/internal/identifier"
+)
+
+// RecordError is returned when a record can not be mapped
+type RecordError struct {
+	// Record is the position of the record in its source, starting at 1
+	Record int
+
+	// Field is the field holding an invalid value, if any
+	Field string
+
+	Err error
+}
+
+func (e *RecordError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("importer: record %d: %v", e.Record, e.Err)
+	}
+	return fmt.Sprintf("importer: record %d: field %q: %v", e.Record, e.Field, e.Err)
+}
+
+// Unwrap returns the cause of the failure
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
+
+// Importer writes the records of CSV or JSON Lines sources to the graph following a
+// mapping. It is safe for concurrent use
+type Importer struct {
+	mapping   Mapping
+	statement string
+	bulk      *bulk.Importer
+}
+
+// New returns an importer writing with the sessions of the driver. The Cypher of the
+// config is replaced by the statement generated for the mapping
+func New(driver *neox.Driver, mapping Mapping, config bulk.Config) (*Importer, error) {
+	return build(mapping, config, func(config bulk.Config) *bulk.Importer {
+		return bulk.New(driver, config)
+	})
+}
+
+// NewWithSessions returns an importer writing with the sessions returned by open,
+// see bulk.NewWithSessions
+func NewWithSessions(open func() (bulk.Session, error), mapping Mapping, config bulk.Config) (*Importer, error) {
+	return build(mapping, config, func(config bulk.Config) *bulk.Importer {
+		return bulk.NewWithSessions(open, config)
+	})
+}
+
+func build(mapping Mapping, config bulk.Config, importer func(bulk.Config) *bulk.Importer) (*Importer, error) {
+	statement, err := mapping.statement()
+	if err != nil {
+		return nil, err
+	}
+	config.Cypher = statement
+	return &Importer{
+		mapping:   mapping,
+		statement: statement,
+		bulk:      importer(config),
+	}, nil
+}
+
+// Statement returns the statement writing a batch of records, passed as the $batch parameter
+func (im *Importer) Statement() string {
+	return im.statement
+}
+
+// Import writes the records of the source, which must be maps of values by field name
+func (im *Importer) Import(ctx context.Context, source bulk.Source) (bulk.Report, error) {
+	return im.bulk.Run(ctx, &rows{mapping: im.mapping, source: source})
+}
+
+// ImportCSV writes the records of a CSV file whose first row names its columns
+func (im *Importer) ImportCSV(ctx context.Context, r io.Reader) (bulk.Report, error) {
+	return im.Import(ctx, NewCSVSource(r))
+}
+
+// ImportJSONL writes the records of a JSON Lines file
+func (im *Importer) ImportJSONL(ctx context.Context, r io.Reader) (bulk.Report, error) {
+	return im.Import(ctx, NewJSONLSource(r))
+}
+
+// statement returns the statement writing a batch of rows. Each node and relationship is
+// written in a foreach clause, iterating once when the row holds it and skipping it otherwise
+//
+//	unwind $batch as row
+//	foreach (i in case when row.n0 is null then [] else [1] end |
+//		merge (n:User {id: row.n0.key}) set n += row.n0.props)
+//	foreach (i in case when row.r0 is null then [] else [1] end |
+//		merge (a:User {id: row.r0.from}) merge (b:Company {name: row.r0.to})
+//		merge (a)-[r:WORKS_AT]->(b) set r += row.r0.props)
+func (m Mapping) statement() (string, error) {
+	if len(m.Nodes) == 0 && len(m.Relationships) == 0 {
+		return "", errors.New("importer: the mapping has no node nor relationship")
+	}
+
+	clauses := []string{"unwind $batch as row"}
+	for i, node := range m.Nodes {
+		name := "node " + node.Label
+		pattern, err := endpoint("n", Endpoint{Label: node.Label, Key: node.Key}, "row.n"+strconv.Itoa(i)+".key")
+		if err != nil {
+			return "", fmt.Errorf("importer: %s: %w", name, err)
+		}
+		if err := validate(node.Props); err != nil {
+			return "", fmt.Errorf("importer: %s: %w", name, err)
+		}
+		row := "row.n" + strconv.Itoa(i)
+		clauses = append(clauses, "foreach (i in case when "+row+" is null then [] else [1] end | "+
+			"merge "+pattern+" set n += "+row+".props)")
+	}
+	for i, rel := range m.Relationships {
+		name := "relationship " + rel.Type
+		relType, err := identifier.Escape(rel.Type)
+		if err != nil {
+			return "", fmt.Errorf("importer: %s: %w", name, err)
+		}
+		row := "row.r" + strconv.Itoa(i)
+		from, err := endpoint("a", rel.From, row+".from")
+		if err != nil {
+			return "", fmt.Errorf("importer: %s: from: %w", name, err)
+		}
+		to, err := endpoint("b", rel.To, row+".to")
+		if err != nil {
+			return "", fmt.Errorf("importer: %s: to: %w", name, err)
+		}
+		if err := validate(rel.Props); err != nil {
+			return "", fmt.Errorf("importer: %s: %w", name, err)
+		}
+		clauses = append(clauses, "foreach (i in case when "+row+" is null then [] else [1] end | "+
+			"merge "+from+" merge "+to+" merge (a)-[r:"+relType+"]->(b) set r += "+row+".props)")
+	}
+	return strings.Join(clauses, " "), nil
+}
+
+// endpoint returns the pattern of a node identified by its label and key
+func endpoint(variable string, e Endpoint, key string) (string, error) {
+	label, err := identifier.Escape(e.Label)
+	if err != nil {
+		return "", fmt.Errorf("label: %w", err)
+	}
+	if err := validate([]Property{e.Key}); err != nil {
+		return "", fmt.Errorf("key: %w", err)
+	}
+	prop, err := identifier.Escape(e.Key.Name)
+	if err != nil {
+		return "", fmt.Errorf("key: %w", err)
+	}
+	return "(" + variable + ":" + label + " {" + prop + ": " + key + "})", nil
+}
+
+func validate(props []Property) error {
+	for _, p := range props {
+		if p.Name == "" {
+			return errors.New("a property has no name")
+		}
+		if !p.Type.valid() {
+			return fmt.Errorf("property %s: unknown type %q", p.Name, p.Type)
+		}
+	}
+	return nil
+}
+
+// rows is a source of the rows written for the records of a source
+type rows struct {
+	mapping Mapping
+	source  bulk.Source
+	n       int
+}
+
+// Next returns the row of the next record holding a node or a relationship
+func (r *rows) Next() (interface{}, error) {
+	for {
+		item, err := r.source.Next()
+		if err != nil {
+			return nil, err
+		}
+		r.n++
+
+		var record map[string]interface{}
+		switch item := item.(type) {
+		case map[string]interface{}:
+			record = item
+		case neox.Args:
+			record = item
+		default:
+			return nil, &RecordError{Record: r.n, Err: fmt.Errorf("got %T, want a map", item)}
+		}
+
+		row, err := r.row(record)
+		if err != nil {
+			return nil, err
+		}
+		if len(row) > 0 {
+			return row, nil
+		}
+	}
+}
+
+// row returns the nodes and relationships of a record, by their position in the mapping
+func (r *rows) row(record map[string]interface{}) (map[string]interface{}, error) {
+	row := make(map[string]interface{})
+	for i, node := range r.mapping.Nodes {
+		key, err := r.value(record, node.Key)
+		if err != nil {
+			return nil, err
+		}
+		if key == nil {
+			continue
+		}
+		props, err := r.props(record, node.Props)
+		if err != nil {
+			return nil, err
+		}
+		row["n"+strconv.Itoa(i)] = map[string]interface{}{"key": key, "props": props}
+	}
+	for i, rel := range r.mapping.Relationships {
+		from, err := r.value(record, rel.From.Key)
+		if err != nil {
+			return nil, err
+		}
+		to, err := r.value(record, rel.To.Key)
+		if err != nil {
+			return nil, err
+		}
+		if from == nil || to == nil {
+			continue
+		}
+		props, err := r.props(record, rel.Props)
+		if err != nil {
+			return nil, err
+		}
+		row["r"+strconv.Itoa(i)] = map[string]interface{}{"from": from, "to": to, "props": props}
+	}
+	return row, nil
+}
+
+func (r *rows) props(record map[string]interface{}, props []Property) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(props))
+	for _, p := range props {
+		v, err := r.value(record, p)
+		if err != nil {
+			return nil, err
+		}
+		if v != nil {
+			values[p.Name] = v
+		}
+	}
+	return values, nil
+}
+
+func (r *rows) value(record map[string]interface{}, p Property) (interface{}, error) {
+	v, err := p.value(record)
+	if err != nil {
+		return nil, &RecordError{Record: r.n, Field: p.field(), Err: err}
+	}
+	return v, nil
+}
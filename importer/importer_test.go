@@ -0,0 +1,218 @@
+package importer_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/syllabix/neox/bulk"
+	"github.com/syllabix/neox/importer"
+	"github.com/syllabix/neox/neoxtest"
+)
+
+var mapping = importer.Mapping{
+	Nodes: []importer.Node{
+		{
+			Label: "User",
+			Key:   importer.Property{Name: "id", Field: "user_id", Type: importer.Int},
+			Props: []importer.Property{{Name: "name"}, {Name: "age", Type: importer.Int}},
+		},
+		{
+			Label: "Company",
+			Key:   importer.Property{Name: "name", Field: "company"},
+		},
+	},
+	Relationships: []importer.Relationship{{
+		Type:  "WORKS_AT",
+		From:  importer.Endpoint{Label: "User", Key: importer.Property{Name: "id", Field: "user_id", Type: importer.Int}},
+		To:    importer.Endpoint{Label: "Company", Key: importer.Property{Name: "name", Field: "company"}},
+		Props: []importer.Property{{Name: "since", Type: importer.Int}},
+	}},
+}
+
+const statement = "unwind $batch as row " +
+	"foreach (i in case when row.n0 is null then [] else [1] end | merge (n:User {id: row.n0.key}) set n += row.n0.props) " +
+	"foreach (i in case when row.n1 is null then [] else [1] end | merge (n:Company {name: row.n1.key}) set n += row.n1.props) " +
+	"foreach (i in case when row.r0 is null then [] else [1] end | merge (a:User {id: row.r0.from}) merge (b:Company {name: row.r0.to}) " +
+	"merge (a)-[r:WORKS_AT]->(b) set r += row.r0.props)"
+
+func node(key interface{}, props map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"key": key, "props": props}
+}
+
+func rel(from, to interface{}, props map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"from": from, "to": to, "props": props}
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		mapping importer.Mapping
+		want    string
+		wantErr bool
+	}{
+		{name: "Should generate a statement for the mapping", mapping: mapping, want: statement},
+		{
+			name: "Should escape identifiers",
+			mapping: importer.Mapping{Nodes: []importer.Node{{
+				Label: "Movie Star",
+				Key:   importer.Property{Name: "full name"},
+			}}},
+			want: "unwind $batch as row foreach (i in case when row.n0 is null then [] else [1] end | " +
+				"merge (n:`Movie Star` {`full name`: row.n0.key}) set n += row.n0.props)",
+		},
+		{name: "Should reject empty mappings", mapping: importer.Mapping{}, wantErr: true},
+		{
+			name:    "Should reject nodes without a key",
+			mapping: importer.Mapping{Nodes: []importer.Node{{Label: "User"}}},
+			wantErr: true,
+		},
+		{
+			name:    "Should reject invalid labels",
+			mapping: importer.Mapping{Nodes: []importer.Node{{Label: "", Key: importer.Property{Name: "id"}}}},
+			wantErr: true,
+		},
+		{
+			name: "Should reject unknown types",
+			mapping: importer.Mapping{Nodes: []importer.Node{{
+				Label: "User",
+				Key:   importer.Property{Name: "id"},
+				Props: []importer.Property{{Name: "age", Type: "integer"}},
+			}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			im, err := importer.New(neoxtest.NewDriver().Neox(), tt.mapping, bulk.Config{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && im.Statement() != tt.want {
+				t.Errorf("Importer.Statement() got = %v, want %v", im.Statement(), tt.want)
+			}
+		})
+	}
+}
+
+func TestImporter_Import(t *testing.T) {
+	t.Parallel()
+
+	want := []interface{}{
+		map[string]interface{}{
+			"n0": node(int64(1), map[string]interface{}{"name": "Yolanda", "age": int64(42)}),
+			"n1": node("Acme", map[string]interface{}{}),
+			"r0": rel(int64(1), "Acme", map[string]interface{}{"since": int64(2019)}),
+		},
+		map[string]interface{}{
+			"n0": node(int64(2), map[string]interface{}{"name": "Sam"}),
+		},
+		map[string]interface{}{
+			"n1": node("Initech", map[string]interface{}{}),
+		},
+	}
+
+	tests := []struct {
+		name   string
+		input  string
+		format string
+	}{
+		{
+			name:   "Should import CSV files",
+			format: "csv",
+			input: "user_id,name,age,company,since\n" +
+				"1,Yolanda,42,Acme,2019\n" +
+				"2,Sam,,,\n" +
+				",,,Initech,\n" +
+				",,,,\n",
+		},
+		{
+			name:   "Should import JSON Lines files",
+			format: "jsonl",
+			input: `{"user_id": 1, "name": "Yolanda", "age": "42", "company": "Acme", "since": 2019}` + "\n" +
+				`{"user_id": "2", "name": "Sam", "age": null}` + "\n" +
+				`{"company": "Initech"}` + "\n" +
+				`{}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := neoxtest.NewDriver()
+			fake.On(statement).WithSummary(neoxtest.Summary{Counters: neoxtest.Counters{NodesCreated: 3, RelationshipsCreated: 1}})
+
+			im, err := importer.New(fake.Neox(), mapping, bulk.Config{BatchSize: 10, Workers: 2})
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			var report bulk.Report
+			if tt.format == "csv" {
+				report, err = im.ImportCSV(context.Background(), strings.NewReader(tt.input))
+			} else {
+				report, err = im.ImportJSONL(context.Background(), strings.NewReader(tt.input))
+			}
+			if err != nil {
+				t.Fatalf("Importer.Import() error = %v", err)
+			}
+			if report.Items != 3 || report.Counters.NodesCreated != 3 || report.Counters.RelationshipsCreated != 1 {
+				t.Errorf("Importer.Import() got = %+v, want 3 items", report)
+			}
+
+			calls := fake.Calls()
+			if len(calls) != 1 {
+				t.Fatalf("Calls() = %+v, want a single call", calls)
+			}
+			if got := calls[0].Args["batch"]; !reflect.DeepEqual(got, want) {
+				t.Errorf("batch got = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestImporter_Import_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		input      string
+		wantRecord int
+		wantField  string
+	}{
+		{
+			name:       "Should report invalid values",
+			input:      "user_id,name,age\n1,Yolanda,42\n2,Sam,old\n",
+			wantRecord: 2,
+			wantField:  "age",
+		},
+		{
+			name:       "Should report invalid keys",
+			input:      "user_id,name\nu1,Yolanda\n",
+			wantRecord: 1,
+			wantField:  "user_id",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := neoxtest.NewDriver()
+			im, err := importer.New(fake.Neox(), mapping, bulk.Config{})
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			_, err = im.ImportCSV(context.Background(), strings.NewReader(tt.input))
+			var recordErr *importer.RecordError
+			if !errors.As(err, &recordErr) {
+				t.Fatalf("Importer.Import() error = %v, want a RecordError", err)
+			}
+			if recordErr.Record != tt.wantRecord || recordErr.Field != tt.wantField {
+				t.Errorf("Importer.Import() error = %v, want record %d field %s", err, tt.wantRecord, tt.wantField)
+			}
+			if calls := fake.Calls(); len(calls) != 0 {
+				t.Errorf("Calls() = %+v, want none", calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package importer
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+// Type is the type the values of a field are coerced to. Values are coerced to the
+// types neox passes as Args, ie: integers to int64 and dates to neo4j.Date
+type Type string
+
+// supported types
+const (
+	// Auto keeps values as read, strings for CSV files, and strings, int64, float64,
+	// bool and lists for JSON Lines files
+	Auto Type = ""
+
+	String Type = "string"
+	Int    Type = "int"
+	Float  Type = "float"
+	Bool   Type = "bool"
+
+	// DateTime parses RFC 3339 strings to a time.Time, written as a datetime
+	DateTime Type = "datetime"
+
+	// Date parses strings formatted as 2006-01-02 to a neo4j.Date
+	Date Type = "date"
+
+	// LocalDateTime parses strings formatted as 2006-01-02T15:04:05 to a neo4j.LocalDateTime
+	LocalDateTime Type = "localdatetime"
+)
+
+// Mapping declares how records are written to the graph. Every record is written to
+// each of its nodes and relationships, skipping those whose keys are missing from it
+type Mapping struct {
+	Nodes         []Node         `json:"nodes,omitempty"`
+	Relationships []Relationship `json:"relationships,omitempty"`
+}
+
+// Node maps a record to a node, merged on its key
+type Node struct {
+	Label string     `json:"label"`
+	Key   Property   `json:"key"`
+	Props []Property `json:"props,omitempty"`
+}
+
+// Endpoint identifies the node at one end of a relationship by its label and key
+type Endpoint struct {
+	Label string   `json:"label"`
+	Key   Property `json:"key"`
+}
+
+// Relationship maps a record to a relationship between two nodes, merged along with them
+type Relationship struct {
+	Type  string     `json:"type"`
+	From  Endpoint   `json:"from"`
+	To    Endpoint   `json:"to"`
+	Props []Property `json:"props,omitempty"`
+}
+
+// Property maps the field of a record to a property. Fields of nested JSON objects are
+// named by their path, ie: address.city. Missing fields, null values and empty strings
+// leave the property unset
+type Property struct {
+	// Name is the name of the property
+	Name string `json:"name"`
+
+	// Field is the name of the field, the name of the property by default
+	Field string `json:"field,omitempty"`
+
+	// Type is the type the value is coerced to, or the type of the elements of a list
+	Type Type `json:"type,omitempty"`
+
+	// Separator, when set, splits string values into a list
+	Separator string `json:"separator,omitempty"`
+}
+
+func (p Property) field() string {
+	if p.Field == "" {
+		return p.Name
+	}
+	return p.Field
+}
+
+// value returns the coerced value of the property in the record, or nil when it is missing
+func (p Property) value(record map[string]interface{}) (interface{}, error) {
+	v := lookup(record, p.field())
+	if missing(v) {
+		return nil, nil
+	}
+
+	if s, ok := v.(string); ok && p.Separator != "" {
+		parts := strings.Split(s, p.Separator)
+		list := make([]interface{}, len(parts))
+		for i, part := range parts {
+			list[i] = strings.TrimSpace(part)
+		}
+		v = list
+	}
+	list, ok := v.([]interface{})
+	if !ok {
+		value, err := p.Type.coerce(v)
+		if err != nil {
+			return nil, err
+		}
+		return value, nil
+	}
+
+	values := make([]interface{}, 0, len(list))
+	for _, elem := range list {
+		if missing(elem) {
+			continue
+		}
+		value, err := p.Type.coerce(elem)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// lookup returns the value of a field of the record, following the path of nested objects
+func lookup(record map[string]interface{}, field string) interface{} {
+	if v, ok := record[field]; ok {
+		return v
+	}
+	name, rest, nested := strings.Cut(field, ".")
+	if !nested {
+		return nil
+	}
+	object, ok := record[name].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	return lookup(object, rest)
+}
+
+func missing(v interface{}) bool {
+	return v == nil || v == ""
+}
+
+func (t Type) valid() bool {
+	switch t {
+	case Auto, String, Int, Float, Bool, DateTime, Date, LocalDateTime:
+		return true
+	}
+	return false
+}
+
+// coerce converts a value read from a record to the type
+func (t Type) coerce(v interface{}) (interface{}, error) {
+	switch t {
+	case Auto:
+		return v, nil
+	case String:
+		switch v := v.(type) {
+		case string:
+			return v, nil
+		case int64:
+			return strconv.FormatInt(v, 10), nil
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64), nil
+		case bool:
+			return strconv.FormatBool(v), nil
+		}
+	case Int:
+		switch v := v.(type) {
+		case string:
+			return strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		case int64:
+			return v, nil
+		case float64:
+			if v == math.Trunc(v) && math.Abs(v) < 1<<63 {
+				return int64(v), nil
+			}
+			return nil, fmt.Errorf("%v is not an integer", v)
+		}
+	case Float:
+		switch v := v.(type) {
+		case string:
+			return strconv.ParseFloat(strings.TrimSpace(v), 64)
+		case int64:
+			return float64(v), nil
+		case float64:
+			return v, nil
+		}
+	case Bool:
+		switch v := v.(type) {
+		case string:
+			return strconv.ParseBool(strings.TrimSpace(v))
+		case bool:
+			return v, nil
+		}
+	case DateTime:
+		if s, ok := v.(string); ok {
+			return time.Parse(time.RFC3339Nano, strings.TrimSpace(s))
+		}
+	case Date:
+		if s, ok := v.(string); ok {
+			d, err := time.Parse("2006-01-02", strings.TrimSpace(s))
+			if err != nil {
+				return nil, err
+			}
+			return neo4j.DateOf(d), nil
+		}
+	case LocalDateTime:
+		if s, ok := v.(string); ok {
+			d, err := time.Parse("2006-01-02T15:04:05.999999999", strings.TrimSpace(s))
+			if err != nil {
+				return nil, err
+			}
+			return neo4j.LocalDateTimeOf(d), nil
+		}
+	}
+	return nil, fmt.Errorf("can not coerce %T to %s", v, t)
+}
@@ -0,0 +1,65 @@
+package importer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+func TestProperty_value(t *testing.T) {
+	t.Parallel()
+
+	record := map[string]interface{}{
+		"name":    "Yolanda Erasmus",
+		"age":     "42",
+		"score":   int64(7),
+		"ratio":   float64(3),
+		"active":  "true",
+		"born":    "1982-03-14",
+		"seen":    "2021-06-01T10:30:00Z",
+		"local":   "2021-06-01T10:30:00",
+		"tags":    "a; b ;c",
+		"ids":     []interface{}{"1", int64(2), nil},
+		"empty":   "",
+		"address": map[string]interface{}{"city": "Cape Town"},
+	}
+
+	tests := []struct {
+		name    string
+		prop    Property
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "Should keep values by default", prop: Property{Name: "age"}, want: "42"},
+		{name: "Should read fields by name", prop: Property{Name: "n", Field: "name"}, want: "Yolanda Erasmus"},
+		{name: "Should read nested fields", prop: Property{Name: "city", Field: "address.city"}, want: "Cape Town"},
+		{name: "Should skip missing fields", prop: Property{Name: "missing", Type: Int}, want: nil},
+		{name: "Should skip empty strings", prop: Property{Name: "empty", Type: Int}, want: nil},
+		{name: "Should coerce strings to int64", prop: Property{Name: "age", Type: Int}, want: int64(42)},
+		{name: "Should coerce integral floats to int64", prop: Property{Name: "ratio", Type: Int}, want: int64(3)},
+		{name: "Should coerce integers to float64", prop: Property{Name: "score", Type: Float}, want: float64(7)},
+		{name: "Should coerce integers to strings", prop: Property{Name: "score", Type: String}, want: "7"},
+		{name: "Should coerce bools", prop: Property{Name: "active", Type: Bool}, want: true},
+		{name: "Should coerce dates", prop: Property{Name: "born", Type: Date}, want: neo4j.DateOf(time.Date(1982, 3, 14, 0, 0, 0, 0, time.UTC))},
+		{name: "Should coerce datetimes", prop: Property{Name: "seen", Type: DateTime}, want: time.Date(2021, 6, 1, 10, 30, 0, 0, time.UTC)},
+		{name: "Should coerce local datetimes", prop: Property{Name: "local", Type: LocalDateTime}, want: neo4j.LocalDateTimeOf(time.Date(2021, 6, 1, 10, 30, 0, 0, time.UTC))},
+		{name: "Should split strings into lists", prop: Property{Name: "tags", Separator: ";"}, want: []interface{}{"a", "b", "c"}},
+		{name: "Should coerce list elements", prop: Property{Name: "ids", Type: Int}, want: []interface{}{int64(1), int64(2)}},
+		{name: "Should fail on invalid integers", prop: Property{Name: "name", Type: Int}, wantErr: true},
+		{name: "Should fail on invalid dates", prop: Property{Name: "age", Type: Date}, wantErr: true},
+		{name: "Should fail on uncoercible values", prop: Property{Name: "score", Type: Bool}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.prop.value(record)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Property.value() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Property.value() got = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
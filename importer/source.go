@@ -0,0 +1,94 @@
+package importer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// CSVSource reads records from a CSV file whose first row holds the names of its columns.
+// Values are read as strings. The csv.Reader may be configured before the first record is
+// read, ie: to change its delimiter
+type CSVSource struct {
+	Reader *csv.Reader
+	header []string
+}
+
+// NewCSVSource returns a source reading CSV records from r
+func NewCSVSource(r io.Reader) *CSVSource {
+	return &CSVSource{Reader: csv.NewReader(r)}
+}
+
+// Next returns the next record as a map of its values by column name
+func (s *CSVSource) Next() (interface{}, error) {
+	if s.header == nil {
+		header, err := s.Reader.Read()
+		if err != nil {
+			return nil, err
+		}
+		s.header = header
+	}
+
+	values, err := s.Reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	record := make(map[string]interface{}, len(s.header))
+	for i, name := range s.header {
+		if i < len(values) {
+			record[name] = values[i]
+		}
+	}
+	return record, nil
+}
+
+// JSONLSource reads records from a JSON Lines file, holding a JSON object per line.
+// Integers are read as int64 and other numbers as float64
+type JSONLSource struct {
+	dec  *json.Decoder
+	line int
+}
+
+// NewJSONLSource returns a source reading JSON Lines records from r
+func NewJSONLSource(r io.Reader) *JSONLSource {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	return &JSONLSource{dec: dec}
+}
+
+// Next returns the next record
+func (s *JSONLSource) Next() (interface{}, error) {
+	s.line++
+	var record map[string]interface{}
+	if err := s.dec.Decode(&record); err == io.EOF {
+		return nil, err
+	} else if err != nil {
+		return nil, fmt.Errorf("record %d: %w", s.line, err)
+	}
+	if record == nil {
+		return nil, fmt.Errorf("record %d: not a JSON object", s.line)
+	}
+	return normalize(record), nil
+}
+
+// normalize replaces the json numbers of a decoded value with an int64 or a float64
+func normalize(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case []interface{}:
+		for i := range v {
+			v[i] = normalize(v[i])
+		}
+	case map[string]interface{}:
+		for k := range v {
+			v[k] = normalize(v[k])
+		}
+	}
+	return v
+}
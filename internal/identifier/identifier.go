@@ -0,0 +1,39 @@
+// Package identifier validates and escapes the names written into cypher statements
+package identifier
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrInvalid is matched by the errors returned for names that can not be safely
+// used as a label, relationship type, property key or variable
+var ErrInvalid = errors.New("invalid identifier")
+
+// Escape validates a name and quotes it with backticks unless it is a plain
+// identifier. Backslashes are rejected as some servers interpret unicode escapes
+// within quoted names
+func Escape(name string) (string, error) {
+	if name == "" || !utf8.ValidString(name) {
+		return "", fmt.Errorf("%w: %q", ErrInvalid, name)
+	}
+
+	plain := true
+	for i, r := range name {
+		switch {
+		case r == '\\' || unicode.IsControl(r):
+			return "", fmt.Errorf("%w: %q contains %q", ErrInvalid, name, r)
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case i > 0 && r >= '0' && r <= '9':
+		default:
+			plain = false
+		}
+	}
+	if plain {
+		return name, nil
+	}
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`", nil
+}
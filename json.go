@@ -0,0 +1,293 @@
+package neox
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+// ToJSONValue converts a value returned by the driver into a value encoding/json renders
+// faithfully, as the driver implementations of graph values have no exported fields.
+//
+//	node          {"id": 1, "labels": ["User"], "properties": {"name": "Yolanda"}}
+//	relationship  {"id": 7, "type": "KNOWS", "start": 1, "end": 2, "properties": {}}
+//	path          {"start": node, "end": node, "segments": [{"start": node, "relationship": relationship, "end": node}]}
+//	point         {"srid": 4326, "x": 18.42, "y": -33.92}, with a z for 3D points
+//
+// Segments are listed in the order the path traverses them, their relationship keeping its own
+// start and end. Temporal values are converted to ISO 8601 strings, and NaN or infinite floats
+// to strings. Lists and maps are converted element by element, other values are returned as is
+func ToJSONValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case neo4j.Node:
+		return jsonNode(v)
+	case neo4j.Relationship:
+		return jsonRelationship(v)
+	case neo4j.Path:
+		return jsonPath(v)
+	case neo4j.Date, neo4j.LocalTime, neo4j.OffsetTime, neo4j.LocalDateTime, neo4j.Duration:
+		return v.(interface{ String() string }).String()
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case *neo4j.Point:
+		p := map[string]interface{}{"srid": int64(v.SrId()), "x": v.X(), "y": v.Y()}
+		if z := v.Z(); !math.IsNaN(z) {
+			p["z"] = z
+		}
+		return p
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return strconv.FormatFloat(v, 'g', -1, 64)
+		}
+		return v
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = ToJSONValue(item)
+		}
+		return list
+	case map[string]interface{}:
+		return jsonProps(v)
+	}
+	return v
+}
+
+// FromJSONValue is the reverse of ToJSONValue, meant to build fixtures from JSON documents.
+// Objects shaped as a node, a relationship or a path are converted to values implementing
+// neo4j.Node, neo4j.Relationship and neo4j.Path, json.Number values to an int64 when they
+// are integers and a float64 otherwise. Temporal values and points remain strings and maps,
+// as their JSON form does not tell them apart
+func FromJSONValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = FromJSONValue(item)
+		}
+		return list
+	case map[string]interface{}:
+		if n, ok := nodeFromJSON(v); ok {
+			return n
+		}
+		if r, ok := relationshipFromJSON(v); ok {
+			return r
+		}
+		if p, ok := pathFromJSON(v); ok {
+			return p
+		}
+		m := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			m[k] = FromJSONValue(item)
+		}
+		return m
+	}
+	return v
+}
+
+// JSONValue wraps a value to marshal it as converted by ToJSONValue, and to unmarshal
+// JSON into the value converted by FromJSONValue
+//
+//	b, err := json.Marshal(neox.JSONValue{Value: node})
+//
+//	var fixture neox.JSONValue
+//	err = json.Unmarshal(b, &fixture)
+//	node = fixture.Value.(neo4j.Node)
+type JSONValue struct {
+	Value interface{}
+}
+
+// MarshalJSON marshals the value converted by ToJSONValue
+func (v JSONValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(ToJSONValue(v.Value))
+}
+
+// UnmarshalJSON unmarshals JSON into the value converted by FromJSONValue, reading
+// numbers as json.Number so that integers are not turned into floats
+func (v *JSONValue) UnmarshalJSON(b []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return err
+	}
+	v.Value = FromJSONValue(value)
+	return nil
+}
+
+func jsonProps(props map[string]interface{}) map[string]interface{} {
+	m := make(map[string]interface{}, len(props))
+	for k, v := range props {
+		m[k] = ToJSONValue(v)
+	}
+	return m
+}
+
+func jsonNode(n neo4j.Node) map[string]interface{} {
+	labels := n.Labels()
+	if labels == nil {
+		labels = []string{}
+	}
+	return map[string]interface{}{
+		"id":         n.Id(),
+		"labels":     labels,
+		"properties": jsonProps(n.Props()),
+	}
+}
+
+func jsonRelationship(r neo4j.Relationship) map[string]interface{} {
+	return map[string]interface{}{
+		"id":         r.Id(),
+		"type":       r.Type(),
+		"start":      r.StartId(),
+		"end":        r.EndId(),
+		"properties": jsonProps(r.Props()),
+	}
+}
+
+func jsonPath(p neo4j.Path) map[string]interface{} {
+	nodes, rels := p.Nodes(), p.Relationships()
+	if len(nodes) == 0 {
+		return map[string]interface{}{"start": nil, "end": nil, "segments": []interface{}{}}
+	}
+	segments := make([]interface{}, len(rels))
+	for i, rel := range rels {
+		segments[i] = map[string]interface{}{
+			"start":        jsonNode(nodes[i]),
+			"relationship": jsonRelationship(rel),
+			"end":          jsonNode(nodes[i+1]),
+		}
+	}
+	return map[string]interface{}{
+		"start":    jsonNode(nodes[0]),
+		"end":      jsonNode(nodes[len(nodes)-1]),
+		"segments": segments,
+	}
+}
+
+// hasKeys reports whether the object holds exactly the provided keys
+func hasKeys(object map[string]interface{}, keys ...string) bool {
+	if len(object) != len(keys) {
+		return false
+	}
+	for _, k := range keys {
+		if _, ok := object[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func nodeFromJSON(object map[string]interface{}) (*graphNode, bool) {
+	if !hasKeys(object, "id", "labels", "properties") {
+		return nil, false
+	}
+	id, ok := jsonID(object["id"])
+	list, isList := object["labels"].([]interface{})
+	props, isMap := jsonPropsFrom(object["properties"])
+	if !ok || !isList || !isMap {
+		return nil, false
+	}
+	labels := make([]string, len(list))
+	for i, label := range list {
+		if labels[i], ok = label.(string); !ok {
+			return nil, false
+		}
+	}
+	return &graphNode{id: id, labels: labels, props: props}, true
+}
+
+func relationshipFromJSON(object map[string]interface{}) (*graphRelationship, bool) {
+	if !hasKeys(object, "id", "type", "start", "end", "properties") {
+		return nil, false
+	}
+	id, okID := jsonID(object["id"])
+	start, okStart := jsonID(object["start"])
+	end, okEnd := jsonID(object["end"])
+	relType, okType := object["type"].(string)
+	props, okProps := jsonPropsFrom(object["properties"])
+	if !okID || !okStart || !okEnd || !okType || !okProps {
+		return nil, false
+	}
+	return &graphRelationship{id: id, startID: start, endID: end, relType: relType, props: props}, true
+}
+
+func pathFromJSON(object map[string]interface{}) (*graphPath, bool) {
+	if !hasKeys(object, "start", "end", "segments") {
+		return nil, false
+	}
+	segments, ok := object["segments"].([]interface{})
+	if !ok {
+		return nil, false
+	}
+	p := &graphPath{}
+	if object["start"] == nil && len(segments) == 0 {
+		return p, true
+	}
+	start, ok := object["start"].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	first, ok := nodeFromJSON(start)
+	if !ok {
+		return nil, false
+	}
+	p.nodes = append(p.nodes, first)
+	for _, item := range segments {
+		segment, ok := item.(map[string]interface{})
+		if !ok || !hasKeys(segment, "start", "relationship", "end") {
+			return nil, false
+		}
+		rel, okRel := segmentPart(segment["relationship"], relationshipFromJSON)
+		end, okEnd := segmentPart(segment["end"], nodeFromJSON)
+		if !okRel || !okEnd {
+			return nil, false
+		}
+		p.rels = append(p.rels, rel)
+		p.nodes = append(p.nodes, end)
+	}
+	return p, true
+}
+
+func segmentPart[T any](v interface{}, from func(map[string]interface{}) (T, bool)) (T, bool) {
+	object, ok := v.(map[string]interface{})
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return from(object)
+}
+
+func jsonID(v interface{}) (int64, bool) {
+	switch v := v.(type) {
+	case json.Number:
+		i, err := v.Int64()
+		return i, err == nil
+	case float64:
+		return int64(v), v == math.Trunc(v)
+	case int64:
+		return v, true
+	}
+	return 0, false
+}
+
+func jsonPropsFrom(v interface{}) (map[string]interface{}, bool) {
+	object, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	props := make(map[string]interface{}, len(object))
+	for k, item := range object {
+		props[k] = FromJSONValue(item)
+	}
+	return props, true
+}
@@ -0,0 +1,122 @@
+package neox_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+	"github.com/syllabix/neox"
+	"github.com/syllabix/neox/neoxtest"
+)
+
+func TestToJSONValue(t *testing.T) {
+	t.Parallel()
+
+	alice := neoxtest.NewNode(1, []string{"Person"}, map[string]interface{}{"name": "Alice"})
+	bob := neoxtest.NewNode(2, nil, nil)
+	knows := neoxtest.NewRelationship(7, 1, 2, "KNOWS", nil)
+
+	aliceJSON := `{"id":1,"labels":["Person"],"properties":{"name":"Alice"}}`
+	bobJSON := `{"id":2,"labels":[],"properties":{}}`
+	knowsJSON := `{"end":2,"id":7,"properties":{},"start":1,"type":"KNOWS"}`
+
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{name: "Should convert nodes", value: alice, want: aliceJSON},
+		{name: "Should convert nodes without labels nor properties", value: bob, want: bobJSON},
+		{name: "Should convert relationships", value: knows, want: knowsJSON},
+		{
+			name:  "Should convert paths",
+			value: neoxtest.NewPath([]neo4j.Node{alice, bob}, []neo4j.Relationship{knows}),
+			want:  `{"end":` + bobJSON + `,"segments":[{"end":` + bobJSON + `,"relationship":` + knowsJSON + `,"start":` + aliceJSON + `}],"start":` + aliceJSON + `}`,
+		},
+		{
+			name:  "Should convert paths of a single node",
+			value: neoxtest.NewPath([]neo4j.Node{alice}, nil),
+			want:  `{"end":` + aliceJSON + `,"segments":[],"start":` + aliceJSON + `}`,
+		},
+		{
+			name:  "Should convert temporal values",
+			value: []interface{}{neo4j.DurationOf(1, 2, 3, 0), time.Date(2021, 6, 1, 10, 30, 0, 0, time.UTC)},
+			want:  `["P1M2DT3S","2021-06-01T10:30:00Z"]`,
+		},
+		{
+			name:  "Should convert nested values",
+			value: map[string]interface{}{"friends": []interface{}{alice}},
+			want:  `{"friends":[` + aliceJSON + `]}`,
+		},
+		{name: "Should keep other values", value: int64(42), want: `42`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(neox.ToJSONValue(tt.value))
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			if got := string(b); got != tt.want {
+				t.Errorf("ToJSONValue() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONValue(t *testing.T) {
+	t.Parallel()
+
+	alice := neoxtest.NewNode(1, []string{"Person"}, map[string]interface{}{"name": "Alice", "age": int64(42), "score": 1.5})
+	bob := neoxtest.NewNode(2, []string{"Person"}, map[string]interface{}{"tags": []interface{}{"a"}})
+	knows := neoxtest.NewRelationship(7, 2, 1, "KNOWS", map[string]interface{}{"since": int64(2019)})
+
+	tests := []struct {
+		name  string
+		value interface{}
+	}{
+		{name: "Should round trip nodes", value: alice},
+		{name: "Should round trip relationships", value: knows},
+		{name: "Should round trip paths", value: neoxtest.NewPath([]neo4j.Node{alice, bob}, []neo4j.Relationship{knows})},
+		{name: "Should round trip paths of a single node", value: neoxtest.NewPath([]neo4j.Node{bob}, nil)},
+		{
+			name:  "Should round trip maps and lists",
+			value: map[string]interface{}{"user": alice, "ids": []interface{}{int64(1), 2.5, "x", nil, true}},
+		},
+		{
+			name:  "Should keep maps that are not shaped as graph values",
+			value: map[string]interface{}{"id": int64(1), "labels": "Person"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(neox.JSONValue{Value: tt.value})
+			if err != nil {
+				t.Fatalf("JSONValue.MarshalJSON() error = %v", err)
+			}
+			var got neox.JSONValue
+			if err := json.Unmarshal(b, &got); err != nil {
+				t.Fatalf("JSONValue.UnmarshalJSON() error = %v", err)
+			}
+			if !reflect.DeepEqual(neox.ToJSONValue(got.Value), neox.ToJSONValue(tt.value)) {
+				t.Errorf("JSONValue.UnmarshalJSON() got = %#v, want %#v", got.Value, tt.value)
+			}
+		})
+	}
+
+	t.Run("Should decode graph values", func(t *testing.T) {
+		var got neox.JSONValue
+		err := json.Unmarshal([]byte(`{"id": 3, "type": "LIKES", "start": 1, "end": 2, "properties": {"weight": 0.5}}`), &got)
+		if err != nil {
+			t.Fatalf("JSONValue.UnmarshalJSON() error = %v", err)
+		}
+		rel, ok := got.Value.(neo4j.Relationship)
+		if !ok {
+			t.Fatalf("JSONValue.UnmarshalJSON() got = %#v, want a neo4j.Relationship", got.Value)
+		}
+		if rel.Id() != 3 || rel.Type() != "LIKES" || rel.StartId() != 1 || rel.EndId() != 2 || rel.Props()["weight"] != 0.5 {
+			t.Errorf("JSONValue.UnmarshalJSON() got = %+v", rel)
+		}
+	})
+}
@@ -0,0 +1,71 @@
+package neox
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+)
+
+// LogHook is a Hook that writes the statements run through a neox.Driver
+// to a standard library logger
+type LogHook struct {
+	// Logger is the destination of the log lines, the standard logger is used when nil
+	Logger *log.Logger
+
+	// SlowThreshold flags statements that took at least as long to complete as slow.
+	// A zero value disables flagging
+	SlowThreshold time.Duration
+
+	// SlowOnly limits logging to slow statements and failures
+	SlowOnly bool
+}
+
+// BeforeRun is a no-op, statements are logged once they complete
+func (h *LogHook) BeforeRun(ctx context.Context, q *Query) context.Context {
+	return ctx
+}
+
+// AfterRun logs the completed statement
+func (h *LogHook) AfterRun(ctx context.Context, q *Query) {
+	slow := h.SlowThreshold > 0 && q.Duration >= h.SlowThreshold
+	if h.SlowOnly && !slow && q.Err == nil {
+		return
+	}
+
+	var line strings.Builder
+	line.WriteString("neox: ")
+	switch {
+	case q.Err != nil:
+		line.WriteString("query failed")
+	case slow:
+		line.WriteString("slow query")
+	default:
+		line.WriteString("query")
+	}
+	line.WriteString(" in ")
+	line.WriteString(q.Duration.String())
+	if q.Summary != nil {
+		line.WriteString(" (available after ")
+		line.WriteString(q.Summary.ResultAvailableAfter().String())
+		line.WriteString(", consumed after ")
+		line.WriteString(q.Summary.ResultConsumedAfter().String())
+		line.WriteString(")")
+	}
+	line.WriteString(": ")
+	line.WriteString(strings.Join(strings.Fields(q.Cypher), " "))
+
+	if q.Err != nil {
+		h.printf("%s error=%q args=%v", line.String(), q.Err.Error(), q.Args)
+		return
+	}
+	h.printf("%s args=%v", line.String(), q.Args)
+}
+
+func (h *LogHook) printf(format string, v ...interface{}) {
+	if h.Logger == nil {
+		log.Printf(format, v...)
+		return
+	}
+	h.Logger.Printf(format, v...)
+}
@@ -0,0 +1,80 @@
+package neox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogHook_AfterRun(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		hook     LogHook
+		query    Query
+		want     []string
+		wantNone bool
+	}{
+		{
+			name: "Should log completed statements with timings",
+			query: Query{
+				Cypher:   "match (n)\n\treturn n",
+				Args:     Args{"id": 1},
+				Duration: 12 * time.Millisecond,
+				Summary:  &msum{available: 3 * time.Millisecond, consumed: 9 * time.Millisecond},
+			},
+			want: []string{"neox: query in 12ms (available after 3ms, consumed after 9ms): match (n) return n", "args=map[id:1]"},
+		},
+		{
+			name: "Should flag slow statements",
+			hook: LogHook{SlowThreshold: 10 * time.Millisecond},
+			query: Query{
+				Cypher:   "match (n) return n",
+				Duration: 12 * time.Millisecond,
+			},
+			want: []string{"neox: slow query in 12ms: match (n) return n"},
+		},
+		{
+			name: "Should skip fast statements when logging slow statements only",
+			hook: LogHook{SlowThreshold: time.Second, SlowOnly: true},
+			query: Query{
+				Cypher:   "match (n) return n",
+				Duration: 12 * time.Millisecond,
+			},
+			wantNone: true,
+		},
+		{
+			name: "Should always log failures",
+			hook: LogHook{SlowThreshold: time.Second, SlowOnly: true},
+			query: Query{
+				Cypher:   "matchx (n) return n",
+				Duration: time.Millisecond,
+				Err:      errors.New("invalid input"),
+			},
+			want: []string{"neox: query failed in 1ms: matchx (n) return n", `error="invalid input"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			hook := tt.hook
+			hook.Logger = log.New(&buf, "", 0)
+			hook.AfterRun(context.Background(), &tt.query)
+
+			got := buf.String()
+			if tt.wantNone && got != "" {
+				t.Errorf("LogHook.AfterRun() logged %q, want nothing", got)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("LogHook.AfterRun() logged %q, want it to contain %q", got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,317 @@
+package neox
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+	"github.com/syllabix/neox/internal/identifier"
+)
+
+// graphtag is the struct tag declaring how a struct maps to the graph
+const graphtag = "neox"
+
+// entity is the graph mapping of a struct type. A struct maps to a node when it declares
+// a label, and to a relationship carrying properties when it has a field tagged neox:"node"
+// holding the node at the other end of the relationship
+type entity struct {
+	typ   reflect.Type
+	label string
+	key   int
+	props []property
+	rels  []relation
+
+	// node is the index of the field holding the other node of a relationship struct, or -1
+	node    int
+	nodePtr bool
+	nodeOf  *entity
+}
+
+// property is a struct field mapped to a property by its db tag
+type property struct {
+	name  string
+	prop  string
+	index int
+}
+
+// relation is a struct field holding the nodes related to a node, or the relationship
+// structs carrying the properties of the relationships along with the related nodes
+type relation struct {
+	index   int
+	name    string
+	relType string
+	dir     string
+	many    bool
+	ptr     bool
+
+	// node is the mapping of the related nodes, and props the mapping of the relationship
+	// structs when the relation holds relationship structs
+	node  *entity
+	props *entity
+}
+
+// entities caches the mapping of struct types
+var entities sync.Map
+
+// entityOf returns the graph mapping of a struct type
+func entityOf(t reflect.Type) (*entity, error) {
+	if e, ok := entities.Load(t); ok {
+		return e.(*entity), nil
+	}
+	building := make(map[reflect.Type]*entity)
+	e, err := buildEntity(t, building)
+	if err != nil {
+		return nil, err
+	}
+	for t, e := range building {
+		entities.Store(t, e)
+	}
+	return e, nil
+}
+
+func buildEntity(t reflect.Type, building map[reflect.Type]*entity) (*entity, error) {
+	if e, ok := entities.Load(t); ok {
+		return e.(*entity), nil
+	}
+	if e, ok := building[t]; ok {
+		return e, nil
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("neox: %s is not a struct", t)
+	}
+
+	e := &entity{typ: t, key: -1, node: -1}
+	building[t] = e
+
+	var key string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup(graphtag)
+		if !ok {
+			name := field.Tag.Get(neotag)
+			if !field.IsExported() || name == "" || name == "-" {
+				continue
+			}
+			prop, err := identifier.Escape(name)
+			if err != nil {
+				return nil, fmt.Errorf("neox: field %s of %s: %w", field.Name, t, err)
+			}
+			e.props = append(e.props, property{name: name, prop: prop, index: i})
+			continue
+		}
+
+		options, err := parseGraphTag(tag)
+		if err != nil {
+			return nil, fmt.Errorf("neox: field %s of %s: %w", field.Name, t, err)
+		}
+		switch {
+		case options.has("label", "key"):
+			label, err := optionalIdentifier(options["label"])
+			if err != nil {
+				return nil, fmt.Errorf("neox: label of %s: %w", t, err)
+			}
+			e.label, key = label, options["key"]
+		case options.has("rel", "dir"):
+			rel, err := buildRelation(field, i, options, building)
+			if err != nil {
+				return nil, fmt.Errorf("neox: field %s of %s: %w", field.Name, t, err)
+			}
+			e.rels = append(e.rels, rel)
+		case options.has("node"):
+			nt := field.Type
+			if e.nodePtr = nt.Kind() == reflect.Ptr; e.nodePtr {
+				nt = nt.Elem()
+			}
+			node, err := buildEntity(nt, building)
+			if err != nil {
+				return nil, fmt.Errorf("neox: field %s of %s: %w", field.Name, t, err)
+			}
+			e.node, e.nodeOf = i, node
+		default:
+			return nil, fmt.Errorf("neox: field %s of %s: invalid %s tag %q", field.Name, t, graphtag, tag)
+		}
+	}
+
+	if key != "" {
+		for i, p := range e.props {
+			if p.name == key {
+				e.key = i
+			}
+		}
+		if e.key < 0 {
+			return nil, fmt.Errorf("neox: the key %q of %s is not a field with a %s tag", key, t, neotag)
+		}
+		if !t.Field(e.props[e.key].index).Type.Comparable() {
+			return nil, fmt.Errorf("neox: the key %q of %s is not comparable", key, t)
+		}
+	}
+	return e, nil
+}
+
+func buildRelation(field reflect.StructField, index int, options graphTag, building map[reflect.Type]*entity) (relation, error) {
+	rel := relation{index: index, name: field.Name, dir: options["dir"]}
+	switch rel.dir {
+	case "":
+		rel.dir = "out"
+	case "out", "in", "both":
+	default:
+		return rel, fmt.Errorf("invalid direction %q, want out, in or both", rel.dir)
+	}
+
+	relType, err := identifier.Escape(options["rel"])
+	if err != nil {
+		return rel, err
+	}
+	rel.relType = relType
+
+	t := field.Type
+	if rel.many = t.Kind() == reflect.Slice; rel.many {
+		t = t.Elem()
+	}
+	if rel.ptr = t.Kind() == reflect.Ptr; rel.ptr {
+		t = t.Elem()
+	}
+	elem, err := buildEntity(t, building)
+	if err != nil {
+		return rel, err
+	}
+	if elem.node >= 0 {
+		rel.props, rel.node = elem, elem.nodeOf
+	} else {
+		rel.node = elem
+	}
+	return rel, nil
+}
+
+// graphTag holds the options of a neox tag, options without a value are set to ""
+type graphTag map[string]string
+
+func parseGraphTag(tag string) (graphTag, error) {
+	options := make(graphTag)
+	for _, option := range strings.Split(tag, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(option), "=")
+		switch name {
+		case "label", "key", "rel", "dir", "node":
+			options[name] = value
+		default:
+			return nil, fmt.Errorf("unknown option %q in %s tag", option, graphtag)
+		}
+	}
+	return options, nil
+}
+
+// has reports whether the tag only holds the provided options, and holds at least one of them
+func (g graphTag) has(names ...string) bool {
+	found := 0
+	for _, name := range names {
+		if _, ok := g[name]; ok {
+			found++
+		}
+	}
+	return found > 0 && found == len(g)
+}
+
+func optionalIdentifier(name string) (string, error) {
+	if name == "" {
+		return "", nil
+	}
+	return identifier.Escape(name)
+}
+
+// keyProp returns the escaped key property of a node struct
+func (e *entity) keyProp() string {
+	return e.props[e.key].prop
+}
+
+// pattern returns a node pattern for the variable, labeled when the struct declares a label
+func (e *entity) pattern(variable string) string {
+	if e.label == "" {
+		return "(" + variable + ")"
+	}
+	return "(" + variable + ":" + e.label + ")"
+}
+
+// properties returns the mapped fields of the struct value v by property name
+func (e *entity) properties(v reflect.Value) map[string]interface{} {
+	props := make(map[string]interface{}, len(e.props))
+	for _, p := range e.props {
+		props[p.name] = v.Field(p.index).Interface()
+	}
+	return props
+}
+
+// assign sets the mapped fields of the struct value v to the provided properties
+func (e *entity) assign(v reflect.Value, props map[string]interface{}) {
+	for _, p := range e.props {
+		if value, ok := props[p.name]; ok {
+			setValue(v.Field(p.index), value)
+		}
+	}
+}
+
+// setValue sets the field to the value when they are of the same kind, mapping nodes and
+// relationships to structs, and lists to slices element by element. It reports whether
+// the field was set
+func setValue(field reflect.Value, value interface{}) bool {
+	if value == nil || !field.CanSet() {
+		return false
+	}
+
+	switch graph := value.(type) {
+	case neo4j.Node:
+		return setEntity(field, graph.Props())
+	case neo4j.Relationship:
+		return setEntity(field, graph.Props())
+	}
+
+	v := reflect.ValueOf(value)
+	if field.Kind() == reflect.Interface && v.Type().AssignableTo(field.Type()) {
+		field.Set(v)
+		return true
+	}
+	if v.Kind() != field.Kind() {
+		return false
+	}
+	if v.Kind() == reflect.Slice && !v.Type().AssignableTo(field.Type()) {
+		s := reflect.MakeSlice(field.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			if !setValue(s.Index(i), v.Index(i).Interface()) {
+				return false
+			}
+		}
+		field.Set(s)
+		return true
+	}
+	if !v.Type().ConvertibleTo(field.Type()) {
+		return false
+	}
+	field.Set(v.Convert(field.Type()))
+	return true
+}
+
+// setEntity sets a struct, or pointer to struct, field to the mapping of the properties
+func setEntity(field reflect.Value, props map[string]interface{}) bool {
+	t := field.Type()
+	ptr := t.Kind() == reflect.Ptr
+	if ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	e, err := entityOf(t)
+	if err != nil {
+		return false
+	}
+
+	v := reflect.New(t)
+	e.assign(v.Elem(), props)
+	if ptr {
+		field.Set(v)
+	} else {
+		field.Set(v.Elem())
+	}
+	return true
+}
@@ -0,0 +1,122 @@
+package metrics
+
+import (
+	"bufio"
+	"expvar"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+// ContentType is the content type of the Prometheus text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// ServeHTTP writes the collected metrics in the Prometheus text exposition format
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	c.WriteTo(w)
+}
+
+// WriteTo writes the collected metrics to w in the Prometheus text exposition format
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	s := c.Snapshot()
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+
+	cw.help("neox_queries_total", "counter", "Number of statements run, by access mode and neo4j status code.")
+	for _, q := range s.Queries {
+		cw.printf("neox_queries_total{mode=%q,status=%q} %d\n", q.Mode, q.Status, q.Value)
+	}
+
+	cw.help("neox_query_duration_seconds", "histogram", "Latency of statements until their result was consumed, by access mode.")
+	for _, mode := range modes(s) {
+		h, ok := s.Latency[mode]
+		if !ok {
+			continue
+		}
+		for i, bound := range h.Buckets {
+			cw.printf("neox_query_duration_seconds_bucket{mode=%q,le=%q} %d\n", mode, formatFloat(bound), h.Counts[i])
+		}
+		cw.printf("neox_query_duration_seconds_bucket{mode=%q,le=\"+Inf\"} %d\n", mode, h.Count)
+		cw.printf("neox_query_duration_seconds_sum{mode=%q} %s\n", mode, formatFloat(h.Sum))
+		cw.printf("neox_query_duration_seconds_count{mode=%q} %d\n", mode, h.Count)
+	}
+
+	cw.help("neox_transactions_total", "counter", "Number of transactions run through the transaction helpers, by access mode and neo4j status code.")
+	for _, tx := range s.Transactions {
+		cw.printf("neox_transactions_total{mode=%q,status=%q} %d\n", tx.Mode, tx.Status, tx.Value)
+	}
+
+	cw.help("neox_transaction_retries_total", "counter", "Number of times a transaction was retried by the driver, by access mode.")
+	for _, mode := range modes(s) {
+		if _, ok := s.Retries[mode]; !ok {
+			continue
+		}
+		cw.printf("neox_transaction_retries_total{mode=%q} %d\n", mode, s.Retries[mode])
+	}
+
+	cw.help("neox_open_sessions", "gauge", "Number of open sessions, by access mode.")
+	for _, mode := range modes(s) {
+		if _, ok := s.OpenSessions[mode]; !ok {
+			continue
+		}
+		cw.printf("neox_open_sessions{mode=%q} %d\n", mode, s.OpenSessions[mode])
+	}
+
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+	return cw.n, cw.err
+}
+
+// Publish publishes the collected metrics as an expvar with the provided name.
+// Like expvar.Publish, it panics if the name is already in use
+func (c *Collector) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return c.Snapshot()
+	}))
+}
+
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (cw *countingWriter) printf(format string, args ...interface{}) {
+	if cw.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(cw.w, format, args...)
+	cw.n += int64(n)
+	cw.err = err
+}
+
+func (cw *countingWriter) help(name, kind, help string) {
+	cw.printf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// modes returns the access modes of the snapshot in a stable order
+func modes(s Snapshot) []string {
+	seen := make(map[string]bool)
+	for mode := range s.Latency {
+		seen[mode] = true
+	}
+	for mode := range s.Retries {
+		seen[mode] = true
+	}
+	for mode := range s.OpenSessions {
+		seen[mode] = true
+	}
+	keys := make([]string, 0, len(seen))
+	for mode := range seen {
+		keys = append(keys, mode)
+	}
+	sort.Strings(keys)
+	return keys
+}
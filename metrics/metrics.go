@@ -0,0 +1,233 @@
+// Package metrics collects usage metrics of a neox.Driver through its hook mechanism.
+//
+// A Collector counts statements, transactions and retries, records statement latencies and
+// tracks open sessions, labelled by access mode and neo4j status code. The collected metrics
+// can be scraped by Prometheus in the text exposition format, or published through expvar
+//
+//	collector := metrics.New()
+//	driver.Use(collector)
+//
+//	http.Handle("/metrics", collector)
+//	collector.Publish("neox")
+package metrics
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+	"github.com/syllabix/neox"
+)
+
+// StatusOK is the status of statements and transactions that succeeded
+const StatusOK = "ok"
+
+// StatusError is the status of failures that do not carry a neo4j status code
+const StatusError = "error"
+
+// DefaultBuckets are the upper bounds, in seconds, of the statement latency histogram
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// key identifies a series by access mode and status
+type key struct {
+	mode   string
+	status string
+}
+
+// Histogram is a snapshot of the latencies of statements run in an access mode
+type Histogram struct {
+	// Buckets are the upper bounds of the histogram, in seconds
+	Buckets []float64 `json:"buckets"`
+
+	// Counts are the cumulative number of observations per bucket
+	Counts []uint64 `json:"counts"`
+
+	// Count is the total number of observations
+	Count uint64 `json:"count"`
+
+	// Sum is the sum of the observations, in seconds
+	Sum float64 `json:"sum"`
+}
+
+// Series is a counter labelled by access mode and status
+type Series struct {
+	Mode   string `json:"mode"`
+	Status string `json:"status"`
+	Value  uint64 `json:"value"`
+}
+
+// Snapshot is a point in time copy of the collected metrics
+type Snapshot struct {
+	Queries      []Series             `json:"queries"`
+	Transactions []Series             `json:"transactions"`
+	Retries      map[string]uint64    `json:"retries"`
+	OpenSessions map[string]int64     `json:"open_sessions"`
+	Latency      map[string]Histogram `json:"latency"`
+}
+
+// Collector is a neox hook collecting usage metrics of a driver.
+// It is safe for concurrent use
+type Collector struct {
+	buckets []float64
+
+	mu           sync.Mutex
+	queries      map[key]uint64
+	transactions map[key]uint64
+	retries      map[string]uint64
+	sessions     map[string]int64
+	latency      map[string]*Histogram
+}
+
+// New returns a Collector recording latencies with the provided histogram
+// buckets, in seconds. The DefaultBuckets are used when none are provided
+func New(buckets ...float64) *Collector {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+
+	return &Collector{
+		buckets:      buckets,
+		queries:      make(map[key]uint64),
+		transactions: make(map[key]uint64),
+		retries:      make(map[string]uint64),
+		sessions:     make(map[string]int64),
+		latency:      make(map[string]*Histogram),
+	}
+}
+
+var _ interface {
+	neox.TransactionHook
+	neox.SessionHook
+} = new(Collector)
+
+// BeforeRun is a no-op, statements are recorded once they complete
+func (c *Collector) BeforeRun(ctx context.Context, q *neox.Query) context.Context {
+	return ctx
+}
+
+// AfterRun records the completed statement
+func (c *Collector) AfterRun(ctx context.Context, q *neox.Query) {
+	mode := accessMode(q.AccessMode)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.queries[key{mode, status(q.Err)}]++
+
+	h, ok := c.latency[mode]
+	if !ok {
+		h = &Histogram{
+			Buckets: c.buckets,
+			Counts:  make([]uint64, len(c.buckets)),
+		}
+		c.latency[mode] = h
+	}
+	seconds := q.Duration.Seconds()
+	for i, bound := range h.Buckets {
+		if seconds <= bound {
+			h.Counts[i]++
+		}
+	}
+	h.Count++
+	h.Sum += seconds
+}
+
+// BeforeTransaction is a no-op, transactions are recorded once they complete
+func (c *Collector) BeforeTransaction(ctx context.Context, tx *neox.TransactionInfo) context.Context {
+	return ctx
+}
+
+// AfterTransaction records the completed transaction and its retries
+func (c *Collector) AfterTransaction(ctx context.Context, tx *neox.TransactionInfo) {
+	mode := accessMode(tx.AccessMode)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.transactions[key{mode, status(tx.Err)}]++
+	if tx.Attempts > 1 {
+		c.retries[mode] += uint64(tx.Attempts - 1)
+	}
+}
+
+// SessionOpened records an open session
+func (c *Collector) SessionOpened(mode neo4j.AccessMode) {
+	c.mu.Lock()
+	c.sessions[accessMode(mode)]++
+	c.mu.Unlock()
+}
+
+// SessionClosed records a closed session
+func (c *Collector) SessionClosed(mode neo4j.AccessMode) {
+	c.mu.Lock()
+	c.sessions[accessMode(mode)]--
+	c.mu.Unlock()
+}
+
+// Snapshot returns a copy of the collected metrics
+func (c *Collector) Snapshot() Snapshot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := Snapshot{
+		Queries:      series(c.queries),
+		Transactions: series(c.transactions),
+		Retries:      make(map[string]uint64, len(c.retries)),
+		OpenSessions: make(map[string]int64, len(c.sessions)),
+		Latency:      make(map[string]Histogram, len(c.latency)),
+	}
+	for mode, n := range c.retries {
+		s.Retries[mode] = n
+	}
+	for mode, n := range c.sessions {
+		s.OpenSessions[mode] = n
+	}
+	for mode, h := range c.latency {
+		s.Latency[mode] = Histogram{
+			Buckets: h.Buckets,
+			Counts:  append([]uint64(nil), h.Counts...),
+			Count:   h.Count,
+			Sum:     h.Sum,
+		}
+	}
+	return s
+}
+
+// series returns the counters sorted by access mode and status
+func series(counters map[key]uint64) []Series {
+	s := make([]Series, 0, len(counters))
+	for k, v := range counters {
+		s = append(s, Series{Mode: k.mode, Status: k.status, Value: v})
+	}
+	sort.Slice(s, func(i, j int) bool {
+		if s[i].Mode != s[j].Mode {
+			return s[i].Mode < s[j].Mode
+		}
+		return s[i].Status < s[j].Status
+	})
+	return s
+}
+
+// status returns the neo4j status code of err, StatusOK when err is nil
+// and StatusError when err does not carry a status code
+func status(err error) string {
+	if err == nil {
+		return StatusOK
+	}
+	var classified *neox.Error
+	if errors.As(err, &classified) && classified.Code != "" {
+		return classified.Code
+	}
+	return StatusError
+}
+
+func accessMode(mode neo4j.AccessMode) string {
+	if mode == neo4j.AccessModeRead {
+		return "read"
+	}
+	return "write"
+}
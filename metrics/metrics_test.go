@@ -0,0 +1,159 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+	"github.com/syllabix/neox"
+)
+
+func TestCollector(t *testing.T) {
+	t.Parallel()
+
+	c := New(0.01, 0.1)
+	ctx := context.Background()
+	deadlock := neox.ClassifyError(&fdberr{"Neo.TransientError.Transaction.DeadlockDetected"})
+
+	c.SessionOpened(neo4j.AccessModeRead)
+	c.SessionOpened(neo4j.AccessModeWrite)
+	c.SessionOpened(neo4j.AccessModeWrite)
+	c.SessionClosed(neo4j.AccessModeWrite)
+
+	c.AfterRun(ctx, &neox.Query{AccessMode: neo4j.AccessModeRead, Duration: 5 * time.Millisecond})
+	c.AfterRun(ctx, &neox.Query{AccessMode: neo4j.AccessModeRead, Duration: 50 * time.Millisecond})
+	c.AfterRun(ctx, &neox.Query{AccessMode: neo4j.AccessModeWrite, Duration: time.Second, Err: deadlock})
+	c.AfterRun(ctx, &neox.Query{AccessMode: neo4j.AccessModeWrite, Duration: time.Millisecond, Err: errors.New("boom")})
+
+	c.AfterTransaction(ctx, &neox.TransactionInfo{AccessMode: neo4j.AccessModeWrite, Attempts: 3})
+	c.AfterTransaction(ctx, &neox.TransactionInfo{AccessMode: neo4j.AccessModeWrite, Attempts: 1, Err: deadlock})
+
+	s := c.Snapshot()
+
+	wantQueries := []Series{
+		{"read", StatusOK, 2},
+		{"write", "Neo.TransientError.Transaction.DeadlockDetected", 1},
+		{"write", StatusError, 1},
+	}
+	if fmt.Sprint(s.Queries) != fmt.Sprint(wantQueries) {
+		t.Errorf("Snapshot().Queries = %v, want %v", s.Queries, wantQueries)
+	}
+
+	wantTransactions := []Series{
+		{"write", "Neo.TransientError.Transaction.DeadlockDetected", 1},
+		{"write", StatusOK, 1},
+	}
+	if fmt.Sprint(s.Transactions) != fmt.Sprint(wantTransactions) {
+		t.Errorf("Snapshot().Transactions = %v, want %v", s.Transactions, wantTransactions)
+	}
+
+	if s.Retries["write"] != 2 {
+		t.Errorf("Snapshot().Retries = %v, want 2 write retries", s.Retries)
+	}
+	if s.OpenSessions["read"] != 1 || s.OpenSessions["write"] != 1 {
+		t.Errorf("Snapshot().OpenSessions = %v, want 1 of each", s.OpenSessions)
+	}
+
+	read := s.Latency["read"]
+	if fmt.Sprint(read.Counts) != "[1 2]" || read.Count != 2 {
+		t.Errorf("Snapshot().Latency[read] = %+v", read)
+	}
+	write := s.Latency["write"]
+	if fmt.Sprint(write.Counts) != "[1 1]" || write.Count != 2 {
+		t.Errorf("Snapshot().Latency[write] = %+v", write)
+	}
+}
+
+func TestCollector_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	c := New(0.01)
+	c.SessionOpened(neo4j.AccessModeRead)
+	c.AfterRun(context.Background(), &neox.Query{AccessMode: neo4j.AccessModeRead, Duration: 5 * time.Millisecond})
+	c.AfterTransaction(context.Background(), &neox.TransactionInfo{AccessMode: neo4j.AccessModeRead, Attempts: 2})
+
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	if ct := rec.Header().Get("Content-Type"); ct != ContentType {
+		t.Errorf("Content-Type = %v, want %v", ct, ContentType)
+	}
+
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE neox_queries_total counter",
+		`neox_queries_total{mode="read",status="ok"} 1`,
+		`neox_query_duration_seconds_bucket{mode="read",le="0.01"} 1`,
+		`neox_query_duration_seconds_bucket{mode="read",le="+Inf"} 1`,
+		`neox_query_duration_seconds_sum{mode="read"} 0.005`,
+		`neox_query_duration_seconds_count{mode="read"} 1`,
+		`neox_transactions_total{mode="read",status="ok"} 1`,
+		`neox_transaction_retries_total{mode="read"} 1`,
+		`neox_open_sessions{mode="read"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("ServeHTTP() body is missing %q\n%s", want, body)
+		}
+	}
+
+	var buf bytes.Buffer
+	n, err := c.WriteTo(&buf)
+	if err != nil || n != int64(buf.Len()) {
+		t.Errorf("WriteTo() = %d, %v, wrote %d bytes", n, err, buf.Len())
+	}
+}
+
+func TestCollector_Driver(t *testing.T) {
+	t.Parallel()
+
+	c := New()
+	driver := &neox.Driver{Driver: &fdriver{}}
+	driver.Use(c)
+
+	session, err := driver.Sessionx(neo4j.AccessModeWrite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Snapshot().OpenSessions["write"]; got != 1 {
+		t.Errorf("open write sessions = %d, want 1", got)
+	}
+
+	session.Close()
+	session.Close()
+	if got := c.Snapshot().OpenSessions["write"]; got != 0 {
+		t.Errorf("open write sessions = %d, want 0", got)
+	}
+}
+
+// fake neo4j.Driver
+type fdriver struct {
+	neo4j.Driver
+}
+
+func (d *fdriver) Session(mode neo4j.AccessMode, bookmarks ...string) (neo4j.Session, error) {
+	return &fsession{}, nil
+}
+
+// fake neo4j.Session
+type fsession struct {
+	neo4j.Session
+}
+
+func (s *fsession) Close() error { return nil }
+
+// fake gobolt.DatabaseError
+type fdberr struct {
+	code string
+}
+
+func (e *fdberr) BoltError() bool        { return true }
+func (e *fdberr) Classification() string { return strings.Split(e.code, ".")[1] }
+func (e *fdberr) Code() string           { return e.code }
+func (e *fdberr) Message() string        { return "failure" }
+func (e *fdberr) Error() string          { return e.code }
@@ -0,0 +1,30 @@
+// Package migrate applies versioned migrations to a neo4j database, such as the creation
+// of indexes and constraints or data backfills. Migrations are cypher files, usually
+// embedded in the binary, or Go functions
+//
+//	//go:embed migrations
+//	var files embed.FS
+//
+//	migrations, err := migrate.Load(files, "migrations")
+//	if err != nil {
+//		return err
+//	}
+//	m, err := migrate.New(driver, migrations...)
+//	if err != nil {
+//		return err
+//	}
+//	applied, err := m.Up(ctx)
+//
+// Files are named after the version and the name of their migration, followed by .up.cypher
+// for the statements applying it and by .down.cypher for those reverting it, ie:
+// 20240101120000_user_email_index.up.cypher. Statements are separated by semicolons, and
+// each runs in its own transaction, as neo4j does not allow schema and data changes in a
+// single transaction.
+//
+// Applied migrations are recorded on :__NeoxMigration nodes along with the checksum of their
+// statements, a migration whose statements changed once applied is reported as an error.
+// A :__NeoxMigrationLock node prevents concurrent runs of Up and Down. Uniqueness constraints
+// on the name of the lock and on the version of the records are created before taking the lock.
+// Statements are not cancelled by the context passed to Up and Down, it is checked between
+// migrations and between their statements
+package migrate
@@ -0,0 +1,178 @@
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/syllabix/neox"
+)
+
+// Func is a migration written in Go. It receives a write session, and may run as many
+// transactions as it needs
+type Func func(ctx context.Context, session *neox.Session) error
+
+// Migration is a versioned change of the database. It is applied by its Up statements
+// or UpFunc, and reverted by its Down statements or DownFunc
+type Migration struct {
+	Version int64
+	Name    string
+
+	Up   []string
+	Down []string
+
+	UpFunc   Func
+	DownFunc Func
+}
+
+// Checksum returns the checksum of the Up statements of the migration, or an empty
+// string for migrations written in Go
+func (m Migration) Checksum() string {
+	if len(m.Up) == 0 {
+		return ""
+	}
+	h := sha256.New()
+	for _, stmt := range m.Up {
+		h.Write([]byte(stmt))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (m Migration) String() string {
+	return strconv.FormatInt(m.Version, 10) + "_" + m.Name
+}
+
+func (m Migration) reversible() bool {
+	return len(m.Down) > 0 || m.DownFunc != nil
+}
+
+// filename matches the files of migrations, capturing their version, name and direction
+var filename = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.cypher$`)
+
+// Load reads the migrations of the cypher files in the directory of fsys, ignoring other
+// files. A migration must have an up file, its down file is optional
+func Load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := filename.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migrate: %s: invalid version: %w", entry.Name(), err)
+		}
+		b, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("migrate: %w", err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("migrate: version %d is used by %s and %s", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = Split(string(b))
+		} else {
+			m.Down = Split(string(b))
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if len(m.Up) == 0 {
+			return nil, fmt.Errorf("migrate: %s has no up statement", m)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Split splits a script into its statements, separated by semicolons outside of strings,
+// quoted identifiers and comments. Statements holding nothing but comments are dropped
+func Split(script string) []string {
+	var (
+		statements []string
+		current    strings.Builder
+		code       bool
+		quote      rune
+		escaped    bool
+	)
+	runes := []rune(script)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		var next rune
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		switch {
+		case quote != 0:
+			switch {
+			case escaped:
+				escaped = false
+			case r == '\\' && quote != '`':
+				escaped = true
+			case r == quote:
+				quote = 0
+			}
+		case r == '/' && next == '/':
+			end := i
+			for end < len(runes) && runes[end] != '\n' {
+				end++
+			}
+			current.WriteString(string(runes[i:end]))
+			i = end - 1
+			continue
+		case r == '/' && next == '*':
+			end := i + 2
+			for end < len(runes) && !(runes[end-1] == '*' && runes[end] == '/' && end > i+2) {
+				end++
+			}
+			if end < len(runes) {
+				end++
+			}
+			current.WriteString(string(runes[i:end]))
+			i = end - 1
+			continue
+		case r == ';':
+			if stmt := strings.TrimSpace(current.String()); code {
+				statements = append(statements, stmt)
+			}
+			current.Reset()
+			code = false
+			continue
+		case r == '"' || r == '\'' || r == '`':
+			quote = r
+			code = true
+		case !unicode.IsSpace(r):
+			code = true
+		}
+		current.WriteRune(r)
+	}
+	if stmt := strings.TrimSpace(current.String()); code {
+		statements = append(statements, stmt)
+	}
+	return statements
+}
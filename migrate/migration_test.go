@@ -0,0 +1,121 @@
+package migrate_test
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/syllabix/neox/migrate"
+)
+
+func TestSplit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{
+			name:   "Should split statements on semicolons",
+			script: "create index for (u:User) on (u.email);\n\nmatch (u:User) set u.active = true;\n",
+			want:   []string{"create index for (u:User) on (u.email)", "match (u:User) set u.active = true"},
+		},
+		{
+			name:   "Should keep the last statement without a semicolon",
+			script: "return 1;\nreturn 2",
+			want:   []string{"return 1", "return 2"},
+		},
+		{
+			name:   "Should ignore semicolons in strings and identifiers",
+			script: `match (u:User {name: "a;b"}) set u.note = 'it\'s; fine', u.` + "`x;y`" + ` = 1;`,
+			want:   []string{`match (u:User {name: "a;b"}) set u.note = 'it\'s; fine', u.` + "`x;y`" + ` = 1`},
+		},
+		{
+			name:   "Should ignore semicolons in comments",
+			script: "// create users; then index them\ncreate (u:User);\n/* done; */",
+			want:   []string{"// create users; then index them\ncreate (u:User)"},
+		},
+		{
+			name:   "Should drop empty statements",
+			script: " ; ;\n// nothing\n;",
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := migrate.Split(tt.script); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Split() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		files   fstest.MapFS
+		want    []migrate.Migration
+		wantErr bool
+	}{
+		{
+			name: "Should load migrations ordered by version",
+			files: fstest.MapFS{
+				"migrations/2_backfill.up.cypher":          {Data: []byte("match (u:User) set u.active = true")},
+				"migrations/1_user_email.up.cypher":        {Data: []byte("create index a;\ncreate index b;")},
+				"migrations/1_user_email.down.cypher":      {Data: []byte("drop index a; drop index b;")},
+				"migrations/README.md":                     {Data: []byte("ignored")},
+				"migrations/10_constraints.up.cypher":      {Data: []byte("create constraint c")},
+				"migrations/nested/3_ignored.up.cypher":    {Data: []byte("return 1")},
+				"migrations/11_no_statement.down.cypher.b": {Data: []byte("return 1")},
+			},
+			want: []migrate.Migration{
+				{Version: 1, Name: "user_email", Up: []string{"create index a", "create index b"}, Down: []string{"drop index a", "drop index b"}},
+				{Version: 2, Name: "backfill", Up: []string{"match (u:User) set u.active = true"}},
+				{Version: 10, Name: "constraints", Up: []string{"create constraint c"}},
+			},
+		},
+		{
+			name: "Should reject migrations without up statements",
+			files: fstest.MapFS{
+				"migrations/1_user_email.down.cypher": {Data: []byte("drop index a")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should reject versions used twice",
+			files: fstest.MapFS{
+				"migrations/1_a.up.cypher": {Data: []byte("return 1")},
+				"migrations/1_b.up.cypher": {Data: []byte("return 2")},
+			},
+			wantErr: true,
+		},
+		{name: "Should fail on missing directories", files: fstest.MapFS{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := migrate.Load(tt.files, "migrations")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) && !(len(got) == 0 && len(tt.want) == 0) {
+				t.Errorf("Load() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigration_Checksum(t *testing.T) {
+	t.Parallel()
+
+	a := migrate.Migration{Up: []string{"create index a", "create index b"}}
+	b := migrate.Migration{Up: []string{"create index a", "create index b "}}
+	if a.Checksum() == "" || a.Checksum() == b.Checksum() {
+		t.Errorf("Migration.Checksum() got = %q and %q, want distinct checksums", a.Checksum(), b.Checksum())
+	}
+	if got := (migrate.Migration{}).Checksum(); got != "" {
+		t.Errorf("Migration.Checksum() got = %q, want an empty checksum", got)
+	}
+}
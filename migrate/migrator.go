@@ -0,0 +1,431 @@
+package migrate
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+	"github.com/syllabix/neox"
+)
+
+var (
+	// ErrLocked is returned when another runner holds the migration lock
+	ErrLocked = errors.New("migrate: the migration lock is held by another runner")
+
+	// ErrChecksumMismatch is returned when the statements of an applied migration changed
+	ErrChecksumMismatch = errors.New("migrate: checksum mismatch")
+
+	// ErrIrreversible is returned when reverting a migration without down statements nor DownFunc
+	ErrIrreversible = errors.New("migrate: irreversible migration")
+
+	// ErrUnknownMigration is returned when reverting an applied migration that is not known
+	ErrUnknownMigration = errors.New("migrate: unknown migration")
+)
+
+// statements recording applied migrations and locking runs
+const (
+	// the constraints keep a single lock node, which concurrent runners would otherwise both
+	// create on a database where it does not exist yet, and a single record per version.
+	// They are written in the syntax supported by neo4j 3.5 and 4, which fails when the
+	// constraint exists
+	lockConstraintStatement    = `create constraint on (l:__NeoxMigrationLock) assert l.name is unique`
+	versionConstraintStatement = `create constraint on (m:__NeoxMigration) assert m.version is unique`
+
+	appliedStatement = `match (m:__NeoxMigration) return m.version as version, m.name as name, ` +
+		`m.checksum as checksum, m.applied_at as applied_at order by m.version`
+	recordStatement = `create (m:__NeoxMigration {version: $version, name: $name, checksum: $checksum, applied_at: datetime()})`
+	forgetStatement = `match (m:__NeoxMigration {version: $version}) delete m`
+
+	// lockStatement sets a property of the lock node first, taking the write lock of the node
+	// so that its owner is read once concurrent runners committed
+	lockStatement = `merge (l:__NeoxMigrationLock {name: "migrate"}) set l.touched_at = timestamp() ` +
+		`with l where l.owner is null or l.owner = $owner ` +
+		`set l.owner = $owner, l.locked_at = datetime() return l.owner as owner`
+	unlockStatement      = `match (l:__NeoxMigrationLock {name: "migrate", owner: $owner}) remove l.owner, l.locked_at`
+	forceUnlockStatement = `match (l:__NeoxMigrationLock {name: "migrate"}) remove l.owner, l.locked_at`
+)
+
+// codes of the failures reported when creating a constraint that exists
+var existingConstraint = map[string]bool{
+	"Neo.ClientError.Schema.ConstraintAlreadyExists":           true,
+	"Neo.ClientError.Schema.EquivalentSchemaRuleAlreadyExists": true,
+}
+
+// Status is the state of a migration in the database
+type Status struct {
+	Migration Migration
+
+	// Applied is set when the migration was applied, at AppliedAt with the recorded Checksum
+	Applied   bool
+	AppliedAt time.Time
+	Checksum  string
+
+	// Missing is set when the migration was applied but is not among the known migrations,
+	// only its version and name are then set
+	Missing bool
+}
+
+// applied is a migration recorded in the database
+type applied struct {
+	Version   int64     `db:"version"`
+	Name      string    `db:"name"`
+	Checksum  string    `db:"checksum"`
+	AppliedAt time.Time `db:"applied_at"`
+}
+
+// Migrator applies and reverts migrations
+type Migrator struct {
+	// DryRun, when set, reports the migrations Up and Down would run without running them
+	DryRun bool
+
+	// Logger, when set, logs the migrations and the statements run
+	Logger *log.Logger
+
+	// Owner identifies the runner holding the lock, a random id prefixed with the host name by default
+	Owner string
+
+	driver     *neox.Driver
+	migrations []Migration
+}
+
+// New returns a migrator for the provided migrations, failing when a version is used twice
+// or a migration has neither up statements nor an UpFunc
+func New(driver *neox.Driver, migrations ...Migration) (*Migrator, error) {
+	sorted := append([]Migration(nil), migrations...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+	for i, m := range sorted {
+		if len(m.Up) == 0 && m.UpFunc == nil {
+			return nil, fmt.Errorf("migrate: %s has neither up statements nor an UpFunc", m)
+		}
+		if i > 0 && sorted[i-1].Version == m.Version {
+			return nil, fmt.Errorf("migrate: version %d is used by %s and %s", m.Version, sorted[i-1].Name, m.Name)
+		}
+	}
+
+	return &Migrator{
+		Owner:      owner(),
+		driver:     driver,
+		migrations: sorted,
+	}, nil
+}
+
+func owner() string {
+	host, _ := os.Hostname()
+	b := make([]byte, 4)
+	rand.Read(b)
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hex.EncodeToString(b))
+}
+
+// Migrations returns the known migrations ordered by version
+func (m *Migrator) Migrations() []Migration {
+	return append([]Migration(nil), m.migrations...)
+}
+
+// Status returns the status of the known migrations, followed by those that were
+// applied but are not known, ordered by version
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	session, err := m.driver.Sessionx(neo4j.AccessModeRead)
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	records, err := m.applied(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+	return m.status(records), nil
+}
+
+// Up applies every pending migration, returning those applied
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	return m.UpTo(ctx, math.MaxInt64)
+}
+
+// UpTo applies the pending migrations up to the provided version included, returning
+// those applied. Migrations are applied in order, pending migrations older than an
+// applied migration included
+func (m *Migrator) UpTo(ctx context.Context, version int64) ([]Migration, error) {
+	return m.run(ctx, "apply", func(status []Status) ([]Migration, error) {
+		var plan []Migration
+		for _, s := range status {
+			if !s.Applied && s.Migration.Version <= version {
+				plan = append(plan, s.Migration)
+			}
+		}
+		return plan, nil
+	}, m.apply)
+}
+
+// Down reverts the provided number of applied migrations, the latest first, returning
+// those reverted
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	return m.run(ctx, "revert", func(status []Status) ([]Migration, error) {
+		var versions []Status
+		for i := len(status) - 1; i >= 0 && len(versions) < steps; i-- {
+			if status[i].Applied {
+				versions = append(versions, status[i])
+			}
+		}
+		return revertible(versions)
+	}, m.revert)
+}
+
+// DownTo reverts the applied migrations newer than the provided version, the latest first,
+// returning those reverted
+func (m *Migrator) DownTo(ctx context.Context, version int64) ([]Migration, error) {
+	return m.run(ctx, "revert", func(status []Status) ([]Migration, error) {
+		var versions []Status
+		for i := len(status) - 1; i >= 0; i-- {
+			if status[i].Applied && status[i].Migration.Version > version {
+				versions = append(versions, status[i])
+			}
+		}
+		return revertible(versions)
+	}, m.revert)
+}
+
+// ForceUnlock releases the migration lock whoever holds it, to recover from a runner
+// that stopped without releasing it
+func (m *Migrator) ForceUnlock(ctx context.Context) error {
+	session, err := m.driver.Sessionx(neo4j.AccessModeWrite)
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+	return m.exec(ctx, session, forceUnlockStatement, nil)
+}
+
+func revertible(status []Status) ([]Migration, error) {
+	plan := make([]Migration, len(status))
+	for i, s := range status {
+		if s.Missing {
+			return nil, fmt.Errorf("%w: %s is applied but not known", ErrUnknownMigration, s.Migration)
+		}
+		if !s.Migration.reversible() {
+			return nil, fmt.Errorf("%w: %s", ErrIrreversible, s.Migration)
+		}
+		plan[i] = s.Migration
+	}
+	return plan, nil
+}
+
+// run plans migrations from their status and runs them with step, holding the lock.
+// It stops before the next migration once the context is done
+func (m *Migrator) run(ctx context.Context, verb string, plan func([]Status) ([]Migration, error), step func(context.Context, *neox.Session, Migration) error) (done []Migration, err error) {
+	session, err := m.driver.Sessionx(neo4j.AccessModeWrite)
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	if !m.DryRun {
+		if err := m.constrain(ctx, session); err != nil {
+			return nil, err
+		}
+		if err := m.lock(ctx, session); err != nil {
+			return nil, err
+		}
+		defer func() {
+			if uerr := m.exec(context.Background(), session, unlockStatement, neox.Args{"owner": m.Owner}); err == nil {
+				err = uerr
+			}
+		}()
+	}
+
+	records, err := m.applied(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+	status := m.status(records)
+	for _, s := range status {
+		if s.Applied && !s.Missing && s.Checksum != s.Migration.Checksum() {
+			return nil, fmt.Errorf("%w: %s was applied with checksum %q, its statements now have checksum %q",
+				ErrChecksumMismatch, s.Migration, s.Checksum, s.Migration.Checksum())
+		}
+	}
+
+	migrations, err := plan(status)
+	if err != nil {
+		return nil, err
+	}
+	if m.DryRun {
+		for _, migration := range migrations {
+			m.logf("dry run: %s %s", verb, migration)
+		}
+		return migrations, nil
+	}
+	for _, migration := range migrations {
+		if err := ctx.Err(); err != nil {
+			return done, err
+		}
+		if err := step(ctx, session, migration); err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// constrain creates the constraints the lock and the records of migrations rely on
+func (m *Migrator) constrain(ctx context.Context, session *neox.Session) error {
+	for _, stmt := range []string{lockConstraintStatement, versionConstraintStatement} {
+		var nerr *neox.Error
+		if err := m.exec(ctx, session, stmt, nil); err != nil && !(errors.As(err, &nerr) && existingConstraint[nerr.Code]) {
+			return err
+		}
+	}
+	return nil
+}
+
+// lock takes the migration lock, a runner creating the lock node concurrently fails
+// with a constraint violation and is reported as ErrLocked
+func (m *Migrator) lock(ctx context.Context, session *neox.Session) error {
+	locked, err := session.WriteTransactionxContext(ctx, func(tx *neox.Transaction) (interface{}, error) {
+		res, err := tx.Runx(lockStatement, neox.Args{"owner": m.Owner})
+		if err != nil {
+			return nil, err
+		}
+		found := res.Next()
+		if err := res.Err(); err != nil {
+			return nil, err
+		}
+		_, err = res.Consume()
+		return found, err
+	})
+	if errors.Is(err, neox.ErrConstraintViolation) {
+		return fmt.Errorf("%w: %v", ErrLocked, err)
+	}
+	if err != nil {
+		return err
+	}
+	if !locked.(bool) {
+		return ErrLocked
+	}
+	return nil
+}
+
+func (m *Migrator) apply(ctx context.Context, session *neox.Session, migration Migration) error {
+	m.logf("applying %s", migration)
+	if err := m.step(ctx, session, migration, migration.Up, migration.UpFunc); err != nil {
+		return err
+	}
+	return m.exec(ctx, session, recordStatement, neox.Args{
+		"version":  migration.Version,
+		"name":     migration.Name,
+		"checksum": migration.Checksum(),
+	})
+}
+
+func (m *Migrator) revert(ctx context.Context, session *neox.Session, migration Migration) error {
+	m.logf("reverting %s", migration)
+	if err := m.step(ctx, session, migration, migration.Down, migration.DownFunc); err != nil {
+		return err
+	}
+	return m.exec(ctx, session, forgetStatement, neox.Args{"version": migration.Version})
+}
+
+// step runs the statements of a migration, or its function. Statements are not
+// cancelled by the context, which is checked between them instead
+func (m *Migrator) step(ctx context.Context, session *neox.Session, migration Migration, statements []string, fn Func) error {
+	if fn != nil {
+		if err := fn(ctx, session); err != nil {
+			return fmt.Errorf("migrate: %s: %w", migration, err)
+		}
+		return nil
+	}
+	for _, stmt := range statements {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("migrate: %s: %w", migration, err)
+		}
+		if err := m.exec(ctx, session, stmt, nil); err != nil {
+			return fmt.Errorf("migrate: %s: %w", migration, err)
+		}
+	}
+	return nil
+}
+
+// exec runs a statement in a write transaction
+func (m *Migrator) exec(ctx context.Context, session *neox.Session, stmt string, args neox.Args) error {
+	m.logf("%s", stmt)
+	_, err := session.WriteTransactionxContext(ctx, func(tx *neox.Transaction) (interface{}, error) {
+		res, err := tx.Runx(stmt, args)
+		if err != nil {
+			return nil, err
+		}
+		_, err = res.Consume()
+		return nil, err
+	})
+	return err
+}
+
+func (m *Migrator) applied(ctx context.Context, session *neox.Session) ([]applied, error) {
+	records, err := session.ReadTransactionxContext(ctx, func(tx *neox.Transaction) (interface{}, error) {
+		res, err := tx.Runx(appliedStatement, nil)
+		if err != nil {
+			return nil, err
+		}
+		var records []applied
+		for res.Next() {
+			var a applied
+			if err := res.ToStruct(&a); err != nil {
+				return nil, err
+			}
+			records = append(records, a)
+		}
+		return records, res.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return records.([]applied), nil
+}
+
+// status merges the known migrations with the applied ones, ordered by version
+func (m *Migrator) status(records []applied) []Status {
+	byVersion := make(map[int64]applied, len(records))
+	for _, a := range records {
+		byVersion[a.Version] = a
+	}
+
+	status := make([]Status, 0, len(m.migrations)+len(records))
+	for _, migration := range m.migrations {
+		s := Status{Migration: migration}
+		if a, ok := byVersion[migration.Version]; ok {
+			s.Applied, s.AppliedAt, s.Checksum = true, a.AppliedAt, a.Checksum
+			delete(byVersion, migration.Version)
+		}
+		status = append(status, s)
+	}
+	for _, a := range records {
+		if _, ok := byVersion[a.Version]; ok {
+			status = append(status, Status{
+				Migration: Migration{Version: a.Version, Name: a.Name},
+				Applied:   true,
+				AppliedAt: a.AppliedAt,
+				Checksum:  a.Checksum,
+				Missing:   true,
+			})
+		}
+	}
+	sort.SliceStable(status, func(i, j int) bool {
+		return status[i].Migration.Version < status[j].Migration.Version
+	})
+	return status
+}
+
+func (m *Migrator) logf(format string, args ...interface{}) {
+	if m.Logger != nil {
+		m.Logger.Printf("migrate: "+format, args...)
+	}
+}
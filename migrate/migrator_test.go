@@ -0,0 +1,344 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/syllabix/neox"
+	"github.com/syllabix/neox/neoxtest"
+)
+
+var (
+	appliedAt = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	users = Migration{
+		Version: 1,
+		Name:    "users",
+		Up:      []string{"create index users"},
+		Down:    []string{"drop index users"},
+	}
+	backfill = Migration{
+		Version: 2,
+		Name:    "backfill",
+		UpFunc: func(ctx context.Context, session *neox.Session) error {
+			_, err := session.BatchWriteContext(ctx, "unwind $batch as row create (:User {id: row.id})", []neox.Args{{"id": 1}}, 10)
+			return err
+		},
+	}
+	emails = Migration{
+		Version: 3,
+		Name:    "emails",
+		Up:      []string{"create index emails", "match (u:User) set u.email = ''"},
+		Down:    []string{"drop index emails"},
+	}
+)
+
+var appliedKeys = []string{"version", "name", "checksum", "applied_at"}
+
+func appliedRow(m Migration, checksum string) []interface{} {
+	return []interface{}{m.Version, m.Name, checksum, appliedAt}
+}
+
+func record(m Migration) neox.Args {
+	return neox.Args{"version": m.Version, "name": m.Name, "checksum": m.Checksum()}
+}
+
+func migrator(t *testing.T, fake *neoxtest.Driver, migrations ...Migration) *Migrator {
+	m, err := New(fake.Neox(), migrations...)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	m.Owner = "test"
+	return m
+}
+
+// constrained expects the creation of the constraints preceding the lock
+func constrained(fake *neoxtest.Driver) {
+	fake.On(lockConstraintStatement)
+	fake.On(versionConstraintStatement)
+}
+
+func cyphers(fake *neoxtest.Driver) []string {
+	var list []string
+	for _, c := range fake.Calls() {
+		list = append(list, c.Cypher)
+	}
+	return list
+}
+
+func TestMigrator_Up(t *testing.T) {
+	t.Parallel()
+
+	lock := neox.Args{"owner": "test"}
+
+	t.Run("Should apply pending migrations in order", func(t *testing.T) {
+		fake := neoxtest.NewDriver()
+		constrained(fake)
+		fake.On(lockStatement).WithArgs(lock).Return([]string{"owner"}, []interface{}{"test"})
+		fake.On(appliedStatement).Return(appliedKeys, appliedRow(users, users.Checksum()))
+		fake.On("unwind $batch as row create (:User {id: row.id})")
+		fake.On("create index emails")
+		fake.On("match (u:User) set u.email = ''")
+		fake.On(recordStatement).WithArgs(record(backfill))
+		fake.On(recordStatement).WithArgs(record(emails))
+		fake.On(unlockStatement).WithArgs(lock)
+
+		got, err := migrator(t, fake, emails, users, backfill).Up(context.Background())
+		if err != nil {
+			t.Fatalf("Migrator.Up() error = %v", err)
+		}
+		if want := []Migration{backfill, emails}; len(got) != 2 || got[0].Name != want[0].Name || got[1].Name != want[1].Name {
+			t.Errorf("Migrator.Up() got = %v, want %v", got, want)
+		}
+		want := []string{
+			lockConstraintStatement, versionConstraintStatement, lockStatement, appliedStatement,
+			"unwind $batch as row create (:User {id: row.id})", recordStatement,
+			"create index emails", "match (u:User) set u.email = ''", recordStatement,
+			unlockStatement,
+		}
+		if got := cyphers(fake); !reflect.DeepEqual(got, want) {
+			t.Errorf("Calls() got = %q, want %q", got, want)
+		}
+		if unmet := fake.Unmet(); len(unmet) > 0 {
+			t.Errorf("Unmet() = %v", unmet)
+		}
+	})
+
+	t.Run("Should stop at the target version", func(t *testing.T) {
+		fake := neoxtest.NewDriver()
+		constrained(fake)
+		fake.On(lockStatement).Return([]string{"owner"}, []interface{}{"test"})
+		fake.On(appliedStatement).Return(appliedKeys)
+		fake.On("create index users")
+		fake.On(recordStatement).WithArgs(record(users))
+		fake.On(unlockStatement)
+
+		got, err := migrator(t, fake, users, emails).UpTo(context.Background(), 2)
+		if err != nil || len(got) != 1 || got[0].Version != 1 {
+			t.Errorf("Migrator.UpTo() got = %v, %v, want %v", got, err, []Migration{users})
+		}
+	})
+
+	t.Run("Should fail when the lock is held", func(t *testing.T) {
+		fake := neoxtest.NewDriver()
+		constrained(fake)
+		fake.On(lockStatement).Return([]string{"owner"})
+
+		if _, err := migrator(t, fake, users).Up(context.Background()); !errors.Is(err, ErrLocked) {
+			t.Errorf("Migrator.Up() error = %v, want %v", err, ErrLocked)
+		}
+		if got := cyphers(fake); !reflect.DeepEqual(got, []string{lockConstraintStatement, versionConstraintStatement, lockStatement}) {
+			t.Errorf("Calls() got = %q", got)
+		}
+	})
+
+	t.Run("Should fail when another runner created the lock", func(t *testing.T) {
+		fake := neoxtest.NewDriver()
+		constrained(fake)
+		fake.On(lockStatement).ReturnError(neoxtest.DatabaseError("Neo.ClientError.Schema.ConstraintValidationFailed",
+			"Node(0) already exists with label `__NeoxMigrationLock` and property `name` = 'migrate'"))
+
+		if _, err := migrator(t, fake, users).Up(context.Background()); !errors.Is(err, ErrLocked) {
+			t.Errorf("Migrator.Up() error = %v, want %v", err, ErrLocked)
+		}
+		want := []string{lockConstraintStatement, versionConstraintStatement, lockStatement}
+		if got := cyphers(fake); !reflect.DeepEqual(got, want) {
+			t.Errorf("Calls() got = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("Should tolerate existing constraints", func(t *testing.T) {
+		fake := neoxtest.NewDriver()
+		fake.On(lockConstraintStatement).ReturnError(neoxtest.DatabaseError("Neo.ClientError.Schema.EquivalentSchemaRuleAlreadyExists", "exists"))
+		fake.On(versionConstraintStatement).ReturnError(neoxtest.DatabaseError("Neo.ClientError.Schema.ConstraintAlreadyExists", "exists"))
+		fake.On(lockStatement).Return([]string{"owner"}, []interface{}{"test"})
+		fake.On(appliedStatement).Return(appliedKeys, appliedRow(users, users.Checksum()))
+		fake.On(unlockStatement)
+
+		if _, err := migrator(t, fake, users).Up(context.Background()); err != nil {
+			t.Errorf("Migrator.Up() error = %v", err)
+		}
+	})
+
+	t.Run("Should fail when an applied migration changed", func(t *testing.T) {
+		fake := neoxtest.NewDriver()
+		constrained(fake)
+		fake.On(lockStatement).Return([]string{"owner"}, []interface{}{"test"})
+		fake.On(appliedStatement).Return(appliedKeys, appliedRow(users, "changed"))
+		fake.On(unlockStatement)
+
+		if _, err := migrator(t, fake, users, emails).Up(context.Background()); !errors.Is(err, ErrChecksumMismatch) {
+			t.Errorf("Migrator.Up() error = %v, want %v", err, ErrChecksumMismatch)
+		}
+		if got := cyphers(fake); !reflect.DeepEqual(got, []string{lockConstraintStatement, versionConstraintStatement, lockStatement, appliedStatement, unlockStatement}) {
+			t.Errorf("Calls() got = %q", got)
+		}
+	})
+
+	t.Run("Should stop at the first failure", func(t *testing.T) {
+		fake := neoxtest.NewDriver()
+		constrained(fake)
+		fake.On(lockStatement).Return([]string{"owner"}, []interface{}{"test"})
+		fake.On(appliedStatement).Return(appliedKeys)
+		fake.On("create index users")
+		fake.On(recordStatement)
+		fake.On("create index emails").ReturnError(neoxtest.DatabaseError("Neo.ClientError.Statement.SyntaxError", "invalid"))
+		fake.On(unlockStatement)
+
+		got, err := migrator(t, fake, users, emails).Up(context.Background())
+		if !errors.Is(err, neox.ErrSyntax) {
+			t.Errorf("Migrator.Up() error = %v, want %v", err, neox.ErrSyntax)
+		}
+		if len(got) != 1 || got[0].Version != 1 {
+			t.Errorf("Migrator.Up() got = %v, want %v", got, []Migration{users})
+		}
+	})
+
+	t.Run("Should stop once the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		cancelling := Migration{
+			Version: 1,
+			Name:    "cancelling",
+			UpFunc: func(ctx context.Context, session *neox.Session) error {
+				cancel()
+				return nil
+			},
+		}
+
+		fake := neoxtest.NewDriver()
+		constrained(fake)
+		fake.On(lockStatement).Return([]string{"owner"}, []interface{}{"test"})
+		fake.On(appliedStatement).Return(appliedKeys)
+		fake.On(recordStatement)
+		fake.On(unlockStatement)
+
+		got, err := migrator(t, fake, cancelling, emails).Up(ctx)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Migrator.Up() error = %v, want %v", err, context.Canceled)
+		}
+		if len(got) != 1 || got[0].Version != 1 {
+			t.Errorf("Migrator.Up() got = %v, want %v", got, []Migration{cancelling})
+		}
+		want := []string{lockConstraintStatement, versionConstraintStatement, lockStatement, appliedStatement, recordStatement, unlockStatement}
+		if got := cyphers(fake); !reflect.DeepEqual(got, want) {
+			t.Errorf("Calls() got = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("Should only plan migrations on dry runs", func(t *testing.T) {
+		fake := neoxtest.NewDriver()
+		fake.On(appliedStatement).Return(appliedKeys)
+
+		m := migrator(t, fake, users, emails)
+		m.DryRun = true
+		got, err := m.Up(context.Background())
+		if err != nil || len(got) != 2 {
+			t.Errorf("Migrator.Up() got = %v, %v, want %v", got, err, []Migration{users, emails})
+		}
+		if got := cyphers(fake); !reflect.DeepEqual(got, []string{appliedStatement}) {
+			t.Errorf("Calls() got = %q", got)
+		}
+	})
+}
+
+func TestMigrator_Down(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should revert the latest migrations", func(t *testing.T) {
+		fake := neoxtest.NewDriver()
+		constrained(fake)
+		fake.On(lockStatement).Return([]string{"owner"}, []interface{}{"test"})
+		fake.On(appliedStatement).Return(appliedKeys, appliedRow(users, users.Checksum()), appliedRow(emails, emails.Checksum()))
+		fake.On("drop index emails")
+		fake.On(forgetStatement).WithArgs(neox.Args{"version": int64(3)})
+		fake.On(unlockStatement)
+
+		got, err := migrator(t, fake, users, emails).Down(context.Background(), 1)
+		if err != nil || len(got) != 1 || got[0].Version != 3 {
+			t.Errorf("Migrator.Down() got = %v, %v, want %v", got, err, []Migration{emails})
+		}
+		want := []string{lockConstraintStatement, versionConstraintStatement, lockStatement, appliedStatement, "drop index emails", forgetStatement, unlockStatement}
+		if got := cyphers(fake); !reflect.DeepEqual(got, want) {
+			t.Errorf("Calls() got = %q, want %q", got, want)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		applied [][]interface{}
+		wantErr error
+	}{
+		{
+			name:    "Should refuse to revert irreversible migrations",
+			applied: [][]interface{}{appliedRow(users, users.Checksum()), appliedRow(backfill, "")},
+			wantErr: ErrIrreversible,
+		},
+		{
+			name:    "Should refuse to revert unknown migrations",
+			applied: [][]interface{}{appliedRow(users, users.Checksum()), {int64(9), "gone", "", appliedAt}},
+			wantErr: ErrUnknownMigration,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := neoxtest.NewDriver()
+			constrained(fake)
+			fake.On(lockStatement).Return([]string{"owner"}, []interface{}{"test"})
+			fake.On(appliedStatement).Return(appliedKeys, tt.applied...)
+			fake.On(unlockStatement)
+
+			if _, err := migrator(t, fake, users, backfill).DownTo(context.Background(), 0); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Migrator.DownTo() error = %v, want %v", err, tt.wantErr)
+			}
+			if got := cyphers(fake); !reflect.DeepEqual(got, []string{lockConstraintStatement, versionConstraintStatement, lockStatement, appliedStatement, unlockStatement}) {
+				t.Errorf("Calls() got = %q", got)
+			}
+		})
+	}
+}
+
+func TestMigrator_Status(t *testing.T) {
+	t.Parallel()
+
+	fake := neoxtest.NewDriver()
+	fake.On(appliedStatement).Return(appliedKeys,
+		appliedRow(users, users.Checksum()),
+		[]interface{}{int64(2), "gone", "", appliedAt},
+	)
+
+	got, err := migrator(t, fake, users, emails).Status(context.Background())
+	if err != nil {
+		t.Fatalf("Migrator.Status() error = %v", err)
+	}
+	want := []Status{
+		{Migration: users, Applied: true, AppliedAt: appliedAt, Checksum: users.Checksum()},
+		{Migration: Migration{Version: 2, Name: "gone"}, Applied: true, AppliedAt: appliedAt, Missing: true},
+		{Migration: emails},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Migrator.Status() got = %+v, want %+v", got, want)
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		migrations []Migration
+	}{
+		{name: "Should reject versions used twice", migrations: []Migration{users, {Version: 1, Name: "again", Up: []string{"return 1"}}}},
+		{name: "Should reject migrations without up", migrations: []Migration{{Version: 4, Name: "empty"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(neoxtest.NewDriver().Neox(), tt.migrations...); err == nil {
+				t.Errorf("New() error = %v, want an error", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package neoxtest
+
+import (
+	"fmt"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+	"github.com/syllabix/neox"
+)
+
+// NewRecord returns a neo4j.Record holding a value per key
+func NewRecord(keys []string, values []interface{}) neo4j.Record {
+	return &record{keys: keys, values: values}
+}
+
+// NewResult returns a neo4j.Result streaming the provided records, its keys are
+// the keys of the first record. The summary of the result is empty
+func NewResult(records ...neo4j.Record) neo4j.Result {
+	return NewResultWithSummary(Summary{}, records...)
+}
+
+// NewResultWithSummary returns a neo4j.Result streaming the provided records
+// and reporting the provided summary
+func NewResultWithSummary(s Summary, records ...neo4j.Record) neo4j.Result {
+	var keys []string
+	if len(records) > 0 {
+		keys = records[0].Keys()
+	}
+	return &result{
+		keys:    keys,
+		records: records,
+		summary: &summary{s: s, statement: &statement{}},
+	}
+}
+
+// NewResultx returns a neox.Result streaming the provided records
+func NewResultx(records ...neo4j.Record) *neox.Result {
+	return &neox.Result{Result: NewResult(records...)}
+}
+
+// NewNode returns a neo4j.Node
+func NewNode(id int64, labels []string, props map[string]interface{}) neo4j.Node {
+	return &node{id: id, labels: labels, props: props}
+}
+
+// NewRelationship returns a neo4j.Relationship of the provided type from the start node to the end node
+func NewRelationship(id, startID, endID int64, relType string, props map[string]interface{}) neo4j.Relationship {
+	return &relationship{id: id, startID: startID, endID: endID, relType: relType, props: props}
+}
+
+// NewPath returns a neo4j.Path traversing the provided nodes in order through the provided
+// relationships, the relationship at index i connecting the nodes at i and i+1 in either direction.
+// It panics if the relationships do not connect the nodes
+func NewPath(nodes []neo4j.Node, rels []neo4j.Relationship) neo4j.Path {
+	if len(nodes) == 0 || len(rels) != len(nodes)-1 {
+		panic(fmt.Sprintf("neoxtest: a path of %d nodes can not have %d relationships", len(nodes), len(rels)))
+	}
+	for i, rel := range rels {
+		a, b := nodes[i].Id(), nodes[i+1].Id()
+		if !(rel.StartId() == a && rel.EndId() == b) && !(rel.StartId() == b && rel.EndId() == a) {
+			panic(fmt.Sprintf("neoxtest: relationship %d does not connect nodes %d and %d", rel.Id(), a, b))
+		}
+	}
+	return &path{nodes: nodes, rels: rels}
+}
+
+// node is an in-memory neo4j.Node
+type node struct {
+	id     int64
+	labels []string
+	props  map[string]interface{}
+}
+
+func (n *node) Id() int64                     { return n.id }
+func (n *node) Labels() []string              { return n.labels }
+func (n *node) Props() map[string]interface{} { return n.props }
+
+// relationship is an in-memory neo4j.Relationship
+type relationship struct {
+	id      int64
+	startID int64
+	endID   int64
+	relType string
+	props   map[string]interface{}
+}
+
+func (r *relationship) Id() int64                     { return r.id }
+func (r *relationship) StartId() int64                { return r.startID }
+func (r *relationship) EndId() int64                  { return r.endID }
+func (r *relationship) Type() string                  { return r.relType }
+func (r *relationship) Props() map[string]interface{} { return r.props }
+
+// path is an in-memory neo4j.Path
+type path struct {
+	nodes []neo4j.Node
+	rels  []neo4j.Relationship
+}
+
+func (p *path) Nodes() []neo4j.Node                 { return p.nodes }
+func (p *path) Relationships() []neo4j.Relationship { return p.rels }
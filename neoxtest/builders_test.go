@@ -0,0 +1,95 @@
+package neoxtest
+
+import (
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+func TestNewResultx_ToStruct(t *testing.T) {
+	t.Parallel()
+
+	keys := []string{"user_name", "user_age"}
+	tests := []struct {
+		name   string
+		record neo4j.Record
+		want   user
+	}{
+		{
+			name:   "Should map all values",
+			record: NewRecord(keys, []interface{}{"Yolanda Erasmus", int64(17)}),
+			want:   user{"Yolanda Erasmus", 17},
+		},
+		{
+			name:   "Should skip values of another type",
+			record: NewRecord(keys, []interface{}{"Jordan Ames", 34.5}),
+			want:   user{Name: "Jordan Ames"},
+		},
+		{
+			name:   "Should skip missing values",
+			record: NewRecord([]string{"user_age"}, []interface{}{int64(40)}),
+			want:   user{Age: 40},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := NewResultx(tt.record)
+			if !res.Next() {
+				t.Fatalf("Result.Next() = false, want a record")
+			}
+			var got user
+			if err := res.ToStruct(&got); err != nil {
+				t.Fatalf("Result.ToStruct() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Result.ToStruct() = %+v, want %+v", got, tt.want)
+			}
+			if res.Next() {
+				t.Errorf("Result.Next() = true, want the result to be exhausted")
+			}
+		})
+	}
+}
+
+func TestNewRecord(t *testing.T) {
+	t.Parallel()
+
+	yolanda := NewNode(1, []string{"User"}, map[string]interface{}{"name": "Yolanda"})
+	jordan := NewNode(2, []string{"User"}, map[string]interface{}{"name": "Jordan"})
+	friends := NewRelationship(10, 2, 1, "FRIENDS_WITH", nil)
+	path := NewPath([]neo4j.Node{yolanda, jordan}, []neo4j.Relationship{friends})
+
+	record := NewRecord([]string{"u", "p"}, []interface{}{yolanda, path})
+	r := NewResultx(record)
+	r.Next()
+	rec := r.Recordx()
+
+	u, ok := rec.Get("u")
+	if !ok || u.(neo4j.Node).Props()["name"] != "Yolanda" {
+		t.Errorf("Record.Get(u) = %v, %v", u, ok)
+	}
+	p, _ := rec.GetByIndex(1).(neo4j.Path)
+	if len(p.Nodes()) != 2 || p.Relationships()[0].Type() != "FRIENDS_WITH" {
+		t.Errorf("Record.GetByIndex(1) = %v", p)
+	}
+	if _, ok := rec.Get("missing"); ok {
+		t.Errorf("Record.Get(missing) = true, want false")
+	}
+	if rec.GetByIndex(5) != nil {
+		t.Errorf("Record.GetByIndex(5) = %v, want nil", rec.GetByIndex(5))
+	}
+}
+
+func TestNewPath(t *testing.T) {
+	t.Parallel()
+
+	a := NewNode(1, nil, nil)
+	b := NewNode(2, nil, nil)
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("NewPath() did not panic for disconnected nodes")
+		}
+	}()
+	NewPath([]neo4j.Node{a, b}, []neo4j.Relationship{NewRelationship(1, 3, 4, "KNOWS", nil)})
+}
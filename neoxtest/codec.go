@@ -0,0 +1,404 @@
+package neoxtest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+// Tags of the values encoded in fixtures that have no native JSON representation.
+// Tagged values are encoded as a JSON object with a single tag key
+const (
+	tagInt           = "$int"
+	tagFloat         = "$float"
+	tagBytes         = "$bytes"
+	tagMap           = "$map"
+	tagNode          = "$node"
+	tagRelationship  = "$relationship"
+	tagPath          = "$path"
+	tagDate          = "$date"
+	tagLocalTime     = "$localTime"
+	tagOffsetTime    = "$offsetTime"
+	tagLocalDateTime = "$localDateTime"
+	tagDateTime      = "$dateTime"
+	tagDuration      = "$duration"
+	tagPoint         = "$point"
+)
+
+const (
+	layoutDate          = "2006-01-02"
+	layoutLocalTime     = "15:04:05.999999999"
+	layoutOffsetTime    = "15:04:05.999999999Z07:00"
+	layoutLocalDateTime = "2006-01-02T15:04:05.999999999"
+)
+
+type jsonNode struct {
+	ID     int64                  `json:"id"`
+	Labels []string               `json:"labels"`
+	Props  map[string]interface{} `json:"props"`
+}
+
+type jsonRelationship struct {
+	ID    int64                  `json:"id"`
+	Start int64                  `json:"start"`
+	End   int64                  `json:"end"`
+	Type  string                 `json:"type"`
+	Props map[string]interface{} `json:"props"`
+}
+
+type jsonPath struct {
+	Nodes         []jsonNode         `json:"nodes"`
+	Relationships []jsonRelationship `json:"relationships"`
+}
+
+type jsonDateTime struct {
+	Time string `json:"time"`
+	Zone string `json:"zone,omitempty"`
+}
+
+type jsonDuration struct {
+	Months  int64 `json:"months"`
+	Days    int64 `json:"days"`
+	Seconds int64 `json:"seconds"`
+	Nanos   int   `json:"nanos"`
+}
+
+type jsonPoint struct {
+	SrID int      `json:"srid"`
+	X    float64  `json:"x"`
+	Y    float64  `json:"y"`
+	Z    *float64 `json:"z,omitempty"`
+}
+
+// encodeValue converts a value returned by, or passed to, the driver
+// into a JSON compatible value that can be decoded without loss
+func encodeValue(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case nil, bool, string:
+		return v, nil
+	case float64:
+		return encodeFloat(v), nil
+	case float32:
+		return encodeFloat(float64(v)), nil
+	case []byte:
+		return tagged(tagBytes, base64.StdEncoding.EncodeToString(v)), nil
+	case neo4j.Node:
+		n, err := encodeNode(v)
+		return tagged(tagNode, n), err
+	case neo4j.Relationship:
+		r, err := encodeRelationship(v)
+		return tagged(tagRelationship, r), err
+	case neo4j.Path:
+		var p jsonPath
+		for _, n := range v.Nodes() {
+			jn, err := encodeNode(n)
+			if err != nil {
+				return nil, err
+			}
+			p.Nodes = append(p.Nodes, jn)
+		}
+		for _, r := range v.Relationships() {
+			jr, err := encodeRelationship(r)
+			if err != nil {
+				return nil, err
+			}
+			p.Relationships = append(p.Relationships, jr)
+		}
+		return tagged(tagPath, p), nil
+	case neo4j.Date:
+		return tagged(tagDate, v.Time().Format(layoutDate)), nil
+	case neo4j.LocalTime:
+		return tagged(tagLocalTime, v.String()), nil
+	case neo4j.OffsetTime:
+		return tagged(tagOffsetTime, v.String()), nil
+	case neo4j.LocalDateTime:
+		return tagged(tagLocalDateTime, v.Time().Format(layoutLocalDateTime)), nil
+	case time.Time:
+		return tagged(tagDateTime, jsonDateTime{
+			Time: v.Format(time.RFC3339Nano),
+			Zone: zoneName(v.Location()),
+		}), nil
+	case neo4j.Duration:
+		return tagged(tagDuration, jsonDuration{v.Months(), v.Days(), v.Seconds(), v.Nanos()}), nil
+	case *neo4j.Point:
+		p := jsonPoint{SrID: v.SrId(), X: v.X(), Y: v.Y()}
+		if z := v.Z(); !math.IsNaN(z) {
+			p.Z = &z
+		}
+		return tagged(tagPoint, p), nil
+	case map[string]interface{}:
+		return encodeMap(v)
+	case []interface{}:
+		return encodeList(v)
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return tagged(tagInt, strconv.FormatInt(rv.Int(), 10)), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return tagged(tagInt, strconv.FormatUint(rv.Uint(), 10)), nil
+	case reflect.Slice, reflect.Array:
+		list := make([]interface{}, rv.Len())
+		for i := range list {
+			list[i] = rv.Index(i).Interface()
+		}
+		return encodeList(list)
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			break
+		}
+		m := make(map[string]interface{}, rv.Len())
+		for _, k := range rv.MapKeys() {
+			m[k.String()] = rv.MapIndex(k).Interface()
+		}
+		return encodeMap(m)
+	}
+
+	return nil, fmt.Errorf("neoxtest: unable to encode value of type %T", v)
+}
+
+func encodeFloat(f float64) interface{} {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return tagged(tagFloat, strconv.FormatFloat(f, 'g', -1, 64))
+	}
+	return f
+}
+
+func encodeList(list []interface{}) (interface{}, error) {
+	encoded := make([]interface{}, len(list))
+	for i, v := range list {
+		ev, err := encodeValue(v)
+		if err != nil {
+			return nil, err
+		}
+		encoded[i] = ev
+	}
+	return encoded, nil
+}
+
+func encodeMap(m map[string]interface{}) (interface{}, error) {
+	encoded, err := encodeProps(m)
+	if err != nil {
+		return nil, err
+	}
+	if len(encoded) == 1 {
+		for k := range encoded {
+			if isTag(k) {
+				return tagged(tagMap, encoded), nil
+			}
+		}
+	}
+	return encoded, nil
+}
+
+func encodeProps(m map[string]interface{}) (map[string]interface{}, error) {
+	if m == nil {
+		return nil, nil
+	}
+	encoded := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		ev, err := encodeValue(v)
+		if err != nil {
+			return nil, err
+		}
+		encoded[k] = ev
+	}
+	return encoded, nil
+}
+
+func encodeNode(n neo4j.Node) (jsonNode, error) {
+	props, err := encodeProps(n.Props())
+	return jsonNode{ID: n.Id(), Labels: n.Labels(), Props: props}, err
+}
+
+func encodeRelationship(r neo4j.Relationship) (jsonRelationship, error) {
+	props, err := encodeProps(r.Props())
+	return jsonRelationship{ID: r.Id(), Start: r.StartId(), End: r.EndId(), Type: r.Type(), Props: props}, err
+}
+
+func tagged(tag string, v interface{}) map[string]interface{} {
+	return map[string]interface{}{tag: v}
+}
+
+func zoneName(loc *time.Location) string {
+	if loc == time.UTC || loc == time.Local {
+		return ""
+	}
+	if _, err := time.LoadLocation(loc.String()); err != nil {
+		return ""
+	}
+	return loc.String()
+}
+
+func isTag(key string) bool {
+	switch key {
+	case tagInt, tagFloat, tagBytes, tagMap, tagNode, tagRelationship, tagPath, tagDate,
+		tagLocalTime, tagOffsetTime, tagLocalDateTime, tagDateTime, tagDuration, tagPoint:
+		return true
+	}
+	return false
+}
+
+// decodeValue converts a value decoded from JSON with encoding/json
+// back into the value it was encoded from
+func decodeValue(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			dv, err := decodeValue(item)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = dv
+		}
+		return list, nil
+	case map[string]interface{}:
+		if len(v) == 1 {
+			for k, raw := range v {
+				if isTag(k) {
+					return decodeTagged(k, raw)
+				}
+			}
+		}
+		return decodeProps(v)
+	}
+	return v, nil
+}
+
+func decodeProps(m map[string]interface{}) (map[string]interface{}, error) {
+	if m == nil {
+		return nil, nil
+	}
+	decoded := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		dv, err := decodeValue(v)
+		if err != nil {
+			return nil, err
+		}
+		decoded[k] = dv
+	}
+	return decoded, nil
+}
+
+func decodeTagged(tag string, raw interface{}) (interface{}, error) {
+	switch tag {
+	case tagInt:
+		s, _ := raw.(string)
+		return strconv.ParseInt(s, 10, 64)
+	case tagFloat:
+		s, _ := raw.(string)
+		return strconv.ParseFloat(s, 64)
+	case tagBytes:
+		s, _ := raw.(string)
+		return base64.StdEncoding.DecodeString(s)
+	case tagMap:
+		m, _ := raw.(map[string]interface{})
+		return decodeProps(m)
+	case tagNode:
+		var n jsonNode
+		if err := remarshal(raw, &n); err != nil {
+			return nil, err
+		}
+		return decodeNode(n)
+	case tagRelationship:
+		var r jsonRelationship
+		if err := remarshal(raw, &r); err != nil {
+			return nil, err
+		}
+		return decodeRelationship(r)
+	case tagPath:
+		var p jsonPath
+		if err := remarshal(raw, &p); err != nil {
+			return nil, err
+		}
+		nodes := make([]neo4j.Node, len(p.Nodes))
+		for i, jn := range p.Nodes {
+			n, err := decodeNode(jn)
+			if err != nil {
+				return nil, err
+			}
+			nodes[i] = n
+		}
+		rels := make([]neo4j.Relationship, len(p.Relationships))
+		for i, jr := range p.Relationships {
+			r, err := decodeRelationship(jr)
+			if err != nil {
+				return nil, err
+			}
+			rels[i] = r
+		}
+		return &path{nodes: nodes, rels: rels}, nil
+	case tagDate:
+		t, err := parseTagged(raw, layoutDate)
+		return neo4j.DateOf(t), err
+	case tagLocalTime:
+		t, err := parseTagged(raw, layoutLocalTime)
+		return neo4j.LocalTimeOf(t), err
+	case tagOffsetTime:
+		t, err := parseTagged(raw, layoutOffsetTime)
+		return neo4j.OffsetTimeOf(t), err
+	case tagLocalDateTime:
+		t, err := parseTagged(raw, layoutLocalDateTime)
+		return neo4j.LocalDateTimeOf(t), err
+	case tagDateTime:
+		var dt jsonDateTime
+		if err := remarshal(raw, &dt); err != nil {
+			return nil, err
+		}
+		t, err := time.Parse(time.RFC3339Nano, dt.Time)
+		if err != nil || dt.Zone == "" {
+			return t, err
+		}
+		loc, err := time.LoadLocation(dt.Zone)
+		if err != nil {
+			return nil, err
+		}
+		return t.In(loc), nil
+	case tagDuration:
+		var d jsonDuration
+		err := remarshal(raw, &d)
+		return neo4j.DurationOf(d.Months, d.Days, d.Seconds, d.Nanos), err
+	case tagPoint:
+		var p jsonPoint
+		if err := remarshal(raw, &p); err != nil {
+			return nil, err
+		}
+		if p.Z != nil {
+			return neo4j.NewPoint3D(p.SrID, p.X, p.Y, *p.Z), nil
+		}
+		return neo4j.NewPoint2D(p.SrID, p.X, p.Y), nil
+	}
+	return nil, fmt.Errorf("neoxtest: unknown value tag %s", tag)
+}
+
+func decodeNode(n jsonNode) (*node, error) {
+	props, err := decodeProps(n.Props)
+	return &node{id: n.ID, labels: n.Labels, props: props}, err
+}
+
+func decodeRelationship(r jsonRelationship) (*relationship, error) {
+	props, err := decodeProps(r.Props)
+	return &relationship{id: r.ID, startID: r.Start, endID: r.End, relType: r.Type, props: props}, err
+}
+
+func parseTagged(raw interface{}, layout string) (time.Time, error) {
+	s, _ := raw.(string)
+	return time.Parse(layout, s)
+}
+
+// remarshal decodes a value decoded from JSON into the provided destination
+func remarshal(raw interface{}, dest interface{}) error {
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, dest)
+}
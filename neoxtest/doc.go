@@ -0,0 +1,37 @@
+// Package neoxtest provides an in-memory, scriptable neo4j driver for testing code
+// built on neox without a running Neo4j server.
+//
+// Statements are matched against expectations, either exactly or with a regular expression,
+// and answered with canned keys, records and summaries, or with an injected error. Every
+// statement run through the fake is recorded along with its args
+//
+//	fake := neoxtest.NewDriver()
+//	fake.On("match (u:User {id: $id}) return u.name as user_name").
+//		Return([]string{"user_name"}, []interface{}{"Yolanda Erasmus"})
+//	fake.OnRegexp(`^create \(u:User`).
+//		ReturnError(neoxtest.DatabaseError("Neo.ClientError.Schema.ConstraintValidationFailed",
+//			"Node(0) already exists with label `User` and property `email` = 'ya@cool.com'"))
+//
+//	repo := NewUserRepository(fake.Neox())
+//	...
+//	calls := fake.Calls()
+//
+// Records, results and graph values can also be built directly, to exercise
+// the extensions of a neox.Result in table tests
+//
+//	user := neoxtest.NewNode(1, []string{"User"}, map[string]interface{}{"name": "Yolanda"})
+//	result := neoxtest.NewResultx(
+//		neoxtest.NewRecord([]string{"u", "user_name"}, []interface{}{user, "Yolanda"}),
+//	)
+//
+// Traffic against a running server can be recorded with a Recorder, saved as a JSON fixture
+// and replayed by the fake driver returned by Load. Each recorded statement answers a single
+// statement with the same cypher and args, in the order they were recorded
+//
+//	recorder := neoxtest.NewRecorder(driver)
+//	repo := NewUserRepository(recorder.Neox())
+//	...
+//	err := recorder.Save("testdata/users.json")
+//
+//	fake, err := neoxtest.Load("testdata/users.json")
+package neoxtest
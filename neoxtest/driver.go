@@ -0,0 +1,333 @@
+package neoxtest
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sync"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+	"github.com/syllabix/neox"
+)
+
+// DefaultMaxAttempts is the number of times the transaction functions of a fake session
+// run a unit of work failing with a transient error, emulating the retries of the driver
+const DefaultMaxAttempts = 3
+
+var (
+	// ErrClosed is returned when using a closed session or transaction
+	ErrClosed = errors.New("neoxtest: already closed")
+
+	// ErrUnexpected is wrapped by the errors returned for statements that do not
+	// match any expectation
+	ErrUnexpected = errors.New("neoxtest: unexpected statement")
+)
+
+// Call is a statement run through the fake driver
+type Call struct {
+	Cypher      string
+	Args        neox.Args
+	AccessMode  neo4j.AccessMode
+	Transaction bool
+}
+
+// Driver is an in-memory, scriptable neo4j.Driver. It is safe for concurrent use
+// once its expectations are configured
+type Driver struct {
+	// SessionError, when set, is returned when opening sessions
+	SessionError error
+
+	// MaxAttempts is the maximum number of times the transaction functions run a unit
+	// of work that fails with a transient error. Zero means DefaultMaxAttempts
+	MaxAttempts int
+
+	mu           sync.Mutex
+	expectations []*Expectation
+	calls        []Call
+	sessions     int
+	closed       bool
+}
+
+// NewDriver returns a fake driver without expectations
+func NewDriver() *Driver {
+	return new(Driver)
+}
+
+// Neox returns a neox.Driver backed by the fake driver
+func (d *Driver) Neox() *neox.Driver {
+	return &neox.Driver{Driver: d}
+}
+
+// On adds an expectation for statements equal to the provided cypher,
+// ignoring differences in whitespace
+func (d *Driver) On(cypher string) *Expectation {
+	return d.expect(cypher, exact(cypher))
+}
+
+// OnRegexp adds an expectation for statements matching the provided regular expression.
+// It panics if the expression cannot be compiled
+func (d *Driver) OnRegexp(expr string) *Expectation {
+	return d.expect(expr, pattern(regexp.MustCompile(expr)))
+}
+
+func (d *Driver) expect(desc string, match func(string) bool) *Expectation {
+	e := &Expectation{desc: desc, match: match}
+	d.mu.Lock()
+	d.expectations = append(d.expectations, e)
+	d.mu.Unlock()
+	return e
+}
+
+// Calls returns the statements run through the fake driver, in order
+func (d *Driver) Calls() []Call {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]Call(nil), d.calls...)
+}
+
+// Unmet returns the descriptions of the expectations limited with Times
+// that answered fewer statements than expected
+func (d *Driver) Unmet() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var unmet []string
+	for _, e := range d.expectations {
+		if e.times > 0 && e.used < e.times {
+			unmet = append(unmet, e.desc)
+		}
+	}
+	return unmet
+}
+
+// OpenSessions returns the number of sessions that were opened and not closed
+func (d *Driver) OpenSessions() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.sessions
+}
+
+// Reset discards the expectations and recorded calls of the driver
+func (d *Driver) Reset() {
+	d.mu.Lock()
+	d.expectations = nil
+	d.calls = nil
+	d.mu.Unlock()
+}
+
+// Target returns the url of the fake driver
+func (d *Driver) Target() url.URL {
+	return url.URL{Scheme: "bolt", Host: "neoxtest"}
+}
+
+// Session returns a fake session with the provided access mode
+func (d *Driver) Session(accessMode neo4j.AccessMode, bookmarks ...string) (neo4j.Session, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return nil, ErrClosed
+	}
+	if d.SessionError != nil {
+		return nil, d.SessionError
+	}
+	d.sessions++
+	return &Session{driver: d, mode: accessMode}, nil
+}
+
+// Close closes the fake driver, further sessions can not be opened
+func (d *Driver) Close() error {
+	d.mu.Lock()
+	d.closed = true
+	d.mu.Unlock()
+	return nil
+}
+
+// run records the statement and answers it with the first matching expectation
+func (d *Driver) run(mode neo4j.AccessMode, tx bool, cypher string, params map[string]interface{}) (neo4j.Result, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	args := make(neox.Args, len(params))
+	for k, v := range params {
+		args[k] = v
+	}
+	d.calls = append(d.calls, Call{
+		Cypher:      cypher,
+		Args:        args,
+		AccessMode:  mode,
+		Transaction: tx,
+	})
+
+	for _, e := range d.expectations {
+		if !e.matches(cypher, params) {
+			continue
+		}
+		e.used++
+		if e.err != nil {
+			return nil, e.err
+		}
+
+		records := make([]neo4j.Record, len(e.rows))
+		for i, row := range e.rows {
+			records[i] = NewRecord(e.keys, row)
+		}
+		return &result{
+			keys:    e.keys,
+			records: records,
+			summary: &summary{
+				s:         e.summary,
+				statement: &statement{cypher: cypher, params: params},
+			},
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrUnexpected, cypher)
+}
+
+func (d *Driver) closeSession() {
+	d.mu.Lock()
+	d.sessions--
+	d.mu.Unlock()
+}
+
+// Session is a fake neo4j.Session returned by a fake Driver
+type Session struct {
+	driver    *Driver
+	mode      neo4j.AccessMode
+	tx        *Transaction
+	bookmarks int
+	closed    bool
+}
+
+// LastBookmark returns a bookmark identifying the last committed transaction
+func (s *Session) LastBookmark() string {
+	if s.bookmarks == 0 {
+		return ""
+	}
+	return fmt.Sprintf("neoxtest:bookmark:%d", s.bookmarks)
+}
+
+// BeginTransaction starts an explicit transaction
+func (s *Session) BeginTransaction(configurers ...func(*neo4j.TransactionConfig)) (neo4j.Transaction, error) {
+	return s.begin(s.mode)
+}
+
+func (s *Session) begin(mode neo4j.AccessMode) (*Transaction, error) {
+	if s.closed {
+		return nil, ErrClosed
+	}
+	if s.tx != nil {
+		return nil, errors.New("neoxtest: there's already an open transaction on this session")
+	}
+	s.tx = &Transaction{session: s, mode: mode}
+	return s.tx, nil
+}
+
+// ReadTransaction runs the unit of work in a read transaction
+func (s *Session) ReadTransaction(work neo4j.TransactionWork, configurers ...func(*neo4j.TransactionConfig)) (interface{}, error) {
+	return s.transaction(neo4j.AccessModeRead, work)
+}
+
+// WriteTransaction runs the unit of work in a write transaction
+func (s *Session) WriteTransaction(work neo4j.TransactionWork, configurers ...func(*neo4j.TransactionConfig)) (interface{}, error) {
+	return s.transaction(neo4j.AccessModeWrite, work)
+}
+
+// transaction runs the unit of work, retrying it while it fails with a transient error
+func (s *Session) transaction(mode neo4j.AccessMode, work neo4j.TransactionWork) (interface{}, error) {
+	attempts := s.driver.MaxAttempts
+	if attempts <= 0 {
+		attempts = DefaultMaxAttempts
+	}
+
+	var (
+		res interface{}
+		err error
+	)
+	for i := 0; i < attempts; i++ {
+		var tx *Transaction
+		tx, err = s.begin(mode)
+		if err != nil {
+			return nil, err
+		}
+
+		res, err = work(tx)
+		if err == nil {
+			err = tx.Commit()
+		}
+		tx.Close()
+
+		if err == nil {
+			return res, nil
+		}
+		if !neo4j.IsTransientError(err) {
+			break
+		}
+	}
+	return nil, err
+}
+
+// Run runs an auto-commit statement
+func (s *Session) Run(cypher string, params map[string]interface{}, configurers ...func(*neo4j.TransactionConfig)) (neo4j.Result, error) {
+	if s.closed {
+		return nil, ErrClosed
+	}
+	return s.driver.run(s.mode, false, cypher, params)
+}
+
+// Close closes the session
+func (s *Session) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	s.driver.closeSession()
+	return nil
+}
+
+// Transaction is a fake neo4j.Transaction
+type Transaction struct {
+	session    *Session
+	mode       neo4j.AccessMode
+	done       bool
+	Committed  bool
+	RolledBack bool
+}
+
+// Run runs a statement within the transaction
+func (t *Transaction) Run(cypher string, params map[string]interface{}) (neo4j.Result, error) {
+	if t.done {
+		return nil, ErrClosed
+	}
+	return t.session.driver.run(t.mode, true, cypher, params)
+}
+
+// Commit commits the transaction
+func (t *Transaction) Commit() error {
+	if t.done {
+		return ErrClosed
+	}
+	t.done = true
+	t.Committed = true
+	t.session.bookmarks++
+	return nil
+}
+
+// Rollback rolls the transaction back
+func (t *Transaction) Rollback() error {
+	if t.done {
+		return ErrClosed
+	}
+	t.done = true
+	t.RolledBack = true
+	return nil
+}
+
+// Close rolls the transaction back if it was not committed or rolled back
+func (t *Transaction) Close() error {
+	if !t.done {
+		t.Rollback()
+	}
+	t.session.tx = nil
+	return nil
+}
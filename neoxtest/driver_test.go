@@ -0,0 +1,202 @@
+package neoxtest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+	"github.com/syllabix/neox"
+)
+
+type user struct {
+	Name string `db:"user_name"`
+	Age  int64  `db:"user_age"`
+}
+
+func TestDriver_Runx(t *testing.T) {
+	t.Parallel()
+
+	fake := NewDriver()
+	fake.On("match (u:User) return u.name as user_name, u.age as user_age").
+		Return([]string{"user_name", "user_age"},
+			[]interface{}{"Yolanda Erasmus", int64(17)},
+			[]interface{}{"Jordan Ames", int64(34)},
+		).
+		WithSummary(Summary{Address: "localhost:7687"})
+
+	session, err := fake.Neox().Sessionx(neo4j.AccessModeRead)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	result, err := session.Runx(`
+		match (u:User)
+		return u.name as user_name, u.age as user_age`, nil)
+	if err != nil {
+		t.Fatalf("Session.Runx() error = %v", err)
+	}
+
+	var users []user
+	for result.Next() {
+		var u user
+		if err := result.ToStruct(&u); err != nil {
+			t.Fatalf("Result.ToStruct() error = %v", err)
+		}
+		users = append(users, u)
+	}
+
+	want := []user{{"Yolanda Erasmus", 17}, {"Jordan Ames", 34}}
+	if len(users) != len(want) || users[0] != want[0] || users[1] != want[1] {
+		t.Errorf("users = %+v, want %+v", users, want)
+	}
+
+	summary, err := result.Consume()
+	if err != nil || summary.Server().Address() != "localhost:7687" {
+		t.Errorf("Result.Consume() = %v, %v", summary, err)
+	}
+}
+
+func TestDriver_Expectations(t *testing.T) {
+	t.Parallel()
+
+	violation := DatabaseError("Neo.ClientError.Schema.ConstraintValidationFailed",
+		"Node(0) already exists with label `User` and property `email` = 'ya@cool.com'")
+
+	fake := NewDriver()
+	fake.OnRegexp(`^create \(u:User`).WithArgs(neox.Args{"email": "ya@cool.com"}).ReturnError(violation)
+	fake.OnRegexp(`^create \(u:User`).WithSummary(Summary{Counters: Counters{NodesCreated: 1}})
+
+	session, _ := fake.Neox().Sessionx(neo4j.AccessModeWrite)
+
+	tests := []struct {
+		name    string
+		args    neox.Args
+		wantErr error
+	}{
+		{
+			name:    "Should inject errors for matching args",
+			args:    neox.Args{"email": "ya@cool.com"},
+			wantErr: neox.ErrConstraintViolation,
+		},
+		{
+			name: "Should fall through to the next expectation",
+			args: neox.Args{"email": "ja@cool.com"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := session.Runx("create (u:User {email: $email})", tt.args)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Session.Runx() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			summary, _ := res.Consume()
+			if summary.Counters().NodesCreated() != 1 {
+				t.Errorf("NodesCreated = %d, want 1", summary.Counters().NodesCreated())
+			}
+		})
+	}
+
+	t.Run("Should fail unexpected statements", func(t *testing.T) {
+		_, err := session.Runx("match (n) detach delete n", nil)
+		if !errors.Is(err, ErrUnexpected) {
+			t.Errorf("Session.Runx() error = %v, want %v", err, ErrUnexpected)
+		}
+	})
+
+	calls := fake.Calls()
+	if len(calls) != 3 {
+		t.Fatalf("Calls() = %d calls, want 3", len(calls))
+	}
+	if calls[0].Args["email"] != "ya@cool.com" || calls[0].AccessMode != neo4j.AccessModeWrite || calls[0].Transaction {
+		t.Errorf("Calls()[0] = %+v", calls[0])
+	}
+}
+
+func TestExpectation_WithArgs(t *testing.T) {
+	t.Parallel()
+
+	born := neo4j.DateOf(time.Date(1982, 3, 14, 0, 0, 0, 0, time.UTC))
+	tests := []struct {
+		name string
+		want neox.Args
+		args map[string]interface{}
+		ok   bool
+	}{
+		{name: "Should match any args without expected args", want: nil, args: map[string]interface{}{"id": 1}, ok: true},
+		{name: "Should match equal args", want: neox.Args{"name": "Alice", "born": born}, args: map[string]interface{}{"name": "Alice", "born": born}, ok: true},
+		{name: "Should match integers of any size", want: neox.Args{"ids": []int{1, 2}}, args: map[string]interface{}{"ids": []interface{}{int64(1), int64(2)}}, ok: true},
+		{name: "Should match empty args", want: neox.Args{}, args: nil, ok: true},
+		{name: "Should not match other values", want: neox.Args{"id": 1}, args: map[string]interface{}{"id": 2}},
+		{name: "Should not match floats to integers", want: neox.Args{"id": 1}, args: map[string]interface{}{"id": 1.0}},
+		{name: "Should not match extra args", want: neox.Args{}, args: map[string]interface{}{"id": 1}},
+		{name: "Should not match args that can not be encoded", want: neox.Args{"id": user{}}, args: map[string]interface{}{"id": user{}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewDriver().On("match (u) return u")
+			if tt.want != nil {
+				e.WithArgs(tt.want)
+			}
+			if got := e.matches("match (u) return u", tt.args); got != tt.ok {
+				t.Errorf("Expectation.matches() got = %v, want %v", got, tt.ok)
+			}
+		})
+	}
+}
+
+func TestSession_WriteTransaction(t *testing.T) {
+	t.Parallel()
+
+	fake := NewDriver()
+	fake.On("merge (u:User {id: $id})").
+		ReturnError(DatabaseError("Neo.TransientError.Transaction.DeadlockDetected", "deadlock")).
+		Once()
+	fake.On("merge (u:User {id: $id})")
+	fake.On("match (u:User) return count(u)").Times(2)
+
+	driver := fake.Neox()
+	session, _ := driver.Sessionx(neo4j.AccessModeWrite)
+
+	_, err := session.WriteTransactionx(func(tx *neox.Transaction) (interface{}, error) {
+		return tx.Runx("merge (u:User {id: $id})", neox.Args{"id": 1})
+	})
+	if err != nil {
+		t.Fatalf("Session.WriteTransactionx() error = %v, want the deadlock to be retried", err)
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 2 || !calls[1].Transaction {
+		t.Errorf("Calls() = %+v, want 2 transactional calls", calls)
+	}
+	if session.LastBookmark() == "" {
+		t.Errorf("Session.LastBookmark() is empty after a commit")
+	}
+
+	if unmet := fake.Unmet(); len(unmet) != 1 || unmet[0] != "match (u:User) return count(u)" {
+		t.Errorf("Unmet() = %v", unmet)
+	}
+
+	if fake.OpenSessions() != 1 {
+		t.Errorf("OpenSessions() = %d, want 1", fake.OpenSessions())
+	}
+	session.Close()
+	if fake.OpenSessions() != 0 {
+		t.Errorf("OpenSessions() = %d, want 0", fake.OpenSessions())
+	}
+}
+
+func TestDriver_SessionError(t *testing.T) {
+	t.Parallel()
+
+	fake := NewDriver()
+	fake.SessionError = errors.New("connection refused")
+
+	if _, err := fake.Neox().Sessionx(neo4j.AccessModeRead); err != fake.SessionError {
+		t.Errorf("Driver.Sessionx() error = %v, want %v", err, fake.SessionError)
+	}
+}
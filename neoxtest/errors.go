@@ -0,0 +1,42 @@
+package neoxtest
+
+import (
+	"fmt"
+	"strings"
+)
+
+// databaseError mirrors the failures reported by the server through the driver,
+// so injected errors are classified like real ones by neox and the driver
+type databaseError struct {
+	code    string
+	message string
+}
+
+// DatabaseError returns an error that behaves like a failure reported by the server
+// with the provided neo4j status code, ie: Neo.ClientError.Statement.SyntaxError
+func DatabaseError(code, message string) error {
+	return &databaseError{code: code, message: message}
+}
+
+func (e *databaseError) BoltError() bool {
+	return true
+}
+
+func (e *databaseError) Classification() string {
+	if parts := strings.Split(e.code, "."); len(parts) >= 2 {
+		return parts[1]
+	}
+	return ""
+}
+
+func (e *databaseError) Code() string {
+	return e.code
+}
+
+func (e *databaseError) Message() string {
+	return e.message
+}
+
+func (e *databaseError) Error() string {
+	return fmt.Sprintf("database returned error [%s]: %s", e.code, e.message)
+}
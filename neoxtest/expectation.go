@@ -0,0 +1,129 @@
+package neoxtest
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+	"github.com/syllabix/neox"
+)
+
+// Counters are the statistics reported in the summary of a scripted result
+type Counters struct {
+	NodesCreated         int `json:"nodesCreated,omitempty"`
+	NodesDeleted         int `json:"nodesDeleted,omitempty"`
+	RelationshipsCreated int `json:"relationshipsCreated,omitempty"`
+	RelationshipsDeleted int `json:"relationshipsDeleted,omitempty"`
+	PropertiesSet        int `json:"propertiesSet,omitempty"`
+	LabelsAdded          int `json:"labelsAdded,omitempty"`
+	LabelsRemoved        int `json:"labelsRemoved,omitempty"`
+	IndexesAdded         int `json:"indexesAdded,omitempty"`
+	IndexesRemoved       int `json:"indexesRemoved,omitempty"`
+	ConstraintsAdded     int `json:"constraintsAdded,omitempty"`
+	ConstraintsRemoved   int `json:"constraintsRemoved,omitempty"`
+}
+
+// Summary describes the summary of a scripted result
+type Summary struct {
+	Counters             Counters            `json:"counters"`
+	StatementType        neo4j.StatementType `json:"statementType,omitempty"`
+	Address              string              `json:"address,omitempty"`
+	Version              string              `json:"version,omitempty"`
+	ResultAvailableAfter time.Duration       `json:"resultAvailableAfter,omitempty"`
+	ResultConsumedAfter  time.Duration       `json:"resultConsumedAfter,omitempty"`
+}
+
+// An Expectation answers the statements it matches with a canned result or error.
+// Expectations are configured through chained method calls and are not safe to modify
+// once statements are being run
+type Expectation struct {
+	desc    string
+	match   func(cypher string) bool
+	args    neox.Args
+	keys    []string
+	rows    [][]interface{}
+	err     error
+	summary Summary
+	times   int
+	used    int
+}
+
+// WithArgs restricts the expectation to statements run with args equal to the provided args.
+// Args are compared in the form they are saved in fixtures, so that ie: int and int64 values
+// of the same number are equal. Expectations replaying fixtures match args the same way
+func (e *Expectation) WithArgs(args neox.Args) *Expectation {
+	e.args = args
+	return e
+}
+
+// Return answers matching statements with the provided keys and rows of values,
+// each row holding a value per key
+func (e *Expectation) Return(keys []string, rows ...[]interface{}) *Expectation {
+	e.keys = keys
+	e.rows = rows
+	return e
+}
+
+// ReturnError fails matching statements with the provided error
+func (e *Expectation) ReturnError(err error) *Expectation {
+	e.err = err
+	return e
+}
+
+// WithSummary sets the summary of the results of matching statements
+func (e *Expectation) WithSummary(summary Summary) *Expectation {
+	e.summary = summary
+	return e
+}
+
+// Times limits the number of statements the expectation answers,
+// after which later expectations are considered
+func (e *Expectation) Times(n int) *Expectation {
+	e.times = n
+	return e
+}
+
+// Once limits the expectation to answering a single statement
+func (e *Expectation) Once() *Expectation {
+	return e.Times(1)
+}
+
+// matches reports whether the expectation answers the provided statement
+func (e *Expectation) matches(cypher string, args map[string]interface{}) bool {
+	if e.times > 0 && e.used >= e.times {
+		return false
+	}
+	if !e.match(cypher) {
+		return false
+	}
+	return e.args == nil || sameArgs(e.args, args)
+}
+
+// sameArgs reports whether the args have the same encoding, args holding values that
+// can not be encoded never match
+func sameArgs(want, got map[string]interface{}) bool {
+	w, err := canonical(want)
+	if err != nil {
+		return false
+	}
+	g, err := canonical(got)
+	return err == nil && bytes.Equal(w, g)
+}
+
+func exact(cypher string) func(string) bool {
+	want := normalize(cypher)
+	return func(got string) bool {
+		return normalize(got) == want
+	}
+}
+
+func pattern(expr *regexp.Regexp) func(string) bool {
+	return expr.MatchString
+}
+
+// normalize collapses the whitespace of a statement
+func normalize(cypher string) string {
+	return strings.Join(strings.Fields(cypher), " ")
+}
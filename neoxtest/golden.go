@@ -0,0 +1,341 @@
+package neoxtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+	"github.com/syllabix/neox"
+)
+
+// Fixture is the recorded traffic of a Recorder, it is saved as JSON and
+// replayed through a fake Driver
+type Fixture struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a single recorded statement along with its outcome.
+// Args and record values are kept in their JSON encoded form
+type Interaction struct {
+	Cypher  string                 `json:"cypher"`
+	Args    map[string]interface{} `json:"args,omitempty"`
+	Keys    []string               `json:"keys,omitempty"`
+	Records [][]interface{}        `json:"records,omitempty"`
+	Summary *Summary               `json:"summary,omitempty"`
+	Error   *InteractionError      `json:"error,omitempty"`
+}
+
+// InteractionError is a recorded failure. Code is only
+// set for failures reported by the server
+type InteractionError struct {
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+}
+
+// err returns an error that behaves like the recorded one
+func (e *InteractionError) err() error {
+	if e.Code != "" {
+		return DatabaseError(e.Code, e.Message)
+	}
+	return errors.New(e.Message)
+}
+
+// Recorder is a neo4j.Driver that records the statements run through the
+// driver it wraps, for them to be replayed without a running Neo4j server.
+//
+// Results are fully consumed when their statement is run, so the recorded
+// fixture holds every record. It is safe for concurrent use
+type Recorder struct {
+	neo4j.Driver
+
+	mu       sync.Mutex
+	recorded []Interaction
+	err      error
+}
+
+// NewRecorder returns a Recorder wrapping the provided driver
+func NewRecorder(driver neo4j.Driver) *Recorder {
+	return &Recorder{Driver: driver}
+}
+
+// Neox returns a neox.Driver backed by the recorder
+func (r *Recorder) Neox() *neox.Driver {
+	return &neox.Driver{Driver: r}
+}
+
+// Session returns a session of the wrapped driver recording its statements
+func (r *Recorder) Session(accessMode neo4j.AccessMode, bookmarks ...string) (neo4j.Session, error) {
+	s, err := r.Driver.Session(accessMode, bookmarks...)
+	if err != nil {
+		return nil, err
+	}
+	return &recordingSession{Session: s, recorder: r}, nil
+}
+
+// Fixture returns the traffic recorded so far, or the first
+// value that could not be encoded
+func (r *Recorder) Fixture() (*Fixture, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &Fixture{Interactions: append([]Interaction(nil), r.recorded...)}, nil
+}
+
+// WriteTo writes the traffic recorded so far to w as indented JSON
+func (r *Recorder) WriteTo(w io.Writer) (int64, error) {
+	fixture, err := r.Fixture()
+	if err != nil {
+		return 0, err
+	}
+	b, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(append(b, '\n'))
+	return int64(n), err
+}
+
+// Save writes the traffic recorded so far to the file at the provided path
+func (r *Recorder) Save(path string) error {
+	var buf bytes.Buffer
+	if _, err := r.WriteTo(&buf); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// record runs the statement with the provided function and records its outcome,
+// returning an in-memory result holding the drained records
+func (r *Recorder) record(cypher string, params map[string]interface{}, fn func() (neo4j.Result, error)) (neo4j.Result, error) {
+	in := Interaction{Cypher: cypher}
+	args, err := encodeProps(params)
+	if err != nil {
+		r.fail(err)
+	}
+	if len(args) > 0 {
+		in.Args = args
+	}
+
+	res, err := fn()
+	if err != nil {
+		in.Error = recordError(err)
+		r.add(in)
+		return nil, err
+	}
+
+	drained := &result{}
+	drained.keys, drained.err = res.Keys()
+	in.Keys = drained.keys
+	for res.Next() {
+		rec := res.Record()
+		drained.records = append(drained.records, rec)
+
+		values, err := encodeList(rec.Values())
+		if err != nil {
+			r.fail(err)
+		}
+		row, _ := values.([]interface{})
+		in.Records = append(in.Records, row)
+	}
+	if err := res.Err(); err != nil {
+		drained.err = err
+	}
+
+	if drained.err == nil {
+		drained.summary, drained.err = res.Summary()
+	}
+	if drained.err != nil {
+		in.Error = recordError(drained.err)
+		in.Keys, in.Records = nil, nil
+	} else {
+		in.Summary = recordSummary(drained.summary)
+	}
+
+	r.add(in)
+	return drained, nil
+}
+
+func (r *Recorder) add(in Interaction) {
+	r.mu.Lock()
+	r.recorded = append(r.recorded, in)
+	r.mu.Unlock()
+}
+
+func (r *Recorder) fail(err error) {
+	r.mu.Lock()
+	if r.err == nil {
+		r.err = err
+	}
+	r.mu.Unlock()
+}
+
+// serverError is implemented by the failures reported by the server
+type serverError interface {
+	Code() string
+	Message() string
+}
+
+func recordError(err error) *InteractionError {
+	var serr serverError
+	if errors.As(err, &serr) {
+		return &InteractionError{Code: serr.Code(), Message: serr.Message()}
+	}
+	return &InteractionError{Message: err.Error()}
+}
+
+func recordSummary(rs neo4j.ResultSummary) *Summary {
+	if rs == nil {
+		return nil
+	}
+	s := &Summary{
+		StatementType:        rs.StatementType(),
+		ResultAvailableAfter: rs.ResultAvailableAfter(),
+		ResultConsumedAfter:  rs.ResultConsumedAfter(),
+	}
+	if server := rs.Server(); server != nil {
+		s.Address = server.Address()
+		s.Version = server.Version()
+	}
+	if c := rs.Counters(); c != nil {
+		s.Counters = Counters{
+			NodesCreated:         c.NodesCreated(),
+			NodesDeleted:         c.NodesDeleted(),
+			RelationshipsCreated: c.RelationshipsCreated(),
+			RelationshipsDeleted: c.RelationshipsDeleted(),
+			PropertiesSet:        c.PropertiesSet(),
+			LabelsAdded:          c.LabelsAdded(),
+			LabelsRemoved:        c.LabelsRemoved(),
+			IndexesAdded:         c.IndexesAdded(),
+			IndexesRemoved:       c.IndexesRemoved(),
+			ConstraintsAdded:     c.ConstraintsAdded(),
+			ConstraintsRemoved:   c.ConstraintsRemoved(),
+		}
+	}
+	return s
+}
+
+// recordingSession is a neo4j.Session recording its statements
+type recordingSession struct {
+	neo4j.Session
+	recorder *Recorder
+}
+
+func (s *recordingSession) Run(cypher string, params map[string]interface{}, configurers ...func(*neo4j.TransactionConfig)) (neo4j.Result, error) {
+	return s.recorder.record(cypher, params, func() (neo4j.Result, error) {
+		return s.Session.Run(cypher, params, configurers...)
+	})
+}
+
+func (s *recordingSession) BeginTransaction(configurers ...func(*neo4j.TransactionConfig)) (neo4j.Transaction, error) {
+	tx, err := s.Session.BeginTransaction(configurers...)
+	if err != nil {
+		return nil, err
+	}
+	return &recordingTransaction{Transaction: tx, recorder: s.recorder}, nil
+}
+
+func (s *recordingSession) ReadTransaction(work neo4j.TransactionWork, configurers ...func(*neo4j.TransactionConfig)) (interface{}, error) {
+	return s.Session.ReadTransaction(s.work(work), configurers...)
+}
+
+func (s *recordingSession) WriteTransaction(work neo4j.TransactionWork, configurers ...func(*neo4j.TransactionConfig)) (interface{}, error) {
+	return s.Session.WriteTransaction(s.work(work), configurers...)
+}
+
+func (s *recordingSession) work(work neo4j.TransactionWork) neo4j.TransactionWork {
+	return func(tx neo4j.Transaction) (interface{}, error) {
+		return work(&recordingTransaction{Transaction: tx, recorder: s.recorder})
+	}
+}
+
+// recordingTransaction is a neo4j.Transaction recording its statements
+type recordingTransaction struct {
+	neo4j.Transaction
+	recorder *Recorder
+}
+
+func (t *recordingTransaction) Run(cypher string, params map[string]interface{}) (neo4j.Result, error) {
+	return t.recorder.record(cypher, params, func() (neo4j.Result, error) {
+		return t.Transaction.Run(cypher, params)
+	})
+}
+
+// ReadFixture reads a fixture written by a Recorder
+func ReadFixture(r io.Reader) (*Fixture, error) {
+	var fixture Fixture
+	if err := json.NewDecoder(r).Decode(&fixture); err != nil {
+		return nil, fmt.Errorf("neoxtest: invalid fixture: %w", err)
+	}
+	return &fixture, nil
+}
+
+// Load reads the fixture at the provided path and returns a fake driver replaying it
+func Load(path string) (*Driver, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	fixture, err := ReadFixture(f)
+	if err != nil {
+		return nil, err
+	}
+	return fixture.Driver()
+}
+
+// Driver returns a fake driver replaying the fixture. Each interaction answers a single
+// statement with the same cypher and args, in the order they were recorded
+func (f *Fixture) Driver() (*Driver, error) {
+	d := NewDriver()
+	for i, in := range f.Interactions {
+		args, err := decodeProps(in.Args)
+		if err != nil {
+			return nil, fmt.Errorf("neoxtest: interaction %d: %w", i, err)
+		}
+		if args == nil {
+			args = neox.Args{}
+		}
+
+		e := d.On(in.Cypher).WithArgs(args).Once()
+
+		if in.Error != nil {
+			e.ReturnError(in.Error.err())
+			continue
+		}
+
+		rows := make([][]interface{}, len(in.Records))
+		for j, rec := range in.Records {
+			values, err := decodeValue([]interface{}(rec))
+			if err != nil {
+				return nil, fmt.Errorf("neoxtest: interaction %d: %w", i, err)
+			}
+			rows[j], _ = values.([]interface{})
+		}
+		e.Return(in.Keys, rows...)
+		if in.Summary != nil {
+			e.WithSummary(*in.Summary)
+		}
+	}
+	return d, nil
+}
+
+// canonical returns the JSON encoding of the provided args, as saved in a fixture
+func canonical(args map[string]interface{}) ([]byte, error) {
+	encoded, err := encodeProps(args)
+	if err != nil {
+		return nil, err
+	}
+	if len(encoded) == 0 {
+		encoded = nil
+	}
+	return json.Marshal(encoded)
+}
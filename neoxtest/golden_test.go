@@ -0,0 +1,208 @@
+package neoxtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+	"github.com/syllabix/neox"
+)
+
+func TestCodec_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("time zone database unavailable")
+	}
+
+	yolanda := NewNode(1, []string{"User"}, map[string]interface{}{"name": "Yolanda", "age": int64(17)})
+	jordan := NewNode(2, []string{"User", "Admin"}, nil)
+	follows := NewRelationship(3, 1, 2, "FOLLOWS", map[string]interface{}{"since": int64(2019)})
+
+	tests := []struct {
+		name  string
+		value interface{}
+	}{
+		{name: "nil", value: nil},
+		{name: "bool", value: true},
+		{name: "string", value: "Yolanda Erasmus"},
+		{name: "int", value: int64(math.MaxInt64)},
+		{name: "float", value: 65.234},
+		{name: "infinite float", value: math.Inf(-1)},
+		{name: "bytes", value: []byte{0x00, 0xff, 0x10}},
+		{name: "list", value: []interface{}{int64(1), "two", []interface{}{3.5}}},
+		{name: "map", value: map[string]interface{}{"a": int64(1), "b": []interface{}{"c"}}},
+		{name: "map with a tag as key", value: map[string]interface{}{"$int": "not an int"}},
+		{name: "node", value: yolanda},
+		{name: "relationship", value: follows},
+		{name: "path", value: NewPath([]neo4j.Node{yolanda, jordan}, []neo4j.Relationship{follows})},
+		{name: "date", value: neo4j.DateOf(time.Date(2019, 7, 14, 0, 0, 0, 0, time.UTC))},
+		{name: "local time", value: neo4j.LocalTimeOf(time.Date(0, 1, 1, 13, 45, 2, 123456789, time.UTC))},
+		{name: "offset time", value: neo4j.OffsetTimeOf(time.Date(0, 1, 1, 13, 45, 2, 12, time.FixedZone("", -5*3600)))},
+		{name: "local date time", value: neo4j.LocalDateTimeOf(time.Date(2019, 7, 14, 13, 45, 2, 500, time.UTC))},
+		{name: "date time with offset", value: time.Date(2019, 7, 14, 13, 45, 2, 0, time.FixedZone("", 3600))},
+		{name: "date time with zone", value: time.Date(2019, 7, 14, 13, 45, 2, 0, berlin)},
+		{name: "duration", value: neo4j.DurationOf(14, 3, 7200, 15)},
+		{name: "2d point", value: neo4j.NewPoint2D(7203, 1.5, -2)},
+		{name: "3d point", value: neo4j.NewPoint3D(9157, 1.5, -2, 42)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := encodeValue(tt.value)
+			if err != nil {
+				t.Fatalf("encodeValue() error = %v", err)
+			}
+			b, err := json.Marshal(encoded)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			var raw interface{}
+			if err := json.Unmarshal(b, &raw); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			got, err := decodeValue(raw)
+			if err != nil {
+				t.Fatalf("decodeValue() error = %v", err)
+			}
+
+			if want, ok := tt.value.(time.Time); ok {
+				tm, _ := got.(time.Time)
+				_, offset := tm.Zone()
+				_, wantOffset := want.Zone()
+				if !tm.Equal(want) || offset != wantOffset {
+					t.Errorf("decodeValue() got = %v, want %v", got, want)
+				}
+				return
+			}
+			if want, ok := tt.value.(*neo4j.Point); ok {
+				// the z coordinate of 2d points is NaN, which never equals itself
+				if p, _ := got.(*neo4j.Point); p == nil || p.String() != want.String() {
+					t.Errorf("decodeValue() got = %v, want %v", got, want)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.value) {
+				t.Errorf("decodeValue() got = %#v, want %#v (encoded as %s)", got, tt.value, b)
+			}
+		})
+	}
+
+	t.Run("Should fail on unsupported values", func(t *testing.T) {
+		if _, err := encodeValue(make(chan int)); err == nil {
+			t.Errorf("encodeValue() error = nil, want an error")
+		}
+	})
+}
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	yolanda := NewNode(1, []string{"User"}, map[string]interface{}{
+		"name":     "Yolanda Erasmus",
+		"age":      int64(17),
+		"birthday": neo4j.DateOf(time.Date(2002, 3, 9, 0, 0, 0, 0, time.UTC)),
+		"home":     neo4j.NewPoint3D(4979, 13.4, 52.5, 34),
+	})
+
+	live := NewDriver()
+	live.On("match (u:User {name: $name}) return u, u.age as user_age").
+		WithArgs(neox.Args{"name": "Yolanda Erasmus"}).
+		Return([]string{"u", "user_age"}, []interface{}{yolanda, int64(17)}).
+		WithSummary(Summary{Address: "localhost:7687", Version: "Neo4j/3.5.8"})
+	live.On("create (u:User {name: $name, age: $age})").
+		WithSummary(Summary{Counters: Counters{NodesCreated: 1, PropertiesSet: 2}, StatementType: neo4j.StatementTypeWriteOnly})
+	live.On("merge (u:User {name: $name})").
+		ReturnError(DatabaseError("Neo.ClientError.Statement.SyntaxError", "Invalid input 'X'"))
+
+	exercise := func(t *testing.T, driver *neox.Driver) {
+		session, err := driver.Sessionx(neo4j.AccessModeWrite)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer session.Close()
+
+		result, err := session.Runx("match (u:User {name: $name}) return u, u.age as user_age", neox.Args{"name": "Yolanda Erasmus"})
+		if err != nil {
+			t.Fatalf("Session.Runx() error = %v", err)
+		}
+		if !result.Next() {
+			t.Fatalf("Result.Next() = false, want a record")
+		}
+		u, _ := result.Record().Get("u")
+		if !reflect.DeepEqual(u, yolanda) {
+			t.Errorf("Result.Record() got = %#v, want %#v", u, yolanda)
+		}
+		summary, err := result.Consume()
+		if err != nil || summary.Server().Version() != "Neo4j/3.5.8" {
+			t.Errorf("Result.Consume() = %v, %v", summary, err)
+		}
+
+		_, err = session.WriteTransactionx(func(tx *neox.Transaction) (interface{}, error) {
+			result, err := tx.Runx("create (u:User {name: $name, age: $age})", neox.Args{"name": "Jordan Ames", "age": 34})
+			if err != nil {
+				return nil, err
+			}
+			summary, err := result.Consume()
+			if err != nil {
+				return nil, err
+			}
+			if summary.Counters().NodesCreated() != 1 || summary.StatementType() != neo4j.StatementTypeWriteOnly {
+				t.Errorf("Result.Consume() counters = %+v", summary.Counters())
+			}
+			return nil, nil
+		})
+		if err != nil {
+			t.Errorf("Session.WriteTransactionx() error = %v", err)
+		}
+
+		if _, err := session.Runx("merge (u:User {name: $name})", neox.Args{"name": "X"}); !errors.Is(err, neox.ErrSyntax) {
+			t.Errorf("Session.Runx() error = %v, want %v", err, neox.ErrSyntax)
+		}
+	}
+
+	recorder := NewRecorder(live)
+	exercise(t, recorder.Neox())
+
+	path := filepath.Join(t.TempDir(), "users.json")
+	if err := recorder.Save(path); err != nil {
+		t.Fatalf("Recorder.Save() error = %v", err)
+	}
+
+	replay, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	exercise(t, replay.Neox())
+
+	if unmet := replay.Unmet(); len(unmet) != 0 {
+		t.Errorf("Driver.Unmet() = %v, want none", unmet)
+	}
+
+	t.Run("Should not replay statements with different args", func(t *testing.T) {
+		var buf bytes.Buffer
+		if _, err := recorder.WriteTo(&buf); err != nil {
+			t.Fatal(err)
+		}
+		fixture, err := ReadFixture(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		replay, err := fixture.Driver()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		session, _ := replay.Neox().Sessionx(neo4j.AccessModeRead)
+		defer session.Close()
+		if _, err := session.Runx("match (u:User {name: $name}) return u, u.age as user_age", neox.Args{"name": "Jordan Ames"}); !errors.Is(err, ErrUnexpected) {
+			t.Errorf("Session.Runx() error = %v, want %v", err, ErrUnexpected)
+		}
+	})
+}
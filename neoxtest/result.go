@@ -0,0 +1,173 @@
+package neoxtest
+
+import (
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+// record is an in-memory neo4j.Record
+type record struct {
+	keys   []string
+	values []interface{}
+}
+
+func (r *record) Keys() []string {
+	return r.keys
+}
+
+func (r *record) Values() []interface{} {
+	return r.values
+}
+
+func (r *record) Get(key string) (interface{}, bool) {
+	for i, k := range r.keys {
+		if k == key && i < len(r.values) {
+			return r.values[i], true
+		}
+	}
+	return nil, false
+}
+
+func (r *record) GetByIndex(index int) interface{} {
+	if index < 0 || index >= len(r.values) {
+		return nil
+	}
+	return r.values[index]
+}
+
+// result is an in-memory neo4j.Result
+type result struct {
+	keys    []string
+	records []neo4j.Record
+	summary neo4j.ResultSummary
+	err     error
+	current neo4j.Record
+	next    int
+}
+
+func (r *result) Keys() ([]string, error) {
+	return r.keys, r.err
+}
+
+func (r *result) Next() bool {
+	if r.err != nil || r.next >= len(r.records) {
+		r.current = nil
+		return false
+	}
+	r.current = r.records[r.next]
+	r.next++
+	return true
+}
+
+func (r *result) Err() error {
+	return r.err
+}
+
+func (r *result) Record() neo4j.Record {
+	return r.current
+}
+
+func (r *result) Summary() (neo4j.ResultSummary, error) {
+	r.next = len(r.records)
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.summary, nil
+}
+
+func (r *result) Consume() (neo4j.ResultSummary, error) {
+	r.current = nil
+	return r.Summary()
+}
+
+// summary is an in-memory neo4j.ResultSummary
+type summary struct {
+	s         Summary
+	statement *statement
+}
+
+func (s *summary) Server() neo4j.ServerInfo {
+	return &server{address: s.s.Address, version: s.s.Version}
+}
+
+func (s *summary) Statement() neo4j.Statement {
+	return s.statement
+}
+
+func (s *summary) StatementType() neo4j.StatementType {
+	return s.s.StatementType
+}
+
+func (s *summary) Counters() neo4j.Counters {
+	return &counters{s.s.Counters}
+}
+
+func (s *summary) Plan() neo4j.Plan {
+	return nil
+}
+
+func (s *summary) Profile() neo4j.ProfiledPlan {
+	return nil
+}
+
+func (s *summary) Notifications() []neo4j.Notification {
+	return nil
+}
+
+func (s *summary) ResultAvailableAfter() time.Duration {
+	return s.s.ResultAvailableAfter
+}
+
+func (s *summary) ResultConsumedAfter() time.Duration {
+	return s.s.ResultConsumedAfter
+}
+
+// statement is an in-memory neo4j.Statement
+type statement struct {
+	cypher string
+	params map[string]interface{}
+}
+
+func (s *statement) Text() string {
+	return s.cypher
+}
+
+func (s *statement) Params() map[string]interface{} {
+	return s.params
+}
+
+// server is an in-memory neo4j.ServerInfo
+type server struct {
+	address string
+	version string
+}
+
+func (s *server) Address() string {
+	return s.address
+}
+
+func (s *server) Version() string {
+	return s.version
+}
+
+// counters is an in-memory neo4j.Counters
+type counters struct {
+	c Counters
+}
+
+func (c *counters) ContainsUpdates() bool {
+	return c.c != (Counters{})
+}
+
+func (c *counters) NodesCreated() int         { return c.c.NodesCreated }
+func (c *counters) NodesDeleted() int         { return c.c.NodesDeleted }
+func (c *counters) RelationshipsCreated() int { return c.c.RelationshipsCreated }
+func (c *counters) RelationshipsDeleted() int { return c.c.RelationshipsDeleted }
+func (c *counters) PropertiesSet() int        { return c.c.PropertiesSet }
+func (c *counters) LabelsAdded() int          { return c.c.LabelsAdded }
+func (c *counters) LabelsRemoved() int        { return c.c.LabelsRemoved }
+func (c *counters) IndexesAdded() int         { return c.c.IndexesAdded }
+func (c *counters) IndexesRemoved() int       { return c.c.IndexesRemoved }
+func (c *counters) ConstraintsAdded() int     { return c.c.ConstraintsAdded }
+func (c *counters) ConstraintsRemoved() int   { return c.c.ConstraintsRemoved }
@@ -0,0 +1,246 @@
+package packstream
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrTruncated is returned when the data ends in the middle of a value
+var ErrTruncated = errors.New("packstream: truncated data")
+
+// maxDepth bounds the nesting of decoded lists, maps and structures
+const maxDepth = 1024
+
+// Decoder decodes a sequence of values from a buffer
+type Decoder struct {
+	// Hydrate, when set, is called with every decoded structure
+	// and the value it returns is used in its place
+	Hydrate Hydrator
+
+	data  []byte
+	pos   int
+	depth int
+}
+
+// NewDecoder returns a decoder reading from the provided data
+func NewDecoder(data []byte) *Decoder {
+	return &Decoder{data: data}
+}
+
+// More reports whether there is data left to decode
+func (d *Decoder) More() bool {
+	return d.pos < len(d.data)
+}
+
+// Decode decodes the next value
+func (d *Decoder) Decode() (interface{}, error) {
+	marker, err := d.byte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case marker < 0x80 || marker >= 0xF0:
+		return int64(int8(marker)), nil
+	case marker&0xF0 == tinyString:
+		return d.string(int(marker & 0x0F))
+	case marker&0xF0 == tinyList:
+		return d.list(int(marker & 0x0F))
+	case marker&0xF0 == tinyMap:
+		return d.dictionary(int(marker & 0x0F))
+	case marker&0xF0 == tinyStruct:
+		return d.structure(int(marker & 0x0F))
+	}
+
+	switch marker {
+	case markerNull:
+		return nil, nil
+	case markerFalse:
+		return false, nil
+	case markerTrue:
+		return true, nil
+	case markerFloat:
+		b, err := d.next(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+	case markerInt8:
+		b, err := d.next(1)
+		if err != nil {
+			return nil, err
+		}
+		return int64(int8(b[0])), nil
+	case markerInt16:
+		b, err := d.next(2)
+		if err != nil {
+			return nil, err
+		}
+		return int64(int16(binary.BigEndian.Uint16(b))), nil
+	case markerInt32:
+		b, err := d.next(4)
+		if err != nil {
+			return nil, err
+		}
+		return int64(int32(binary.BigEndian.Uint32(b))), nil
+	case markerInt64:
+		b, err := d.next(8)
+		if err != nil {
+			return nil, err
+		}
+		return int64(binary.BigEndian.Uint64(b)), nil
+	case markerBytes8, markerBytes16, markerBytes32:
+		size, err := d.size(marker - markerBytes8)
+		if err != nil {
+			return nil, err
+		}
+		b, err := d.next(size)
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte(nil), b...), nil
+	case markerString8, markerString16, markerString32:
+		size, err := d.size(marker - markerString8)
+		if err != nil {
+			return nil, err
+		}
+		return d.string(size)
+	case markerList8, markerList16, markerList32:
+		size, err := d.size(marker - markerList8)
+		if err != nil {
+			return nil, err
+		}
+		return d.list(size)
+	case markerMap8, markerMap16, markerMap32:
+		size, err := d.size(marker - markerMap8)
+		if err != nil {
+			return nil, err
+		}
+		return d.dictionary(size)
+	case markerStruct8, markerStruct16:
+		size, err := d.size(marker - markerStruct8)
+		if err != nil {
+			return nil, err
+		}
+		return d.structure(size)
+	}
+
+	return nil, fmt.Errorf("packstream: unknown marker 0x%02X", marker)
+}
+
+func (d *Decoder) byte() (byte, error) {
+	if d.pos >= len(d.data) {
+		return 0, ErrTruncated
+	}
+	b := d.data[d.pos]
+	d.pos++
+	return b, nil
+}
+
+func (d *Decoder) next(n int) ([]byte, error) {
+	if n < 0 || len(d.data)-d.pos < n {
+		return nil, ErrTruncated
+	}
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+// size reads a size encoded on 1, 2 or 4 bytes, as
+// designated by the offset of the marker of its type
+func (d *Decoder) size(offset byte) (int, error) {
+	b, err := d.next(1 << offset)
+	if err != nil {
+		return 0, err
+	}
+	switch offset {
+	case 0:
+		return int(b[0]), nil
+	case 1:
+		return int(binary.BigEndian.Uint16(b)), nil
+	}
+	return int(binary.BigEndian.Uint32(b)), nil
+}
+
+func (d *Decoder) string(size int) (string, error) {
+	b, err := d.next(size)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func (d *Decoder) enter() error {
+	d.depth++
+	if d.depth > maxDepth {
+		return errors.New("packstream: maximum nesting depth exceeded")
+	}
+	return nil
+}
+
+// capacity bounds the memory allocated upfront for a collection, as
+// the size is read from untrusted data and each item takes at least a byte
+func (d *Decoder) capacity(size int) int {
+	if left := len(d.data) - d.pos; size > left {
+		return left
+	}
+	return size
+}
+
+func (d *Decoder) list(size int) ([]interface{}, error) {
+	if err := d.enter(); err != nil {
+		return nil, err
+	}
+	defer func() { d.depth-- }()
+
+	list := make([]interface{}, 0, d.capacity(size))
+	for i := 0; i < size; i++ {
+		v, err := d.Decode()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+	}
+	return list, nil
+}
+
+func (d *Decoder) dictionary(size int) (map[string]interface{}, error) {
+	if err := d.enter(); err != nil {
+		return nil, err
+	}
+	defer func() { d.depth-- }()
+
+	m := make(map[string]interface{}, d.capacity(size))
+	for i := 0; i < size; i++ {
+		k, err := d.Decode()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := k.(string)
+		if !ok {
+			return nil, fmt.Errorf("packstream: dictionary key of type %T", k)
+		}
+		if m[key], err = d.Decode(); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func (d *Decoder) structure(size int) (interface{}, error) {
+	tag, err := d.byte()
+	if err != nil {
+		return nil, err
+	}
+	fields, err := d.list(size)
+	if err != nil {
+		return nil, err
+	}
+	s := Structure{Tag: tag, Fields: fields}
+	if d.Hydrate == nil {
+		return s, nil
+	}
+	return d.Hydrate(s)
+}
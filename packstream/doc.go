@@ -0,0 +1,38 @@
+// Package packstream implements PackStream, the binary serialization format used by
+// the Bolt protocol to exchange values with Neo4j.
+//
+// Values are encoded from, and decoded to, the following Go types
+//
+//	PackStream   Go
+//	Null         nil
+//	Boolean      bool
+//	Integer      int64 (any integer type when encoding)
+//	Float        float64 (float32 when encoding)
+//	Bytes        []byte
+//	String       string
+//	List         []interface{} (any slice or array when encoding)
+//	Dictionary   map[string]interface{} (any map with string keys when encoding)
+//	Structure    Structure
+//
+// Values implementing Marshaler are encoded as the structure they return, and a Decoder
+// can hydrate decoded structures into richer values through its Hydrate function.
+//
+// The graph, temporal and spatial structures of the Bolt protocol are provided as Node,
+// Relationship, Path, Date, Time, LocalTime, LocalDateTime, Duration, Point2D and Point3D.
+// Hydrate turns decoded structures into these values, and time.Time is encoded as a
+// DateTime carrying its zone name, or its offset when the zone is not a named location.
+//
+// Structs are encoded as dictionaries keyed by their db tags, the same tags neox uses to
+// map records, and Unmarshal assigns decoded values back into them, rejecting conversions
+// that would lose information
+//
+//	type User struct {
+//		Name string `db:"user_name"`
+//		Age  int    `db:"user_age"`
+//	}
+//
+//	b, err := packstream.Marshal(User{"Yolanda Erasmus", 17})
+//	...
+//	var u User
+//	err = packstream.Unmarshal(b, &u)
+package packstream
@@ -0,0 +1,270 @@
+package packstream
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+)
+
+// markers of the PackStream types
+const (
+	markerNull     = 0xC0
+	markerFloat    = 0xC1
+	markerFalse    = 0xC2
+	markerTrue     = 0xC3
+	markerInt8     = 0xC8
+	markerInt16    = 0xC9
+	markerInt32    = 0xCA
+	markerInt64    = 0xCB
+	markerBytes8   = 0xCC
+	markerBytes16  = 0xCD
+	markerBytes32  = 0xCE
+	markerString8  = 0xD0
+	markerString16 = 0xD1
+	markerString32 = 0xD2
+	markerList8    = 0xD4
+	markerList16   = 0xD5
+	markerList32   = 0xD6
+	markerMap8     = 0xD8
+	markerMap16    = 0xD9
+	markerMap32    = 0xDA
+	markerStruct8  = 0xDC
+	markerStruct16 = 0xDD
+
+	tinyString = 0x80
+	tinyList   = 0x90
+	tinyMap    = 0xA0
+	tinyStruct = 0xB0
+)
+
+// UnsupportedTypeError is returned when encoding a value
+// that has no PackStream representation
+type UnsupportedTypeError struct {
+	Type reflect.Type
+}
+
+func (e *UnsupportedTypeError) Error() string {
+	return fmt.Sprintf("packstream: unsupported type %v", e.Type)
+}
+
+// Marshal returns the PackStream encoding of v
+func Marshal(v interface{}) ([]byte, error) {
+	return Append(nil, v)
+}
+
+// Append appends the PackStream encoding of v to dst and returns the extended buffer
+func Append(dst []byte, v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case nil:
+		return append(dst, markerNull), nil
+	case bool:
+		if v {
+			return append(dst, markerTrue), nil
+		}
+		return append(dst, markerFalse), nil
+	case int:
+		return appendInt(dst, int64(v)), nil
+	case int64:
+		return appendInt(dst, v), nil
+	case int32:
+		return appendInt(dst, int64(v)), nil
+	case float64:
+		return appendFloat(dst, v), nil
+	case float32:
+		return appendFloat(dst, float64(v)), nil
+	case string:
+		return appendString(dst, v), nil
+	case []byte:
+		return appendBytes(dst, v), nil
+	case []interface{}:
+		dst = appendHeader(dst, tinyList, markerList8, len(v))
+		return appendList(dst, v)
+	case []string:
+		dst = appendHeader(dst, tinyList, markerList8, len(v))
+		for _, s := range v {
+			dst = appendString(dst, s)
+		}
+		return dst, nil
+	case map[string]interface{}:
+		dst = appendHeader(dst, tinyMap, markerMap8, len(v))
+		for k, item := range v {
+			dst = appendString(dst, k)
+			var err error
+			if dst, err = Append(dst, item); err != nil {
+				return nil, err
+			}
+		}
+		return dst, nil
+	case Structure:
+		return appendStructure(dst, v)
+	case *Structure:
+		if v == nil {
+			return append(dst, markerNull), nil
+		}
+		return appendStructure(dst, *v)
+	case Marshaler:
+		s, err := v.MarshalPackStream()
+		if err != nil {
+			return nil, err
+		}
+		return appendStructure(dst, s)
+	case time.Time:
+		return appendStructure(dst, dateTime(v))
+	}
+	return appendReflect(dst, reflect.ValueOf(v))
+}
+
+func appendReflect(dst []byte, rv reflect.Value) ([]byte, error) {
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return appendInt(dst, rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := rv.Uint()
+		if u > math.MaxInt64 {
+			return nil, fmt.Errorf("packstream: integer %d overflows int64", u)
+		}
+		return appendInt(dst, int64(u)), nil
+	case reflect.Float32, reflect.Float64:
+		return appendFloat(dst, rv.Float()), nil
+	case reflect.Bool:
+		return Append(dst, rv.Bool())
+	case reflect.String:
+		return appendString(dst, rv.String()), nil
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return append(dst, markerNull), nil
+		}
+		return Append(dst, rv.Elem().Interface())
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return append(dst, markerNull), nil
+		}
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			return appendBytes(dst, b), nil
+		}
+		dst = appendHeader(dst, tinyList, markerList8, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			var err error
+			if dst, err = Append(dst, rv.Index(i).Interface()); err != nil {
+				return nil, err
+			}
+		}
+		return dst, nil
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			break
+		}
+		if rv.IsNil() {
+			return append(dst, markerNull), nil
+		}
+		dst = appendHeader(dst, tinyMap, markerMap8, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			dst = appendString(dst, iter.Key().String())
+			var err error
+			if dst, err = Append(dst, iter.Value().Interface()); err != nil {
+				return nil, err
+			}
+		}
+		return dst, nil
+	case reflect.Struct:
+		// values of types marshaled through a pointer receiver
+		ptr := reflect.New(rv.Type())
+		ptr.Elem().Set(rv)
+		if m, ok := ptr.Interface().(Marshaler); ok {
+			return Append(dst, m)
+		}
+		return appendStruct(dst, rv)
+	case reflect.Invalid:
+		return append(dst, markerNull), nil
+	}
+	return nil, &UnsupportedTypeError{Type: rv.Type()}
+}
+
+func appendList(dst []byte, list []interface{}) ([]byte, error) {
+	for _, item := range list {
+		var err error
+		if dst, err = Append(dst, item); err != nil {
+			return nil, err
+		}
+	}
+	return dst, nil
+}
+
+func appendStructure(dst []byte, s Structure) ([]byte, error) {
+	size := len(s.Fields)
+	switch {
+	case size < 0x10:
+		dst = append(dst, tinyStruct|byte(size))
+	case size <= math.MaxUint8:
+		dst = append(dst, markerStruct8, byte(size))
+	case size <= math.MaxUint16:
+		dst = append(dst, markerStruct16)
+		dst = binary.BigEndian.AppendUint16(dst, uint16(size))
+	default:
+		return nil, fmt.Errorf("packstream: structure with %d fields is too large", size)
+	}
+	dst = append(dst, s.Tag)
+	return appendList(dst, s.Fields)
+}
+
+func appendInt(dst []byte, n int64) []byte {
+	switch {
+	case n >= -16 && n <= math.MaxInt8:
+		return append(dst, byte(int8(n)))
+	case n >= math.MinInt8 && n <= math.MaxInt8:
+		return append(dst, markerInt8, byte(int8(n)))
+	case n >= math.MinInt16 && n <= math.MaxInt16:
+		dst = append(dst, markerInt16)
+		return binary.BigEndian.AppendUint16(dst, uint16(int16(n)))
+	case n >= math.MinInt32 && n <= math.MaxInt32:
+		dst = append(dst, markerInt32)
+		return binary.BigEndian.AppendUint32(dst, uint32(int32(n)))
+	}
+	dst = append(dst, markerInt64)
+	return binary.BigEndian.AppendUint64(dst, uint64(n))
+}
+
+func appendFloat(dst []byte, f float64) []byte {
+	dst = append(dst, markerFloat)
+	return binary.BigEndian.AppendUint64(dst, math.Float64bits(f))
+}
+
+func appendString(dst []byte, s string) []byte {
+	dst = appendHeader(dst, tinyString, markerString8, len(s))
+	return append(dst, s...)
+}
+
+func appendBytes(dst []byte, b []byte) []byte {
+	switch {
+	case len(b) <= math.MaxUint8:
+		dst = append(dst, markerBytes8, byte(len(b)))
+	case len(b) <= math.MaxUint16:
+		dst = append(dst, markerBytes16)
+		dst = binary.BigEndian.AppendUint16(dst, uint16(len(b)))
+	default:
+		dst = append(dst, markerBytes32)
+		dst = binary.BigEndian.AppendUint32(dst, uint32(len(b)))
+	}
+	return append(dst, b...)
+}
+
+// appendHeader appends the marker and size of a string, list or map. The 8, 16
+// and 32 bit markers of each type follow each other
+func appendHeader(dst []byte, tiny, marker8 byte, size int) []byte {
+	switch {
+	case size < 0x10:
+		return append(dst, tiny|byte(size))
+	case size <= math.MaxUint8:
+		return append(dst, marker8, byte(size))
+	case size <= math.MaxUint16:
+		dst = append(dst, marker8+1)
+		return binary.BigEndian.AppendUint16(dst, uint16(size))
+	}
+	dst = append(dst, marker8+2)
+	return binary.BigEndian.AppendUint32(dst, uint32(size))
+}
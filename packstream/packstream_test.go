@@ -0,0 +1,147 @@
+package packstream
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMarshal(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		value interface{}
+		want  []byte
+	}{
+		{name: "null", value: nil, want: []byte{0xC0}},
+		{name: "true", value: true, want: []byte{0xC3}},
+		{name: "false", value: false, want: []byte{0xC2}},
+		{name: "tiny int", value: 42, want: []byte{0x2A}},
+		{name: "tiny negative int", value: -16, want: []byte{0xF0}},
+		{name: "int8", value: -17, want: []byte{0xC8, 0xEF}},
+		{name: "int16", value: 1234, want: []byte{0xC9, 0x04, 0xD2}},
+		{name: "int32", value: int32(-70000), want: []byte{0xCA, 0xFF, 0xFE, 0xEE, 0x90}},
+		{name: "int64", value: int64(math.MaxInt64), want: []byte{0xCB, 0x7F, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}},
+		{name: "float", value: 1.1, want: []byte{0xC1, 0x3F, 0xF1, 0x99, 0x99, 0x99, 0x99, 0x99, 0x9A}},
+		{name: "tiny string", value: "a", want: []byte{0x81, 0x61}},
+		{name: "bytes", value: []byte{1, 2}, want: []byte{0xCC, 0x02, 0x01, 0x02}},
+		{name: "list", value: []interface{}{1, "b"}, want: []byte{0x92, 0x01, 0x81, 0x62}},
+		{name: "typed list", value: []int{1, 2}, want: []byte{0x92, 0x01, 0x02}},
+		{name: "map", value: map[string]interface{}{"a": 1}, want: []byte{0xA1, 0x81, 0x61, 0x01}},
+		{name: "structure", value: Structure{Tag: 0x4E, Fields: []interface{}{1}}, want: []byte{0xB1, 0x4E, 0x01}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Marshal(tt.value)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("Marshal() got = % X, want % X", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("Should fail on unsupported types", func(t *testing.T) {
+		var unsupported *UnsupportedTypeError
+		if _, err := Marshal(make(chan int)); !errors.As(err, &unsupported) {
+			t.Errorf("Marshal() error = %v, want an *UnsupportedTypeError", err)
+		}
+		if _, err := Marshal(map[int]string{1: "a"}); !errors.As(err, &unsupported) {
+			t.Errorf("Marshal() error = %v, want an *UnsupportedTypeError", err)
+		}
+	})
+}
+
+func TestUnmarshal_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	long := strings.Repeat("x", 70000)
+	list := make([]interface{}, 300)
+	for i := range list {
+		list[i] = int64(i * 1000)
+	}
+
+	tests := []struct {
+		name  string
+		value interface{}
+	}{
+		{name: "null", value: nil},
+		{name: "bool", value: true},
+		{name: "ints", value: []interface{}{int64(-16), int64(-17), int64(128), int64(-32769), int64(math.MinInt64)}},
+		{name: "float", value: -0.5},
+		{name: "infinite float", value: math.Inf(1)},
+		{name: "empty string", value: ""},
+		{name: "long string", value: long},
+		{name: "bytes", value: bytes.Repeat([]byte{7}, 300)},
+		{name: "long list", value: list},
+		{name: "nested map", value: map[string]interface{}{"a": []interface{}{map[string]interface{}{"b": nil}}}},
+		{name: "structure", value: Structure{Tag: 0x01, Fields: []interface{}{int64(1), "KNOWS", map[string]interface{}{}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Marshal(tt.value)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			var got interface{}
+			if err := Unmarshal(b, &got); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.value) {
+				t.Errorf("Unmarshal() got = %v, want %v", got, tt.value)
+			}
+		})
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should hydrate structures", func(t *testing.T) {
+		d := NewDecoder([]byte{0x91, 0xB1, 0x44, 0x01})
+		d.Hydrate = func(s Structure) (interface{}, error) {
+			return s.Fields[0], nil
+		}
+		got, err := d.Decode()
+		if err != nil || !reflect.DeepEqual(got, []interface{}{int64(1)}) {
+			t.Errorf("Decoder.Decode() got = %v, %v", got, err)
+		}
+	})
+
+	t.Run("Should decode a sequence of values", func(t *testing.T) {
+		d := NewDecoder([]byte{0x01, 0x81, 0x61})
+		var got []interface{}
+		for d.More() {
+			v, err := d.Decode()
+			if err != nil {
+				t.Fatalf("Decoder.Decode() error = %v", err)
+			}
+			got = append(got, v)
+		}
+		if !reflect.DeepEqual(got, []interface{}{int64(1), "a"}) {
+			t.Errorf("Decoder.Decode() got = %v", got)
+		}
+	})
+
+	t.Run("Should fail on invalid data", func(t *testing.T) {
+		for _, data := range [][]byte{
+			{0x82, 0x61},
+			{0xD0},
+			{0xCB, 0x01},
+			{0xA1, 0x01, 0x01},
+			{0xC4},
+			{0x01, 0x01},
+			bytes.Repeat([]byte{0x91}, maxDepth+1),
+		} {
+			var v interface{}
+			if err := Unmarshal(data, &v); err == nil {
+				t.Errorf("Unmarshal(% X) error = nil, want an error", data)
+			}
+		}
+	})
+}
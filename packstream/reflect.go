@@ -0,0 +1,241 @@
+package packstream
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// tag is the struct tag naming the key a field is mapped to, as used by neox
+const tag = "db"
+
+// UnmarshalTypeError is returned when a decoded value can not be assigned to the destination
+type UnmarshalTypeError struct {
+	Value string
+	Type  reflect.Type
+	Field string
+}
+
+func (e *UnmarshalTypeError) Error() string {
+	if e.Field != "" {
+		return fmt.Sprintf("packstream: cannot unmarshal %s into field %s of type %v", e.Value, e.Field, e.Type)
+	}
+	return fmt.Sprintf("packstream: cannot unmarshal %s into value of type %v", e.Value, e.Type)
+}
+
+// Unmarshal decodes the single value held by data and stores it in the value pointed to by v.
+// Structures are hydrated with Hydrate.
+//
+// Decoded values are converted to the type of the destination where it is lossless: integers
+// to any integer type they fit in, lists to slices and arrays, and dictionaries to maps with
+// string keys or to structs, whose fields are mapped with the db struct tag like neox.Result.ToStruct
+func Unmarshal(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("packstream: Unmarshal destination must be a non nil pointer")
+	}
+
+	d := NewDecoder(data)
+	d.Hydrate = Hydrate
+	decoded, err := d.Decode()
+	if err != nil {
+		return err
+	}
+	if d.More() {
+		return fmt.Errorf("packstream: %d trailing bytes", len(d.data)-d.pos)
+	}
+	return assign(rv.Elem(), decoded)
+}
+
+// field is a struct field mapped to a dictionary key
+type field struct {
+	key   string
+	index int
+}
+
+var fieldCache sync.Map
+
+// structFields returns the exported fields of a struct type that have a db tag
+func structFields(t reflect.Type) []field {
+	if cached, ok := fieldCache.Load(t); ok {
+		return cached.([]field)
+	}
+
+	var fields []field
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		key := strings.Split(f.Tag.Get(tag), ",")[0]
+		if f.PkgPath != "" || key == "" || key == "-" {
+			continue
+		}
+		fields = append(fields, field{key: key, index: i})
+	}
+	fieldCache.Store(t, fields)
+	return fields
+}
+
+// appendStruct encodes a struct as a dictionary of its mapped fields
+func appendStruct(dst []byte, rv reflect.Value) ([]byte, error) {
+	fields := structFields(rv.Type())
+	dst = appendHeader(dst, tinyMap, markerMap8, len(fields))
+	for _, f := range fields {
+		dst = appendString(dst, f.key)
+		var err error
+		if dst, err = Append(dst, rv.Field(f.index).Interface()); err != nil {
+			return nil, err
+		}
+	}
+	return dst, nil
+}
+
+// assign stores a decoded value in dst, converting it to the type of dst where it is lossless
+func assign(dst reflect.Value, v interface{}) error {
+	if v == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+
+	src := reflect.ValueOf(v)
+	if src.Type().AssignableTo(dst.Type()) {
+		dst.Set(src)
+		return nil
+	}
+	if src.Kind() == reflect.Ptr && src.Elem().Type().AssignableTo(dst.Type()) {
+		dst.Set(src.Elem())
+		return nil
+	}
+
+	mismatch := &UnmarshalTypeError{Value: describe(v), Type: dst.Type()}
+	switch dst.Kind() {
+	case reflect.Ptr:
+		elem := reflect.New(dst.Type().Elem())
+		if err := assign(elem.Elem(), v); err != nil {
+			return err
+		}
+		dst.Set(elem)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := v.(int64)
+		if !ok || dst.OverflowInt(n) {
+			return mismatch
+		}
+		dst.SetInt(n)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, ok := v.(int64)
+		if !ok || n < 0 || dst.OverflowUint(uint64(n)) {
+			return mismatch
+		}
+		dst.SetUint(uint64(n))
+		return nil
+	case reflect.Float32, reflect.Float64:
+		switch n := v.(type) {
+		case float64:
+			if dst.Kind() == reflect.Float32 && !math.IsInf(n, 0) && !math.IsNaN(n) && dst.OverflowFloat(n) {
+				return mismatch
+			}
+			dst.SetFloat(n)
+			return nil
+		case int64:
+			dst.SetFloat(float64(n))
+			return nil
+		}
+	case reflect.String:
+		if s, ok := v.(string); ok {
+			dst.SetString(s)
+			return nil
+		}
+	case reflect.Bool:
+		if b, ok := v.(bool); ok {
+			dst.SetBool(b)
+			return nil
+		}
+	case reflect.Slice:
+		list, ok := v.([]interface{})
+		if !ok {
+			break
+		}
+		slice := reflect.MakeSlice(dst.Type(), len(list), len(list))
+		for i, item := range list {
+			if err := assign(slice.Index(i), item); err != nil {
+				return err
+			}
+		}
+		dst.Set(slice)
+		return nil
+	case reflect.Array:
+		list, ok := v.([]interface{})
+		if !ok || len(list) != dst.Len() {
+			break
+		}
+		for i, item := range list {
+			if err := assign(dst.Index(i), item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		m, ok := v.(map[string]interface{})
+		if !ok || dst.Type().Key().Kind() != reflect.String {
+			break
+		}
+		out := reflect.MakeMapWithSize(dst.Type(), len(m))
+		for k, item := range m {
+			elem := reflect.New(dst.Type().Elem()).Elem()
+			if err := assign(elem, item); err != nil {
+				return err
+			}
+			out.SetMapIndex(reflect.ValueOf(k).Convert(dst.Type().Key()), elem)
+		}
+		dst.Set(out)
+		return nil
+	case reflect.Struct:
+		// the temporal types of the package convert to time.Time and back
+		if src.Kind() == reflect.Struct && src.Type().ConvertibleTo(dst.Type()) {
+			dst.Set(src.Convert(dst.Type()))
+			return nil
+		}
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			break
+		}
+		for _, f := range structFields(dst.Type()) {
+			item, ok := m[f.key]
+			if !ok {
+				continue
+			}
+			if err := assign(dst.Field(f.index), item); err != nil {
+				var mismatch *UnmarshalTypeError
+				if errors.As(err, &mismatch) && mismatch.Field == "" {
+					mismatch.Field = dst.Type().Field(f.index).Name
+				}
+				return err
+			}
+		}
+		return nil
+	}
+	return mismatch
+}
+
+func describe(v interface{}) string {
+	switch v.(type) {
+	case int64:
+		return "integer"
+	case float64:
+		return "float"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case []byte:
+		return "bytes"
+	case []interface{}:
+		return "list"
+	case map[string]interface{}:
+		return "dictionary"
+	}
+	return fmt.Sprintf("%T", v)
+}
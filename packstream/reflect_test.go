@@ -0,0 +1,143 @@
+package packstream
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type user struct {
+	Name     string         `db:"user_name"`
+	Age      uint8          `db:"user_age"`
+	Power    float32        `db:"user_strength"`
+	Tags     []string       `db:"tags"`
+	Friend   *user          `db:"friend"`
+	Born     time.Time      `db:"born"`
+	Scores   map[string]int `db:"scores"`
+	Home     Point2D        `db:"home"`
+	Ignored  string         `db:"-"`
+	Untagged string
+	Extra    map[string]string `db:"extra"`
+}
+
+func TestUnmarshal_Struct(t *testing.T) {
+	t.Parallel()
+
+	born := Date(time.Date(2002, 3, 9, 0, 0, 0, 0, time.UTC))
+	in := user{
+		Name:     "Yolanda Erasmus",
+		Age:      17,
+		Power:    65.25,
+		Tags:     []string{"admin"},
+		Friend:   &user{Name: "Jordan Ames", Age: 34},
+		Born:     time.Time(born),
+		Scores:   map[string]int{"chess": 1200},
+		Home:     Point2D{SRID: 4326, X: 13.4, Y: 52.5},
+		Ignored:  "ignored",
+		Untagged: "untagged",
+	}
+
+	b, err := Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var m map[string]interface{}
+	if err := Unmarshal(b, &m); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if _, ok := m["Untagged"]; ok || m["-"] != nil || m["user_name"] != "Yolanda Erasmus" {
+		t.Errorf("Marshal() encoded fields = %v", m)
+	}
+
+	var out user
+	if err := Unmarshal(b, &out); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	in.Ignored, in.Untagged = "", ""
+	in.Born = in.Born.In(time.FixedZone("Offset", 0))
+	if !out.Born.Equal(in.Born) {
+		t.Errorf("Unmarshal() born = %v, want %v", out.Born, in.Born)
+	}
+	out.Born = in.Born
+	if out.Friend == nil || out.Friend.Name != in.Friend.Name || out.Friend.Age != in.Friend.Age {
+		t.Errorf("Unmarshal() friend = %+v, want %+v", out.Friend, in.Friend)
+	}
+	out.Friend, in.Friend = nil, nil
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("Unmarshal() got = %+v, want %+v", out, in)
+	}
+
+	t.Run("Should convert temporal values to time.Time", func(t *testing.T) {
+		b, _ := Marshal(map[string]interface{}{"born": born})
+		var out user
+		if err := Unmarshal(b, &out); err != nil || !out.Born.Equal(time.Time(born)) {
+			t.Errorf("Unmarshal() born = %v, %v", out.Born, err)
+		}
+	})
+
+	t.Run("Should reject lossy conversions", func(t *testing.T) {
+		tests := []struct {
+			value interface{}
+			field string
+		}{
+			{map[string]interface{}{"user_age": 300}, "Age"},
+			{map[string]interface{}{"user_age": -1}, "Age"},
+			{map[string]interface{}{"user_name": 1}, "Name"},
+			{map[string]interface{}{"user_strength": math.MaxFloat64}, "Power"},
+			{map[string]interface{}{"tags": []interface{}{1}}, "Tags"},
+		}
+		for _, tt := range tests {
+			b, _ := Marshal(tt.value)
+			var out user
+			var mismatch *UnmarshalTypeError
+			if err := Unmarshal(b, &out); !errors.As(err, &mismatch) || mismatch.Field != tt.field {
+				t.Errorf("Unmarshal(%v) error = %v, want a mismatch on %s", tt.value, err, tt.field)
+			}
+		}
+	})
+
+	t.Run("Should require a pointer", func(t *testing.T) {
+		if err := Unmarshal(b, user{}); err == nil {
+			t.Errorf("Unmarshal() error = nil, want an error")
+		}
+	})
+}
+
+func FuzzUnmarshal(f *testing.F) {
+	seeds := []interface{}{
+		nil,
+		int64(-17),
+		"Yolanda",
+		[]interface{}{1.5, true, []byte{1}},
+		map[string]interface{}{"a": map[string]interface{}{}},
+		&Node{ID: 1, Labels: []string{"User"}},
+		Duration{Months: 1},
+		Point3D{X: 1},
+		Structure{Tag: TagPath, Fields: []interface{}{[]interface{}{}, []interface{}{}, []interface{}{}}},
+	}
+	for _, seed := range seeds {
+		b, err := Marshal(seed)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(b)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var v interface{}
+		if err := Unmarshal(data, &v); err != nil {
+			return
+		}
+		b, err := Marshal(v)
+		if err != nil {
+			t.Fatalf("Marshal() of a decoded %T error = %v", v, err)
+		}
+		var again interface{}
+		if err := Unmarshal(b, &again); err != nil {
+			t.Fatalf("Unmarshal() of a marshaled %T error = %v", v, err)
+		}
+	})
+}
@@ -0,0 +1,24 @@
+package packstream
+
+import "fmt"
+
+// Structure is a composite value made of a tag byte, identifying its type,
+// and a list of fields
+type Structure struct {
+	Tag    byte
+	Fields []interface{}
+}
+
+// String returns a readable representation of the structure
+func (s Structure) String() string {
+	return fmt.Sprintf("Structure<0x%02X>%v", s.Tag, s.Fields)
+}
+
+// A Marshaler is a value that is encoded as a structure
+type Marshaler interface {
+	MarshalPackStream() (Structure, error)
+}
+
+// A Hydrator converts a decoded structure into a richer value. It returns the
+// structure as is for tags it does not know about
+type Hydrator func(s Structure) (interface{}, error)
@@ -0,0 +1,405 @@
+package packstream
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Tags of the structures Neo4j uses to exchange graph, temporal and spatial values
+const (
+	TagNode                = 'N'
+	TagRelationship        = 'R'
+	TagUnboundRelationship = 'r'
+	TagPath                = 'P'
+	TagDate                = 'D'
+	TagTime                = 'T'
+	TagLocalTime           = 't'
+	TagDateTime            = 'F'
+	TagDateTimeZoneID      = 'f'
+	TagLocalDateTime       = 'd'
+	TagDuration            = 'E'
+	TagPoint2D             = 'X'
+	TagPoint3D             = 'Y'
+)
+
+// Node is a node of the graph
+type Node struct {
+	ID     int64
+	Labels []string
+	Props  map[string]interface{}
+}
+
+// Relationship is a relationship of the graph
+type Relationship struct {
+	ID      int64
+	StartID int64
+	EndID   int64
+	Type    string
+	Props   map[string]interface{}
+}
+
+// Path is a path of the graph. The start and end of its relationships are
+// those of the stored relationships, regardless of the direction of the traversal
+type Path struct {
+	Nodes         []*Node
+	Relationships []*Relationship
+}
+
+// Date is a date without a time zone. Only its year, month and day are significant
+type Date time.Time
+
+// Time is a time of day with a zone offset. Only its clock and offset are significant
+type Time time.Time
+
+// LocalTime is a time of day without a time zone. Only its clock is significant
+type LocalTime time.Time
+
+// LocalDateTime is a date and time without a time zone. The location is ignored
+type LocalDateTime time.Time
+
+// Duration is a temporal amount
+type Duration struct {
+	Months  int64
+	Days    int64
+	Seconds int64
+	Nanos   int
+}
+
+// Point2D is a point in a two dimensional coordinate reference system
+type Point2D struct {
+	SRID uint32
+	X    float64
+	Y    float64
+}
+
+// Point3D is a point in a three dimensional coordinate reference system
+type Point3D struct {
+	SRID uint32
+	X    float64
+	Y    float64
+	Z    float64
+}
+
+// MarshalPackStream encodes the node as a structure
+func (n *Node) MarshalPackStream() (Structure, error) {
+	labels := make([]interface{}, len(n.Labels))
+	for i, label := range n.Labels {
+		labels[i] = label
+	}
+	return Structure{Tag: TagNode, Fields: []interface{}{n.ID, labels, props(n.Props)}}, nil
+}
+
+// MarshalPackStream encodes the relationship as a structure
+func (r *Relationship) MarshalPackStream() (Structure, error) {
+	return Structure{Tag: TagRelationship, Fields: []interface{}{r.ID, r.StartID, r.EndID, r.Type, props(r.Props)}}, nil
+}
+
+// MarshalPackStream encodes the path as a structure, listing its unique nodes and
+// relationships along with the sequence of indices walking through them
+func (p *Path) MarshalPackStream() (Structure, error) {
+	if len(p.Nodes) != len(p.Relationships)+1 {
+		return Structure{}, fmt.Errorf("packstream: path with %d nodes and %d relationships", len(p.Nodes), len(p.Relationships))
+	}
+
+	var (
+		nodes   []interface{}
+		rels    []interface{}
+		indices []interface{}
+		nodeIdx = map[int64]int64{}
+		relIdx  = map[int64]int64{}
+		addNode = func(n *Node) int64 {
+			if i, ok := nodeIdx[n.ID]; ok {
+				return i
+			}
+			nodeIdx[n.ID] = int64(len(nodes))
+			nodes = append(nodes, n)
+			return nodeIdx[n.ID]
+		}
+	)
+	addNode(p.Nodes[0])
+	for i, r := range p.Relationships {
+		prev, next := p.Nodes[i], p.Nodes[i+1]
+		ri, ok := relIdx[r.ID]
+		if !ok {
+			rels = append(rels, Structure{Tag: TagUnboundRelationship, Fields: []interface{}{r.ID, r.Type, props(r.Props)}})
+			ri = int64(len(rels))
+			relIdx[r.ID] = ri
+		}
+		switch {
+		case r.StartID == prev.ID && r.EndID == next.ID:
+		case r.StartID == next.ID && r.EndID == prev.ID:
+			ri = -ri
+		default:
+			return Structure{}, fmt.Errorf("packstream: relationship %d does not connect nodes %d and %d", r.ID, prev.ID, next.ID)
+		}
+		indices = append(indices, ri, addNode(next))
+	}
+	return Structure{Tag: TagPath, Fields: []interface{}{nodes, rels, indices}}, nil
+}
+
+// MarshalPackStream encodes the date as a structure
+func (d Date) MarshalPackStream() (Structure, error) {
+	t := time.Time(d)
+	days := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix() / secondsPerDay
+	return Structure{Tag: TagDate, Fields: []interface{}{days}}, nil
+}
+
+// MarshalPackStream encodes the time as a structure
+func (t Time) MarshalPackStream() (Structure, error) {
+	_, offset := time.Time(t).Zone()
+	return Structure{Tag: TagTime, Fields: []interface{}{nanosOfDay(time.Time(t)), offset}}, nil
+}
+
+// MarshalPackStream encodes the local time as a structure
+func (t LocalTime) MarshalPackStream() (Structure, error) {
+	return Structure{Tag: TagLocalTime, Fields: []interface{}{nanosOfDay(time.Time(t))}}, nil
+}
+
+// MarshalPackStream encodes the local date time as a structure
+func (t LocalDateTime) MarshalPackStream() (Structure, error) {
+	seconds, nanos := wallClock(time.Time(t))
+	return Structure{Tag: TagLocalDateTime, Fields: []interface{}{seconds, nanos}}, nil
+}
+
+// MarshalPackStream encodes the duration as a structure
+func (d Duration) MarshalPackStream() (Structure, error) {
+	return Structure{Tag: TagDuration, Fields: []interface{}{d.Months, d.Days, d.Seconds, d.Nanos}}, nil
+}
+
+// MarshalPackStream encodes the point as a structure
+func (p Point2D) MarshalPackStream() (Structure, error) {
+	return Structure{Tag: TagPoint2D, Fields: []interface{}{p.SRID, p.X, p.Y}}, nil
+}
+
+// MarshalPackStream encodes the point as a structure
+func (p Point3D) MarshalPackStream() (Structure, error) {
+	return Structure{Tag: TagPoint3D, Fields: []interface{}{p.SRID, p.X, p.Y, p.Z}}, nil
+}
+
+const secondsPerDay = 24 * 60 * 60
+
+func nanosOfDay(t time.Time) int64 {
+	return int64(t.Hour())*int64(time.Hour) +
+		int64(t.Minute())*int64(time.Minute) +
+		int64(t.Second())*int64(time.Second) +
+		int64(t.Nanosecond())
+}
+
+// wallClock returns the seconds and nanoseconds since the epoch
+// of the date and clock of t, ignoring its location
+func wallClock(t time.Time) (int64, int) {
+	utc := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	return utc.Unix(), utc.Nanosecond()
+}
+
+// dateTime encodes an instant as a structure, using the name of its location
+// when it is a zone known to the time zone database and its offset otherwise
+func dateTime(t time.Time) Structure {
+	seconds, nanos := wallClock(t)
+	if name := t.Location().String(); name != "" && name != "Local" {
+		if _, err := time.LoadLocation(name); err == nil {
+			return Structure{Tag: TagDateTimeZoneID, Fields: []interface{}{seconds, nanos, name}}
+		}
+	}
+	_, offset := t.Zone()
+	return Structure{Tag: TagDateTime, Fields: []interface{}{seconds, nanos, offset}}
+}
+
+func props(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return map[string]interface{}{}
+	}
+	return m
+}
+
+// Hydrate converts the structures Neo4j uses for graph, temporal and spatial values into
+// the corresponding types of the package. Zoned date times are converted into a time.Time.
+// Structures with other tags are returned as is
+func Hydrate(s Structure) (interface{}, error) {
+	switch s.Tag {
+	case TagNode:
+		if err := fields(s, 3); err != nil {
+			return nil, err
+		}
+		n := &Node{ID: asInt(s.Fields[0])}
+		labels, _ := s.Fields[1].([]interface{})
+		for _, label := range labels {
+			if l, ok := label.(string); ok {
+				n.Labels = append(n.Labels, l)
+			}
+		}
+		n.Props, _ = s.Fields[2].(map[string]interface{})
+		return n, nil
+	case TagRelationship:
+		if err := fields(s, 5); err != nil {
+			return nil, err
+		}
+		relType, _ := s.Fields[3].(string)
+		props, _ := s.Fields[4].(map[string]interface{})
+		return &Relationship{
+			ID:      asInt(s.Fields[0]),
+			StartID: asInt(s.Fields[1]),
+			EndID:   asInt(s.Fields[2]),
+			Type:    relType,
+			Props:   props,
+		}, nil
+	case TagUnboundRelationship:
+		if err := fields(s, 3); err != nil {
+			return nil, err
+		}
+		relType, _ := s.Fields[1].(string)
+		props, _ := s.Fields[2].(map[string]interface{})
+		return &unboundRelationship{id: asInt(s.Fields[0]), relType: relType, props: props}, nil
+	case TagPath:
+		return hydratePath(s)
+	case TagDate:
+		if err := fields(s, 1); err != nil {
+			return nil, err
+		}
+		return Date(time.Unix(asInt(s.Fields[0])*secondsPerDay, 0).UTC()), nil
+	case TagTime:
+		if err := fields(s, 2); err != nil {
+			return nil, err
+		}
+		zone := time.FixedZone("Offset", int(asInt(s.Fields[1])))
+		return Time(time.Date(0, 1, 1, 0, 0, 0, 0, zone).Add(time.Duration(asInt(s.Fields[0])))), nil
+	case TagLocalTime:
+		if err := fields(s, 1); err != nil {
+			return nil, err
+		}
+		return LocalTime(time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(asInt(s.Fields[0])))), nil
+	case TagDateTime:
+		if err := fields(s, 3); err != nil {
+			return nil, err
+		}
+		offset := int(asInt(s.Fields[2]))
+		return time.Unix(asInt(s.Fields[0])-int64(offset), asInt(s.Fields[1])).In(time.FixedZone("Offset", offset)), nil
+	case TagDateTimeZoneID:
+		if err := fields(s, 3); err != nil {
+			return nil, err
+		}
+		name, _ := s.Fields[2].(string)
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, fmt.Errorf("packstream: unknown time zone %q: %w", name, err)
+		}
+		wall := time.Unix(asInt(s.Fields[0]), asInt(s.Fields[1])).UTC()
+		return time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), wall.Nanosecond(), loc), nil
+	case TagLocalDateTime:
+		if err := fields(s, 2); err != nil {
+			return nil, err
+		}
+		return LocalDateTime(time.Unix(asInt(s.Fields[0]), asInt(s.Fields[1])).UTC()), nil
+	case TagDuration:
+		if err := fields(s, 4); err != nil {
+			return nil, err
+		}
+		return Duration{
+			Months:  asInt(s.Fields[0]),
+			Days:    asInt(s.Fields[1]),
+			Seconds: asInt(s.Fields[2]),
+			Nanos:   int(asInt(s.Fields[3])),
+		}, nil
+	case TagPoint2D:
+		if err := fields(s, 3); err != nil {
+			return nil, err
+		}
+		return Point2D{SRID: uint32(asInt(s.Fields[0])), X: asFloat(s.Fields[1]), Y: asFloat(s.Fields[2])}, nil
+	case TagPoint3D:
+		if err := fields(s, 4); err != nil {
+			return nil, err
+		}
+		return Point3D{SRID: uint32(asInt(s.Fields[0])), X: asFloat(s.Fields[1]), Y: asFloat(s.Fields[2]), Z: asFloat(s.Fields[3])}, nil
+	}
+	return s, nil
+}
+
+// unboundRelationship is a relationship of a path, without its start and end
+type unboundRelationship struct {
+	id      int64
+	relType string
+	props   map[string]interface{}
+}
+
+// MarshalPackStream encodes the relationship as a structure
+func (r *unboundRelationship) MarshalPackStream() (Structure, error) {
+	return Structure{Tag: TagUnboundRelationship, Fields: []interface{}{r.id, r.relType, props(r.props)}}, nil
+}
+
+// hydratePath builds a path from its unique nodes and relationships, and the sequence of
+// indices alternating between a relationship and a node. Relationship indices are one based
+// and negative when the relationship is traversed against its direction
+func hydratePath(s Structure) (*Path, error) {
+	if err := fields(s, 3); err != nil {
+		return nil, err
+	}
+	nodes, _ := s.Fields[0].([]interface{})
+	rels, _ := s.Fields[1].([]interface{})
+	indices, _ := s.Fields[2].([]interface{})
+	if len(nodes) == 0 || len(indices)%2 != 0 {
+		return nil, errors.New("packstream: invalid path structure")
+	}
+
+	node := func(i int64) (*Node, error) {
+		if i < 0 || i >= int64(len(nodes)) {
+			return nil, errors.New("packstream: path node index out of range")
+		}
+		n, ok := nodes[i].(*Node)
+		if !ok {
+			return nil, errors.New("packstream: path holds a value that is not a node")
+		}
+		return n, nil
+	}
+
+	prev, err := node(0)
+	if err != nil {
+		return nil, err
+	}
+	p := &Path{Nodes: []*Node{prev}}
+	for i := 0; i < len(indices); i += 2 {
+		ri, ni := asInt(indices[i]), asInt(indices[i+1])
+		next, err := node(ni)
+		if err != nil {
+			return nil, err
+		}
+
+		forward := ri > 0
+		if !forward {
+			ri = -ri
+		}
+		if ri < 1 || ri > int64(len(rels)) {
+			return nil, errors.New("packstream: path relationship index out of range")
+		}
+		u, ok := rels[ri-1].(*unboundRelationship)
+		if !ok {
+			return nil, errors.New("packstream: path holds a value that is not a relationship")
+		}
+
+		rel := &Relationship{ID: u.id, Type: u.relType, Props: u.props, StartID: prev.ID, EndID: next.ID}
+		if !forward {
+			rel.StartID, rel.EndID = next.ID, prev.ID
+		}
+		p.Nodes = append(p.Nodes, next)
+		p.Relationships = append(p.Relationships, rel)
+		prev = next
+	}
+	return p, nil
+}
+
+func fields(s Structure, n int) error {
+	if len(s.Fields) != n {
+		return fmt.Errorf("packstream: structure 0x%02X has %d fields, want %d", s.Tag, len(s.Fields), n)
+	}
+	return nil
+}
+
+func asInt(v interface{}) int64 {
+	n, _ := v.(int64)
+	return n
+}
+
+func asFloat(v interface{}) float64 {
+	f, _ := v.(float64)
+	return f
+}
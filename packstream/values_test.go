@@ -0,0 +1,153 @@
+package packstream
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHydrate_Path(t *testing.T) {
+	t.Parallel()
+
+	node := func(id int64) Structure {
+		return Structure{Tag: TagNode, Fields: []interface{}{id, []interface{}{"User"}, map[string]interface{}{}}}
+	}
+	rel := func(id int64) Structure {
+		return Structure{Tag: TagUnboundRelationship, Fields: []interface{}{id, "FOLLOWS", map[string]interface{}{}}}
+	}
+
+	// (1)-[10]->(2)<-[11]-(3)
+	b, err := Marshal(Structure{Tag: TagPath, Fields: []interface{}{
+		[]interface{}{node(1), node(2), node(3)},
+		[]interface{}{rel(10), rel(11)},
+		[]interface{}{int64(1), int64(1), int64(-2), int64(2)},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := NewDecoder(b)
+	d.Hydrate = Hydrate
+	v, err := d.Decode()
+	if err != nil {
+		t.Fatalf("Hydrate() error = %v", err)
+	}
+
+	p, ok := v.(*Path)
+	if !ok || len(p.Nodes) != 3 || len(p.Relationships) != 2 {
+		t.Fatalf("Hydrate() got = %#v", v)
+	}
+	got := [][2]int64{
+		{p.Relationships[0].StartID, p.Relationships[0].EndID},
+		{p.Relationships[1].StartID, p.Relationships[1].EndID},
+	}
+	if want := [][2]int64{{1, 2}, {3, 2}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Hydrate() relationships = %v, want %v", got, want)
+	}
+
+	t.Run("Should marshal paths", func(t *testing.T) {
+		b, err := Marshal(p)
+		if err != nil {
+			t.Fatalf("Marshal() error = %v", err)
+		}
+		var again *Path
+		if err := Unmarshal(b, &again); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		if !reflect.DeepEqual(again, p) {
+			t.Errorf("Unmarshal() got = %+v, want %+v", again, p)
+		}
+	})
+
+	t.Run("Should reject disconnected paths", func(t *testing.T) {
+		broken := &Path{Nodes: p.Nodes[:2], Relationships: p.Relationships[1:]}
+		if _, err := Marshal(broken); err == nil {
+			t.Errorf("Marshal() error = nil, want an error")
+		}
+	})
+}
+
+func TestValues_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("time zone database unavailable")
+	}
+
+	tests := []struct {
+		name  string
+		value interface{}
+		equal func(got interface{}) bool
+	}{
+		{
+			name:  "date",
+			value: Date(time.Date(1969, 7, 20, 0, 0, 0, 0, time.UTC)),
+		},
+		{
+			name:  "local time",
+			value: LocalTime(time.Date(0, 1, 1, 20, 17, 40, 5, time.UTC)),
+		},
+		{
+			name:  "local date time",
+			value: LocalDateTime(time.Date(1969, 7, 20, 20, 17, 40, 5, time.UTC)),
+		},
+		{
+			name:  "duration",
+			value: Duration{Months: 1, Days: 2, Seconds: 3, Nanos: 4},
+		},
+		{
+			name:  "point",
+			value: Point3D{SRID: 9157, X: 1, Y: 2, Z: 3},
+		},
+		{
+			name:  "time",
+			value: Time(time.Date(0, 1, 1, 20, 17, 40, 0, time.FixedZone("Offset", 3600))),
+			equal: func(got interface{}) bool {
+				tm, ok := got.(Time)
+				_, offset := time.Time(tm).Zone()
+				return ok && time.Time(tm).Hour() == 20 && offset == 3600
+			},
+		},
+		{
+			name:  "date time with offset",
+			value: time.Date(1969, 7, 20, 20, 17, 40, 0, time.FixedZone("", -7200)),
+			equal: func(got interface{}) bool {
+				tm, ok := got.(time.Time)
+				_, offset := tm.Zone()
+				return ok && tm.Equal(time.Date(1969, 7, 20, 20, 17, 40, 0, time.FixedZone("", -7200))) && offset == -7200
+			},
+		},
+		{
+			name:  "date time with zone",
+			value: time.Date(2019, 7, 20, 20, 17, 40, 0, berlin),
+			equal: func(got interface{}) bool {
+				tm, ok := got.(time.Time)
+				return ok && tm.Equal(time.Date(2019, 7, 20, 20, 17, 40, 0, berlin)) && tm.Location().String() == "Europe/Berlin"
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Marshal(tt.value)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			d := NewDecoder(b)
+			d.Hydrate = Hydrate
+			got, err := d.Decode()
+			if err != nil {
+				t.Fatalf("Hydrate() error = %v", err)
+			}
+			if tt.equal != nil {
+				if !tt.equal(got) {
+					t.Errorf("Hydrate() got = %v, want %v", got, tt.value)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.value) {
+				t.Errorf("Hydrate() got = %#v, want %#v", got, tt.value)
+			}
+		})
+	}
+}
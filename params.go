@@ -1,5 +1,34 @@
 package neox
 
+import (
+	"fmt"
+	"reflect"
+)
+
 // Args can be used to pass named arguments
 // to a cypher query
 type Args map[string]interface{}
+
+// ToArgs converts a struct, or pointer to a struct, to Args holding its fields by their
+// db tags, the reverse of Result.ToStruct. Args and maps with string keys are returned as Args
+func ToArgs(v interface{}) (Args, error) {
+	switch v := v.(type) {
+	case Args:
+		return v, nil
+	case map[string]interface{}:
+		return Args(v), nil
+	}
+
+	value := reflect.ValueOf(v)
+	if value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("neox: can not convert %T to args", v)
+	}
+	e, err := entityOf(value.Type())
+	if err != nil {
+		return nil, err
+	}
+	return Args(e.properties(value)), nil
+}
@@ -0,0 +1,175 @@
+package neox
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+// Direction is the direction of a relationship relative to the traversal of a path
+type Direction int
+
+// directions of a relationship
+const (
+	// Forward relationships go from the start to the end of their segment
+	Forward Direction = iota
+
+	// Backward relationships go from the end to the start of their segment
+	Backward
+)
+
+func (d Direction) String() string {
+	if d == Backward {
+		return "backward"
+	}
+	return "forward"
+}
+
+// Segment is a step of a path, traversing a relationship from one node to the next
+type Segment struct {
+	Start        neo4j.Node
+	Relationship neo4j.Relationship
+	End          neo4j.Node
+	Direction    Direction
+}
+
+// ToStruct maps the start node, the relationship and the end node of the segment to the
+// provided structs, as Result.ToStruct does. Any of them may be nil to skip it, it fails
+// when a value can not be mapped to its struct
+func (s Segment) ToStruct(start, rel, end interface{}) error {
+	for _, m := range []struct {
+		name  string
+		value interface{}
+		dest  interface{}
+	}{{"start", s.Start, start}, {"relationship", s.Relationship, rel}, {"end", s.End, end}} {
+		if m.dest == nil {
+			continue
+		}
+		v := reflect.ValueOf(m.dest)
+		if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+			return ErrInvalidArg
+		}
+		if !setValue(v.Elem(), m.value) {
+			return fmt.Errorf("neox: can not map the %s of the segment to %s", m.name, v.Elem().Type())
+		}
+	}
+	return nil
+}
+
+// Path is a path returned by neo4j as the ordered segments it traverses. It implements
+// neo4j.Path, and can be created from the values of a record with Record.GetPath
+type Path struct {
+	// Start and End are the first and the last node of the path, the same node for
+	// a path without segments
+	Start neo4j.Node
+	End   neo4j.Node
+
+	Segments []Segment
+}
+
+// NewPath returns the segments of a path, failing if its relationships do not connect its nodes
+func NewPath(p neo4j.Path) (*Path, error) {
+	nodes, rels := p.Nodes(), p.Relationships()
+	if len(nodes) == 0 || len(rels) != len(nodes)-1 {
+		return nil, fmt.Errorf("neox: a path of %d nodes can not have %d relationships", len(nodes), len(rels))
+	}
+
+	path := &Path{Start: nodes[0], End: nodes[len(nodes)-1], Segments: make([]Segment, len(rels))}
+	for i, rel := range rels {
+		s := Segment{Start: nodes[i], Relationship: rel, End: nodes[i+1]}
+		switch start, end := s.Start.Id(), s.End.Id(); {
+		case rel.StartId() == start && rel.EndId() == end:
+			s.Direction = Forward
+		case rel.StartId() == end && rel.EndId() == start:
+			s.Direction = Backward
+		default:
+			return nil, fmt.Errorf("neox: relationship %d does not connect nodes %d and %d", rel.Id(), start, end)
+		}
+		path.Segments[i] = s
+	}
+	return path, nil
+}
+
+// Len returns the number of segments of the path
+func (p *Path) Len() int {
+	return len(p.Segments)
+}
+
+// Nodes returns the nodes of the path in the order they are traversed
+func (p *Path) Nodes() []neo4j.Node {
+	nodes := []neo4j.Node{p.Start}
+	for _, s := range p.Segments {
+		nodes = append(nodes, s.End)
+	}
+	return nodes
+}
+
+// Relationships returns the relationships of the path in the order they are traversed
+func (p *Path) Relationships() []neo4j.Relationship {
+	rels := make([]neo4j.Relationship, len(p.Segments))
+	for i, s := range p.Segments {
+		rels[i] = s.Relationship
+	}
+	return rels
+}
+
+// NodesTo maps the nodes of the path to the elements of dest, a pointer to a slice of
+// structs or of pointers to structs, as Result.ToStruct does
+func (p *Path) NodesTo(dest interface{}) error {
+	nodes := p.Nodes()
+	list := make([]interface{}, len(nodes))
+	for i, n := range nodes {
+		list[i] = n
+	}
+	return setList(dest, list)
+}
+
+// RelationshipsTo maps the relationships of the path to the elements of dest, a pointer to
+// a slice of structs or of pointers to structs, as Result.ToStruct does
+func (p *Path) RelationshipsTo(dest interface{}) error {
+	list := make([]interface{}, len(p.Segments))
+	for i, s := range p.Segments {
+		list[i] = s.Relationship
+	}
+	return setList(dest, list)
+}
+
+func setList(dest interface{}, list []interface{}) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+		return errors.New("neox: the provided destination is not a pointer to a slice")
+	}
+	if !setValue(v.Elem(), list) {
+		return fmt.Errorf("neox: can not map the path to %s", v.Elem().Type())
+	}
+	return nil
+}
+
+// GetPathAtIndex retrieves the path at the provided index, returning false when the
+// value is not a path
+func (r *Record) GetPathAtIndex(index int) (*Path, bool) {
+	return pathOf(r.GetByIndex(index))
+}
+
+// GetPath retrieves the path for the provided key. If the key does not exist, or the
+// value is not a path, the method returns nil and false
+func (r *Record) GetPath(key string) (*Path, bool) {
+	v, ok := r.Get(key)
+	if !ok {
+		return nil, false
+	}
+	return pathOf(v)
+}
+
+func pathOf(v interface{}) (*Path, bool) {
+	switch v := v.(type) {
+	case *Path:
+		return v, true
+	case neo4j.Path:
+		p, err := NewPath(v)
+		return p, err == nil
+	}
+	return nil, false
+}
@@ -0,0 +1,130 @@
+package neox_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+	"github.com/syllabix/neox"
+	"github.com/syllabix/neox/neoxtest"
+)
+
+func TestNewPath(t *testing.T) {
+	t.Parallel()
+
+	a := neoxtest.NewNode(1, []string{"Person"}, map[string]interface{}{"id": "a", "name": "Alice"})
+	b := neoxtest.NewNode(2, []string{"Person"}, map[string]interface{}{"id": "b", "name": "Bob"})
+	c := neoxtest.NewNode(3, []string{"Person"}, map[string]interface{}{"id": "c", "name": "Carol"})
+	ab := neoxtest.NewRelationship(10, 1, 2, "FRIENDS_WITH", map[string]interface{}{"since": int64(2019)})
+	cb := neoxtest.NewRelationship(11, 3, 2, "FRIENDS_WITH", map[string]interface{}{"since": int64(2021)})
+
+	tests := []struct {
+		name    string
+		path    neo4j.Path
+		want    []neox.Direction
+		wantErr bool
+	}{
+		{name: "Should build paths of a single node", path: neoxtest.NewPath([]neo4j.Node{a}, nil), want: []neox.Direction{}},
+		{
+			name: "Should tell the direction of relationships",
+			path: neoxtest.NewPath([]neo4j.Node{a, b, c}, []neo4j.Relationship{ab, cb}),
+			want: []neox.Direction{neox.Forward, neox.Backward},
+		},
+		{
+			name:    "Should reject disconnected paths",
+			path:    &brokenPath{nodes: []neo4j.Node{a, c}, rels: []neo4j.Relationship{ab}},
+			wantErr: true,
+		},
+		{
+			name:    "Should reject paths without nodes",
+			path:    &brokenPath{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := neox.NewPath(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewPath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			directions := []neox.Direction{}
+			for _, s := range got.Segments {
+				directions = append(directions, s.Direction)
+			}
+			if !reflect.DeepEqual(directions, tt.want) {
+				t.Errorf("NewPath() directions = %v, want %v", directions, tt.want)
+			}
+			if got.Len() != len(tt.want) || !reflect.DeepEqual(got.Nodes(), tt.path.Nodes()) {
+				t.Errorf("Path.Nodes() got = %v, want %v", got.Nodes(), tt.path.Nodes())
+			}
+			if rels := tt.path.Relationships(); len(rels) > 0 && !reflect.DeepEqual(got.Relationships(), rels) {
+				t.Errorf("Path.Relationships() got = %v, want %v", got.Relationships(), rels)
+			}
+		})
+	}
+
+	t.Run("Should map segments to structs", func(t *testing.T) {
+		record := &neox.Record{Record: neoxtest.NewRecord([]string{"p"}, []interface{}{
+			neoxtest.NewPath([]neo4j.Node{a, b, c}, []neo4j.Relationship{ab, cb}),
+		})}
+		p, ok := record.GetPath("p")
+		if !ok {
+			t.Fatalf("Record.GetPath() ok = %v, want %v", ok, true)
+		}
+		if _, ok := record.GetPath("q"); ok {
+			t.Errorf("Record.GetPath() ok = %v, want %v", ok, false)
+		}
+
+		var people []*person
+		if err := p.NodesTo(&people); err != nil {
+			t.Fatalf("Path.NodesTo() error = %v", err)
+		}
+		if len(people) != 3 || people[0].Name != "Alice" || people[2].ID != "c" {
+			t.Errorf("Path.NodesTo() got = %+v", people)
+		}
+
+		var friendships []friendship
+		if err := p.RelationshipsTo(&friendships); err != nil {
+			t.Fatalf("Path.RelationshipsTo() error = %v", err)
+		}
+		if len(friendships) != 2 || friendships[0].Since != 2019 || friendships[1].Since != 2021 {
+			t.Errorf("Path.RelationshipsTo() got = %+v", friendships)
+		}
+
+		var (
+			start, end person
+			rel        friendship
+		)
+		if err := p.Segments[1].ToStruct(&start, &rel, &end); err != nil {
+			t.Fatalf("Segment.ToStruct() error = %v", err)
+		}
+		if start.Name != "Bob" || end.Name != "Carol" || rel.Since != 2021 {
+			t.Errorf("Segment.ToStruct() got = %+v, %+v, %+v", start, rel, end)
+		}
+		if err := p.Segments[0].ToStruct(nil, 1, nil); err != neox.ErrInvalidArg {
+			t.Errorf("Segment.ToStruct() error = %v, want %v", err, neox.ErrInvalidArg)
+		}
+		var unmapped struct {
+			Name string `neox:"unknown"`
+		}
+		if err := p.Segments[0].ToStruct(nil, nil, &unmapped); err == nil || !strings.Contains(err.Error(), "end of the segment") {
+			t.Errorf("Segment.ToStruct() error = %v, want a failure to map the end", err)
+		}
+		if err := p.NodesTo(people); err == nil {
+			t.Errorf("Path.NodesTo() error = %v, want an error", err)
+		}
+	})
+}
+
+// brokenPath is a neo4j.Path whose relationships may not connect its nodes
+type brokenPath struct {
+	nodes []neo4j.Node
+	rels  []neo4j.Relationship
+}
+
+func (p *brokenPath) Nodes() []neo4j.Node                 { return p.nodes }
+func (p *brokenPath) Relationships() []neo4j.Relationship { return p.rels }
@@ -1,6 +1,8 @@
 package neox
 
 import (
+	"time"
+
 	"github.com/neo4j/neo4j-go-driver/neo4j"
 )
 
@@ -101,3 +103,53 @@ func (r *Record) GetBool(key string) (value bool, ok bool) {
 	}
 	return
 }
+
+// ToMap returns the values of the record by key, normalized recursively to be returned
+// as is in GraphQL or JSON responses. Nodes and relationships are replaced by their
+// properties, paths by the list of the properties of their nodes, temporal values by
+// their RFC 3339 form and points by a map of their srid and coordinates. Integers are
+// kept as int64
+func (r *Record) ToMap() map[string]interface{} {
+	keys, values := r.Keys(), r.Values()
+	m := make(map[string]interface{}, len(keys))
+	for i, k := range keys {
+		if i < len(values) {
+			m[k] = normalize(values[i])
+		}
+	}
+	return m
+}
+
+func normalize(v interface{}) interface{} {
+	switch v := v.(type) {
+	case neo4j.Node:
+		return normalizeMap(v.Props())
+	case neo4j.Relationship:
+		return normalizeMap(v.Props())
+	case neo4j.Path:
+		nodes := make([]interface{}, len(v.Nodes()))
+		for i, n := range v.Nodes() {
+			nodes[i] = normalizeMap(n.Props())
+		}
+		return nodes
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = normalize(item)
+		}
+		return list
+	case map[string]interface{}:
+		return normalizeMap(v)
+	case time.Time, neo4j.Date, neo4j.LocalTime, neo4j.OffsetTime, neo4j.LocalDateTime, neo4j.Duration, *neo4j.Point:
+		return ToJSONValue(v)
+	}
+	return v
+}
+
+func normalizeMap(m map[string]interface{}) map[string]interface{} {
+	normalized := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		normalized[k] = normalize(v)
+	}
+	return normalized
+}
@@ -1,7 +1,9 @@
 package neox
 
 import (
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 
@@ -440,6 +442,77 @@ func TestRecord_GetBool(t *testing.T) {
 	}
 }
 
+func TestRecord_ToMap(t *testing.T) {
+	t.Parallel()
+
+	alice := &graphNode{id: 1, labels: []string{"Person"}, props: map[string]interface{}{
+		"name": "Alice",
+		"born": neo4j.DateOf(time.Date(1982, 3, 14, 0, 0, 0, 0, time.UTC)),
+	}}
+	bob := &graphNode{id: 2, labels: []string{"Person"}, props: map[string]interface{}{"name": "Bob"}}
+	knows := &graphRelationship{id: 7, startID: 1, endID: 2, relType: "KNOWS", props: map[string]interface{}{"since": int64(2019)}}
+
+	tests := []struct {
+		name   string
+		keys   []string
+		values []interface{}
+		want   map[string]interface{}
+	}{
+		{
+			name:   "Should keep scalar values",
+			keys:   []string{"name", "age", "score", "active", "missing"},
+			values: []interface{}{"Alice", int64(42), 1.5, true, nil},
+			want:   map[string]interface{}{"name": "Alice", "age": int64(42), "score": 1.5, "active": true, "missing": nil},
+		},
+		{
+			name:   "Should replace nodes and relationships by their properties",
+			keys:   []string{"user", "knows"},
+			values: []interface{}{alice, knows},
+			want: map[string]interface{}{
+				"user":  map[string]interface{}{"name": "Alice", "born": "1982-03-14"},
+				"knows": map[string]interface{}{"since": int64(2019)},
+			},
+		},
+		{
+			name:   "Should replace paths by the properties of their nodes",
+			keys:   []string{"path"},
+			values: []interface{}{&graphPath{nodes: []neo4j.Node{alice, bob}, rels: []neo4j.Relationship{knows}}},
+			want: map[string]interface{}{"path": []interface{}{
+				map[string]interface{}{"name": "Alice", "born": "1982-03-14"},
+				map[string]interface{}{"name": "Bob"},
+			}},
+		},
+		{
+			name: "Should normalize nested projections",
+			keys: []string{"user"},
+			values: []interface{}{map[string]interface{}{
+				"name":    "Alice",
+				"friends": []interface{}{bob},
+				"seen":    time.Date(2021, 6, 1, 10, 30, 0, 0, time.UTC),
+				"local":   neo4j.LocalDateTimeOf(time.Date(2021, 6, 1, 10, 30, 0, 0, time.UTC)),
+			}},
+			want: map[string]interface{}{"user": map[string]interface{}{
+				"name":    "Alice",
+				"friends": []interface{}{map[string]interface{}{"name": "Bob"}},
+				"seen":    "2021-06-01T10:30:00Z",
+				"local":   "2021-06-01T10:30:00",
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(mrec)
+			m.On("Keys").Return(tt.keys)
+			m.On("Values").Return(tt.values)
+
+			r := &Record{Record: m}
+			if got := r.ToMap(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Record.ToMap() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 type mrec struct {
 	mock.Mock
 	neo4j.Record
@@ -454,3 +527,13 @@ func (m *mrec) Get(key string) (interface{}, bool) {
 	args := m.Called(key)
 	return args.Get(0), args.Bool(1)
 }
+
+func (m *mrec) Keys() []string {
+	args := m.Called()
+	return args.Get(0).([]string)
+}
+
+func (m *mrec) Values() []interface{} {
+	args := m.Called()
+	return args.Get(0).([]interface{})
+}
@@ -0,0 +1,267 @@
+package neox
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+// Load loads the relation fields of v, those tagged with the type and direction of a
+// relationship, from the node with the key of v. Related nodes have their own relation
+// fields loaded in turn, down to the provided depth, a depth of 1 loading the nodes
+// directly related to v. Fields hold either the related nodes
+//
+//	type User struct {
+//		_       struct{} `neox:"label=User,key=id"`
+//		ID      string   `db:"id"`
+//		Friends []User   `neox:"rel=FRIENDS_WITH,dir=out"`
+//		Manager *User    `neox:"rel=MANAGES,dir=in"`
+//	}
+//
+// or relationship structs carrying the properties of the relationships, with a field
+// tagged neox:"node" holding the related node
+//
+//	type Friendship struct {
+//		Since  int64 `db:"since"`
+//		Friend User  `neox:"node"`
+//	}
+//
+// The direction is one of out, the default, in or both. Relations of nodes that do not
+// declare a key are not loaded. All levels are read within a single transaction, with a
+// statement for each relation of each level
+func (r *Repository[T]) Load(ctx context.Context, v *T, depth int) error {
+	if depth <= 0 {
+		return nil
+	}
+	_, err := r.read(ctx, func(tx *Transaction) (interface{}, error) {
+		level := map[*entity][]reflect.Value{r.entity: {reflect.ValueOf(v).Elem()}}
+		for d := 0; d < depth && len(level) > 0; d++ {
+			next := make(map[*entity][]reflect.Value)
+			for e, nodes := range level {
+				if e.key < 0 {
+					continue
+				}
+				for _, rel := range e.rels {
+					if err := loadRelation(tx, e, rel, nodes, next); err != nil {
+						return nil, err
+					}
+				}
+			}
+			level = next
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// SaveRelationships merges the nodes held by the relation fields of v, setting their
+// properties, and merges the relationships from the node with the key of v to them, setting
+// the properties of relationship structs. Related nodes must declare a key, those with a zero
+// key are skipped. Relationships missing from v are left as is, and the relation fields of
+// related nodes are not saved
+func (r *Repository[T]) SaveRelationships(ctx context.Context, v *T) error {
+	value := reflect.ValueOf(v).Elem()
+	key := r.keyOf(v)
+
+	type batch struct {
+		cypher string
+		rows   []interface{}
+	}
+	var batches []batch
+	for _, rel := range r.entity.rels {
+		if rel.node.key < 0 {
+			return fmt.Errorf("neox: %s can not be merged without a key", rel.node.typ)
+		}
+		var rows []interface{}
+		for _, elem := range rel.elements(value.Field(rel.index)) {
+			node := rel.nodeOf(elem)
+			nodeKey := node.Field(rel.node.props[rel.node.key].index)
+			if nodeKey.IsZero() {
+				continue
+			}
+			props := map[string]interface{}{}
+			if rel.props != nil {
+				props = rel.props.properties(reflect.Indirect(elem))
+			}
+			rows = append(rows, map[string]interface{}{
+				"key":   nodeKey.Interface(),
+				"props": rel.node.properties(node),
+				"rel":   props,
+			})
+		}
+		if len(rows) > 0 {
+			batches = append(batches, batch{cypher: saveStatement(r.entity, rel), rows: rows})
+		}
+	}
+	if len(batches) == 0 {
+		return nil
+	}
+
+	_, err := r.write(ctx, func(tx *Transaction) (interface{}, error) {
+		for _, b := range batches {
+			res, err := tx.Runx(b.cypher, Args{"key": key, "rows": b.rows})
+			if err != nil {
+				return nil, err
+			}
+			if _, err := res.Consume(); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// loadRelation reads the nodes related to the provided nodes of e, sets them on the
+// relation field of each node and adds them to the next level
+func loadRelation(tx *Transaction, e *entity, rel relation, nodes []reflect.Value, next map[*entity][]reflect.Value) error {
+	var (
+		keys    []interface{}
+		byIndex [][]reflect.Value
+		indexOf = make(map[interface{}]int)
+	)
+	for _, node := range nodes {
+		key := node.Field(e.props[e.key].index).Interface()
+		i, ok := indexOf[key]
+		if !ok {
+			i = len(keys)
+			indexOf[key] = i
+			keys = append(keys, key)
+			byIndex = append(byIndex, nil)
+		}
+		byIndex[i] = append(byIndex[i], node)
+	}
+
+	res, err := tx.Runx(loadStatement(e, rel), Args{"keys": keys})
+	if err != nil {
+		return err
+	}
+	related := make([][]reflect.Value, len(keys))
+	for res.Next() {
+		record := res.Record()
+		i, _ := record.GetByIndex(0).(int64)
+		relationship, _ := record.GetByIndex(1).(neo4j.Relationship)
+		node, _ := record.GetByIndex(2).(neo4j.Node)
+		if int(i) >= len(related) || relationship == nil || node == nil {
+			return fmt.Errorf("neox: unexpected record loading %s", rel.name)
+		}
+		related[i] = append(related[i], rel.element(relationship, node))
+	}
+	if err := res.Err(); err != nil {
+		return err
+	}
+
+	for i, parents := range byIndex {
+		for _, parent := range parents {
+			field := parent.Field(rel.index)
+			switch {
+			case rel.many:
+				s := reflect.MakeSlice(field.Type(), len(related[i]), len(related[i]))
+				for j, elem := range related[i] {
+					s.Index(j).Set(elem)
+				}
+				field.Set(s)
+			case len(related[i]) > 0:
+				field.Set(related[i][0])
+			default:
+				field.Set(reflect.Zero(field.Type()))
+			}
+			for _, elem := range rel.elements(field) {
+				next[rel.node] = append(next[rel.node], rel.nodeOf(elem))
+			}
+		}
+	}
+	return nil
+}
+
+// loadStatement returns the statement reading the nodes related to the nodes of e
+// with the provided keys, along with the index of the key they are related to
+func loadStatement(e *entity, rel relation) string {
+	match := "(n" + labelOf(e) + " {" + e.keyProp() + ": $keys[i]})" + rel.arrow() + "(m" + labelOf(rel.node) + ")"
+	cypher := "unwind range(0, size($keys) - 1) as i match " + match + " return i, r, m"
+	if rel.node.key >= 0 {
+		cypher += " order by i, m." + rel.node.keyProp()
+	}
+	return cypher
+}
+
+// saveStatement returns the statement merging rows of related nodes and relationships
+func saveStatement(e *entity, rel relation) string {
+	return "match (n" + labelOf(e) + " {" + e.keyProp() + ": $key}) " +
+		"unwind $rows as row " +
+		"merge (m" + labelOf(rel.node) + " {" + rel.node.keyProp() + ": row.key}) set m += row.props " +
+		"merge (n)" + rel.arrow() + "(m) set r += row.rel"
+}
+
+func labelOf(e *entity) string {
+	if e.label == "" {
+		return ""
+	}
+	return ":" + e.label
+}
+
+// arrow returns the relationship pattern from n to m
+func (rel relation) arrow() string {
+	switch rel.dir {
+	case "in":
+		return "<-[r:" + rel.relType + "]-"
+	case "both":
+		return "-[r:" + rel.relType + "]-"
+	}
+	return "-[r:" + rel.relType + "]->"
+}
+
+// element maps a relationship and the related node to a value of the element type of the field
+func (rel relation) element(relationship neo4j.Relationship, n neo4j.Node) reflect.Value {
+	node := reflect.New(rel.node.typ)
+	rel.node.assign(node.Elem(), n.Props())
+
+	elem := node
+	if rel.props != nil {
+		elem = reflect.New(rel.props.typ)
+		rel.props.assign(elem.Elem(), relationship.Props())
+		field := elem.Elem().Field(rel.props.node)
+		if rel.props.nodePtr {
+			field.Set(node)
+		} else {
+			field.Set(node.Elem())
+		}
+	}
+	if rel.ptr {
+		return elem
+	}
+	return elem.Elem()
+}
+
+// elements returns the non nil elements held by a relation field
+func (rel relation) elements(field reflect.Value) []reflect.Value {
+	var elems []reflect.Value
+	add := func(v reflect.Value) {
+		if rel.ptr && v.IsNil() {
+			return
+		}
+		if rel.props != nil && rel.props.nodePtr && reflect.Indirect(v).Field(rel.props.node).IsNil() {
+			return
+		}
+		elems = append(elems, v)
+	}
+	if rel.many {
+		for i := 0; i < field.Len(); i++ {
+			add(field.Index(i))
+		}
+	} else {
+		add(field)
+	}
+	return elems
+}
+
+// nodeOf returns the addressable node struct held by an element
+func (rel relation) nodeOf(elem reflect.Value) reflect.Value {
+	v := reflect.Indirect(elem)
+	if rel.props != nil {
+		v = reflect.Indirect(v.Field(rel.props.node))
+	}
+	return v
+}
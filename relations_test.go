@@ -0,0 +1,168 @@
+package neox_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+	"github.com/syllabix/neox"
+	"github.com/syllabix/neox/neoxtest"
+)
+
+type person struct {
+	_           struct{}     `neox:"label=Person,key=id"`
+	ID          string       `db:"id"`
+	Name        string       `db:"name"`
+	Friends     []person     `neox:"rel=FRIENDS_WITH,dir=out"`
+	Manager     *person      `neox:"rel=MANAGES,dir=in"`
+	Friendships []friendship `neox:"rel=FRIENDS_WITH"`
+}
+
+type friendship struct {
+	Since  int64   `db:"since"`
+	Friend *person `neox:"node"`
+}
+
+func personNode(id int64, key, name string) neo4j.Node {
+	return neoxtest.NewNode(id, []string{"Person"}, map[string]interface{}{"id": key, "name": name})
+}
+
+func TestRepository_Load(t *testing.T) {
+	t.Parallel()
+
+	const (
+		friends = "unwind range(0, size($keys) - 1) as i match (n:Person {id: $keys[i]})-[r:FRIENDS_WITH]->(m:Person) return i, r, m order by i, m.id"
+		manages = "unwind range(0, size($keys) - 1) as i match (n:Person {id: $keys[i]})<-[r:MANAGES]-(m:Person) return i, r, m order by i, m.id"
+	)
+	keys := []string{"i", "r", "m"}
+
+	fake := neoxtest.NewDriver()
+	fake.On(friends).
+		WithArgs(neox.Args{"keys": []interface{}{"a"}}).
+		Return(keys,
+			[]interface{}{int64(0), neoxtest.NewRelationship(10, 1, 2, "FRIENDS_WITH", map[string]interface{}{"since": int64(2019)}), personNode(2, "b", "Jordan Ames")},
+			[]interface{}{int64(0), neoxtest.NewRelationship(11, 1, 3, "FRIENDS_WITH", map[string]interface{}{"since": int64(2020)}), personNode(3, "c", "Ana Lima")},
+		)
+	fake.On(manages).
+		WithArgs(neox.Args{"keys": []interface{}{"a"}}).
+		Return(keys, []interface{}{int64(0), neoxtest.NewRelationship(12, 4, 1, "MANAGES", nil), personNode(4, "m", "Maria Rossi")})
+	fake.On(friends).
+		WithArgs(neox.Args{"keys": []interface{}{"b", "c", "m"}}).
+		Return(keys, []interface{}{int64(0), neoxtest.NewRelationship(13, 2, 5, "FRIENDS_WITH", nil), personNode(5, "d", "Dev Patel")})
+	fake.On(manages).
+		WithArgs(neox.Args{"keys": []interface{}{"b", "c", "m"}}).
+		Return(keys)
+
+	people, err := neox.NewRepository[person](fake.Neox())
+	if err != nil {
+		t.Fatalf("NewRepository() error = %v", err)
+	}
+
+	p := person{ID: "a", Name: "Yolanda Erasmus"}
+	if err := people.Load(context.Background(), &p, 2); err != nil {
+		t.Fatalf("Repository.Load() error = %v", err)
+	}
+
+	if len(p.Friends) != 2 || p.Friends[0].Name != "Jordan Ames" || p.Friends[1].ID != "c" {
+		t.Fatalf("Friends = %+v", p.Friends)
+	}
+	if len(p.Friends[0].Friends) != 1 || p.Friends[0].Friends[0].Name != "Dev Patel" || len(p.Friends[1].Friends) != 0 {
+		t.Errorf("Friends of friends = %+v, %+v", p.Friends[0].Friends, p.Friends[1].Friends)
+	}
+	if p.Friends[0].Friends[0].Friends != nil {
+		t.Errorf("Load() went past the depth limit: %+v", p.Friends[0].Friends[0].Friends)
+	}
+	if p.Manager == nil || p.Manager.Name != "Maria Rossi" {
+		t.Errorf("Manager = %+v", p.Manager)
+	}
+	if len(p.Friendships) != 2 || p.Friendships[1].Since != 2020 || p.Friendships[1].Friend.Name != "Ana Lima" {
+		t.Errorf("Friendships = %+v", p.Friendships)
+	}
+	if len(p.Friendships[0].Friend.Friends) != 1 {
+		t.Errorf("Friendships[0].Friend.Friends = %+v", p.Friendships[0].Friend.Friends)
+	}
+	if calls := fake.Calls(); len(calls) != 6 {
+		t.Errorf("Calls() = %d, want 6", len(calls))
+	}
+}
+
+func TestRepository_SaveRelationships(t *testing.T) {
+	t.Parallel()
+
+	fake := neoxtest.NewDriver()
+	fake.On("match (n:Person {id: $key}) unwind $rows as row merge (m:Person {id: row.key}) set m += row.props merge (n)<-[r:MANAGES]-(m) set r += row.rel").
+		WithArgs(neox.Args{"key": "a", "rows": []interface{}{
+			map[string]interface{}{"key": "m", "props": map[string]interface{}{"id": "m", "name": "Maria Rossi"}, "rel": map[string]interface{}{}},
+		}}).
+		Once()
+	fake.On("match (n:Person {id: $key}) unwind $rows as row merge (m:Person {id: row.key}) set m += row.props merge (n)-[r:FRIENDS_WITH]->(m) set r += row.rel").
+		WithArgs(neox.Args{"key": "a", "rows": []interface{}{
+			map[string]interface{}{"key": "b", "props": map[string]interface{}{"id": "b", "name": "Jordan Ames"}, "rel": map[string]interface{}{"since": int64(2019)}},
+		}}).
+		Once()
+
+	people, _ := neox.NewRepository[person](fake.Neox())
+	p := person{
+		ID:      "a",
+		Friends: []person{{Name: "no key"}},
+		Manager: &person{ID: "m", Name: "Maria Rossi"},
+		Friendships: []friendship{
+			{Since: 2019, Friend: &person{ID: "b", Name: "Jordan Ames"}},
+			{Since: 2021},
+		},
+	}
+	if err := people.SaveRelationships(context.Background(), &p); err != nil {
+		t.Fatalf("Repository.SaveRelationships() error = %v", err)
+	}
+	if unmet := fake.Unmet(); len(unmet) != 0 {
+		t.Errorf("Unmet() = %v", unmet)
+	}
+	if calls := fake.Calls(); len(calls) != 2 || !calls[0].Transaction {
+		t.Errorf("Calls() = %+v, want 2 statements in a transaction", calls)
+	}
+}
+
+func TestResult_ToStruct_Graph(t *testing.T) {
+	t.Parallel()
+
+	type team struct {
+		Name    string      `db:"name"`
+		Members []person    `db:"members"`
+		Lead    *person     `db:"lead"`
+		Tags    []string    `db:"tags"`
+		Extra   interface{} `db:"extra"`
+		Size    int         `db:"size"`
+	}
+
+	fake := neoxtest.NewDriver()
+	fake.On("match (t:Team) return t.name as name, members, lead, tags, extra, size").
+		Return([]string{"name", "members", "lead", "tags", "extra", "size"}, []interface{}{
+			"core",
+			[]interface{}{personNode(1, "a", "Yolanda Erasmus"), personNode(2, "b", "Jordan Ames")},
+			personNode(1, "a", "Yolanda Erasmus"),
+			[]interface{}{"go", "graphs"},
+			int64(7),
+			int64(3),
+		})
+
+	session, _ := fake.Neox().Sessionx(neo4j.AccessModeRead)
+	defer session.Close()
+	res, err := session.Runx("match (t:Team) return t.name as name, members, lead, tags, extra, size", nil)
+	if err != nil || !res.Next() {
+		t.Fatalf("Session.Runx() error = %v", err)
+	}
+
+	var got team
+	if err := res.ToStruct(&got); err != nil {
+		t.Fatalf("Result.ToStruct() error = %v", err)
+	}
+	if got.Name != "core" || len(got.Members) != 2 || got.Members[1].Name != "Jordan Ames" {
+		t.Errorf("Result.ToStruct() got = %+v", got)
+	}
+	if got.Lead == nil || got.Lead.ID != "a" || len(got.Tags) != 2 || got.Extra != int64(7) {
+		t.Errorf("Result.ToStruct() got = %+v", got)
+	}
+	if got.Size != 0 {
+		t.Errorf("Result.ToStruct() size = %d, want values of another kind to be skipped", got.Size)
+	}
+}
@@ -0,0 +1,233 @@
+package neox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+// ErrNotFound is returned by a Repository when no node has the provided key
+var ErrNotFound = errors.New("node not found")
+
+// Repository offers create, read, update and delete operations for the nodes of a label,
+// mapped to and from the struct T. The label and the property identifying a node are declared
+// with a marker field tagged with neox, other fields are mapped to properties by their db tags
+//
+//	type User struct {
+//		_     struct{} `neox:"label=User,key=id"`
+//		ID    string   `db:"id"`
+//		Name  string   `db:"name"`
+//		Age   int64    `db:"age"`
+//	}
+//
+//	users, err := neox.NewRepository[User](driver)
+//
+// Nodes are read with Result.ToStruct, so fields must have the type the driver returns for
+// their property, ie: int64 rather than int. Operations run in transaction functions, with
+// the retry logic of the driver in place. A Repository is safe for concurrent use
+type Repository[T any] struct {
+	driver *Driver
+	entity *entity
+
+	create string
+	get    string
+	update string
+	merge  string
+	delete string
+	list   string
+}
+
+// NewRepository returns a repository for the nodes mapped to T, failing when T is not
+// a struct declaring a label and a key that is one of its mapped fields
+func NewRepository[T any](driver *Driver) (*Repository[T], error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("neox: repository type %s is not a struct", t)
+	}
+	e, err := entityOf(t)
+	if err != nil {
+		return nil, err
+	}
+	if e.label == "" || e.key < 0 {
+		return nil, fmt.Errorf("neox: %s does not declare a label and a key with a %s tag", t, graphtag)
+	}
+
+	projection := make([]string, len(e.props))
+	for i, p := range e.props {
+		projection[i] = "n." + p.prop + " as " + p.prop
+	}
+	var (
+		keyProp = e.keyProp()
+		node    = e.pattern("n")
+		match   = "(n:" + e.label + " {" + keyProp + ": $key})"
+		ret     = strings.Join(projection, ", ")
+	)
+
+	return &Repository[T]{
+		driver: driver,
+		entity: e,
+		create: "create " + node + " set n = $props",
+		get:    "match " + match + " return " + ret + " limit 1",
+		update: "match " + match + " set n += $props return n." + keyProp,
+		merge:  "merge " + match + " set n += $props",
+		delete: "match " + match + " detach delete n",
+		list:   "match " + node + " return " + ret + " order by n." + keyProp + " skip $skip limit $limit",
+	}, nil
+}
+
+// Create creates a node with the properties of v
+func (r *Repository[T]) Create(ctx context.Context, v *T) error {
+	_, err := r.write(ctx, func(tx *Transaction) (interface{}, error) {
+		res, err := tx.Runx(r.create, Args{"props": r.props(v)})
+		if err != nil {
+			return nil, err
+		}
+		_, err = res.Consume()
+		return nil, err
+	})
+	return err
+}
+
+// Get returns the node with the provided key, or ErrNotFound
+func (r *Repository[T]) Get(ctx context.Context, key interface{}) (*T, error) {
+	found, err := r.read(ctx, func(tx *Transaction) (interface{}, error) {
+		res, err := tx.Runx(r.get, Args{"key": key})
+		if err != nil {
+			return nil, err
+		}
+		list, err := r.collect(res)
+		if err != nil || len(list) == 0 {
+			return nil, err
+		}
+		return &list[0], nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, ErrNotFound
+	}
+	return found.(*T), nil
+}
+
+// Update sets the properties of the node with the key of v to the values of v,
+// returning ErrNotFound when there is no such node. Properties of the node that v
+// does not map are left as is
+func (r *Repository[T]) Update(ctx context.Context, v *T) error {
+	found, err := r.write(ctx, func(tx *Transaction) (interface{}, error) {
+		res, err := tx.Runx(r.update, Args{"key": r.keyOf(v), "props": r.props(v)})
+		if err != nil {
+			return nil, err
+		}
+		found := res.Next()
+		if err := res.Err(); err != nil {
+			return nil, err
+		}
+		_, err = res.Consume()
+		return found, err
+	})
+	if err != nil {
+		return err
+	}
+	if !found.(bool) {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Merge creates the node with the key of v when it does not exist, and sets its
+// properties to the values of v
+func (r *Repository[T]) Merge(ctx context.Context, v *T) error {
+	_, err := r.write(ctx, func(tx *Transaction) (interface{}, error) {
+		res, err := tx.Runx(r.merge, Args{"key": r.keyOf(v), "props": r.props(v)})
+		if err != nil {
+			return nil, err
+		}
+		_, err = res.Consume()
+		return nil, err
+	})
+	return err
+}
+
+// Delete deletes the node with the provided key along with its relationships,
+// returning ErrNotFound when there is no such node
+func (r *Repository[T]) Delete(ctx context.Context, key interface{}) error {
+	deleted, err := r.write(ctx, func(tx *Transaction) (interface{}, error) {
+		res, err := tx.Runx(r.delete, Args{"key": key})
+		if err != nil {
+			return nil, err
+		}
+		summary, err := res.Consume()
+		if err != nil {
+			return nil, err
+		}
+		return summary.Counters().NodesDeleted(), nil
+	})
+	if err != nil {
+		return err
+	}
+	if deleted.(int) == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// List returns a page of nodes ordered by key, skipping the first skip nodes and
+// returning at most limit nodes
+func (r *Repository[T]) List(ctx context.Context, skip, limit int) ([]T, error) {
+	list, err := r.read(ctx, func(tx *Transaction) (interface{}, error) {
+		res, err := tx.Runx(r.list, Args{"skip": int64(skip), "limit": int64(limit)})
+		if err != nil {
+			return nil, err
+		}
+		return r.collect(res)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list.([]T), nil
+}
+
+func (r *Repository[T]) read(ctx context.Context, work TransactionWork) (interface{}, error) {
+	session, err := r.driver.Sessionx(neo4j.AccessModeRead)
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+	return session.ReadTransactionxContext(ctx, work)
+}
+
+func (r *Repository[T]) write(ctx context.Context, work TransactionWork) (interface{}, error) {
+	session, err := r.driver.Sessionx(neo4j.AccessModeWrite)
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+	return session.WriteTransactionxContext(ctx, work)
+}
+
+// collect maps every record of the result to a T
+func (r *Repository[T]) collect(res *Result) ([]T, error) {
+	list := []T{}
+	for res.Next() {
+		var v T
+		if err := res.ToStruct(&v); err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+	}
+	return list, res.Err()
+}
+
+// props returns the mapped fields of v by property key
+func (r *Repository[T]) props(v *T) map[string]interface{} {
+	return r.entity.properties(reflect.ValueOf(v).Elem())
+}
+
+func (r *Repository[T]) keyOf(v *T) interface{} {
+	return reflect.ValueOf(v).Elem().Field(r.entity.props[r.entity.key].index).Interface()
+}
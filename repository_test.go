@@ -0,0 +1,142 @@
+package neox_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/syllabix/neox"
+	"github.com/syllabix/neox/neoxtest"
+)
+
+type user struct {
+	_         struct{} `neox:"label=User,key=id"`
+	ID        string   `db:"id"`
+	Name      string   `db:"name"`
+	Age       int64    `db:"age"`
+	FirstName string   `db:"first name"`
+	password  string
+}
+
+func TestNewRepository(t *testing.T) {
+	t.Parallel()
+
+	driver := neoxtest.NewDriver().Neox()
+
+	type unlabeled struct {
+		ID string `db:"id"`
+	}
+	type unkeyed struct {
+		_  struct{} `neox:"label=User,key=email"`
+		ID string   `db:"id"`
+	}
+	type invalid struct {
+		_  struct{} `neox:"label=User\\u0060,key=id"`
+		ID string   `db:"id"`
+	}
+
+	tests := []struct {
+		name string
+		new  func() error
+	}{
+		{"Should require a struct", func() error { _, err := neox.NewRepository[string](driver); return err }},
+		{"Should require a label", func() error { _, err := neox.NewRepository[unlabeled](driver); return err }},
+		{"Should require a mapped key", func() error { _, err := neox.NewRepository[unkeyed](driver); return err }},
+		{"Should validate the label", func() error { _, err := neox.NewRepository[invalid](driver); return err }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.new(); err == nil {
+				t.Errorf("NewRepository() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestRepository(t *testing.T) {
+	t.Parallel()
+
+	const projection = "return n.id as id, n.name as name, n.age as age, n.`first name` as `first name`"
+	keys := []string{"id", "name", "age", "first name"}
+	yolanda := user{ID: "u1", Name: "Yolanda Erasmus", Age: 17, FirstName: "Yolanda", password: "secret"}
+	props := map[string]interface{}{"id": "u1", "name": "Yolanda Erasmus", "age": int64(17), "first name": "Yolanda"}
+
+	fake := neoxtest.NewDriver()
+	fake.On("create (n:User) set n = $props").
+		WithArgs(neox.Args{"props": props}).
+		WithSummary(neoxtest.Summary{Counters: neoxtest.Counters{NodesCreated: 1}})
+	fake.On("match (n:User {id: $key}) "+projection+" limit 1").
+		WithArgs(neox.Args{"key": "u1"}).
+		Return(keys, []interface{}{"u1", "Yolanda Erasmus", int64(17), "Yolanda"})
+	fake.On("match (n:User {id: $key}) " + projection + " limit 1").
+		Return(keys)
+	fake.On("match (n:User {id: $key}) set n += $props return n.id").
+		WithArgs(neox.Args{"key": "u1", "props": props}).
+		Return([]string{"n.id"}, []interface{}{"u1"})
+	fake.On("match (n:User {id: $key}) set n += $props return n.id").
+		Return([]string{"n.id"})
+	fake.On("merge (n:User {id: $key}) set n += $props").
+		WithArgs(neox.Args{"key": "u1", "props": props})
+	fake.On("match (n:User {id: $key}) detach delete n").
+		WithArgs(neox.Args{"key": "u1"}).
+		WithSummary(neoxtest.Summary{Counters: neoxtest.Counters{NodesDeleted: 1}})
+	fake.On("match (n:User {id: $key}) detach delete n")
+	fake.On("match (n:User) "+projection+" order by n.id skip $skip limit $limit").
+		WithArgs(neox.Args{"skip": int64(1), "limit": int64(2)}).
+		Return(keys,
+			[]interface{}{"u2", "Jordan Ames", int64(34), "Jordan"},
+			[]interface{}{"u3", "Ana Lima", int64(28), "Ana"},
+		)
+
+	users, err := neox.NewRepository[user](fake.Neox())
+	if err != nil {
+		t.Fatalf("NewRepository() error = %v", err)
+	}
+	ctx := context.Background()
+
+	if err := users.Create(ctx, &yolanda); err != nil {
+		t.Errorf("Repository.Create() error = %v", err)
+	}
+
+	got, err := users.Get(ctx, "u1")
+	yolanda.password = ""
+	if err != nil || !reflect.DeepEqual(*got, yolanda) {
+		t.Errorf("Repository.Get() = %+v, %v, want %+v", got, err, yolanda)
+	}
+	if _, err := users.Get(ctx, "u9"); !errors.Is(err, neox.ErrNotFound) {
+		t.Errorf("Repository.Get() error = %v, want %v", err, neox.ErrNotFound)
+	}
+
+	if err := users.Update(ctx, &yolanda); err != nil {
+		t.Errorf("Repository.Update() error = %v", err)
+	}
+	if err := users.Update(ctx, &user{ID: "u9"}); !errors.Is(err, neox.ErrNotFound) {
+		t.Errorf("Repository.Update() error = %v, want %v", err, neox.ErrNotFound)
+	}
+
+	if err := users.Merge(ctx, &yolanda); err != nil {
+		t.Errorf("Repository.Merge() error = %v", err)
+	}
+
+	if err := users.Delete(ctx, "u1"); err != nil {
+		t.Errorf("Repository.Delete() error = %v", err)
+	}
+	if err := users.Delete(ctx, "u9"); !errors.Is(err, neox.ErrNotFound) {
+		t.Errorf("Repository.Delete() error = %v, want %v", err, neox.ErrNotFound)
+	}
+
+	page, err := users.List(ctx, 1, 2)
+	if err != nil || len(page) != 2 || page[0].Name != "Jordan Ames" || page[1].Age != 28 {
+		t.Errorf("Repository.List() = %+v, %v", page, err)
+	}
+
+	for _, call := range fake.Calls() {
+		if !call.Transaction {
+			t.Errorf("%q did not run in a transaction", call.Cypher)
+		}
+	}
+	if fake.OpenSessions() != 0 {
+		t.Errorf("OpenSessions() = %d, want 0", fake.OpenSessions())
+	}
+}
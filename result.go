@@ -16,7 +16,6 @@ var (
 
 type rprops struct {
 	index int
-	kind  reflect.Kind
 }
 
 type rcache map[string]rprops
@@ -28,6 +27,34 @@ type Result struct {
 	neo4j.Result
 	m   rcache
 	set bool
+	run *run
+}
+
+// Next returns true only if there is a record to be processed.
+// Once the result is exhausted the hooks of the driver are notified
+func (r *Result) Next() bool {
+	if r.Result.Next() {
+		return true
+	}
+	if r.run != nil {
+		err := r.Err()
+		var summary neo4j.ResultSummary
+		if err == nil {
+			summary, err = r.Result.Summary()
+		}
+		r.run.done(summary, ClassifyError(err))
+	}
+	return false
+}
+
+// Consume consumes the entire result and returns the summary information
+// about the statement execution. Failures reported by neo4j are returned
+// as a classified neox.Error
+func (r *Result) Consume() (neo4j.ResultSummary, error) {
+	summary, err := r.Result.Consume()
+	err = ClassifyError(err)
+	r.run.done(summary, err)
+	return summary, err
 }
 
 // Recordx returns a neox.Record at the current index in the
@@ -36,11 +63,20 @@ func (r *Result) Recordx() *Record {
 	return &Record{r.Record()}
 }
 
+// Err returns the latest error that caused Next to return false,
+// classified as a neox.Error when it was reported by neo4j
+func (r *Result) Err() error {
+	return ClassifyError(r.Result.Err())
+}
+
 // ToStruct attempts to assign the values of the current result record to fields of
 // the provided struct. The argument must be a pointer to a struct or an ErrInvalidArg will be returned.
 // ToStruct will cache results of reflecting on the provided destination type to improve performance
 // on every subsequent call for an instance of a Result. That said, using varying struct types through the lifetime
-// of a single result instance should be considered unsafe and will yield unstable results
+// of a single result instance should be considered unsafe and will yield unstable results.
+//
+// Values are assigned to fields of the same kind. Nodes and relationships are assigned to struct fields
+// mapping their properties by db tags, and lists to slices, ie: a list of nodes to a []User field
 func (r *Result) ToStruct(dest interface{}) error {
 	if r.Err() != nil {
 		return r.Err()
@@ -62,7 +98,6 @@ func (r *Result) ToStruct(dest interface{}) error {
 			fieldType := e.Type().Field(i)
 			r.m[fieldType.Tag.Get(neotag)] = rprops{
 				index: i,
-				kind:  e.Field(i).Kind(),
 			}
 		}
 		r.set = true
@@ -75,13 +110,7 @@ func (r *Result) ToStruct(dest interface{}) error {
 			continue
 		}
 
-		field := e.Field(cache.index)
-		if field.CanSet() {
-			value := reflect.ValueOf(r)
-			if value.Kind() == cache.kind {
-				field.Set(value)
-			}
-		}
+		setValue(e.Field(cache.index), r)
 	}
 
 	return nil
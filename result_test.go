@@ -218,3 +218,20 @@ func (m *mres) Record() neo4j.Record {
 	record, _ := args.Get(0).(neo4j.Record)
 	return record
 }
+
+func (m *mres) Next() bool {
+	args := m.Called()
+	return args.Bool(0)
+}
+
+func (m *mres) Summary() (neo4j.ResultSummary, error) {
+	args := m.Called()
+	summary, _ := args.Get(0).(neo4j.ResultSummary)
+	return summary, args.Error(1)
+}
+
+func (m *mres) Consume() (neo4j.ResultSummary, error) {
+	args := m.Called()
+	summary, _ := args.Get(0).(neo4j.ResultSummary)
+	return summary, args.Error(1)
+}
@@ -1,23 +1,50 @@
 package neox
 
-import "github.com/neo4j/neo4j-go-driver/neo4j"
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
 
 // Session is a struct that offers access to the standard
 // neo4j driver, but offers extension methods for running cypher
 // queries and handling neo4j results
 type Session struct {
 	neo4j.Session
+	mode   neo4j.AccessMode
+	instr  instrumentation
+	closed int32
+}
+
+// Close closes any open resources and marks this session as unusable
+func (s *Session) Close() error {
+	if atomic.CompareAndSwapInt32(&s.closed, 0, 1) {
+		s.instr.sessionClosed(s.mode)
+	}
+	return ClassifyError(s.Session.Close())
 }
 
 // Runx is an extension method that runs the provided cypher
 // query with the respective args and configurers
-// and returns a neox.Result
+// and returns a neox.Result. Failures reported by neo4j are
+// returned as a classified neox.Error
 func (s *Session) Runx(cypher string, args Args, configurers ...func(*neo4j.TransactionConfig)) (*Result, error) {
+	return s.RunxContext(context.Background(), cypher, args, configurers...)
+}
+
+// RunxContext is like Runx, passing the provided context on to the hooks of the driver.
+// The context is not used to cancel the statement
+func (s *Session) RunxContext(ctx context.Context, cypher string, args Args, configurers ...func(*neo4j.TransactionConfig)) (*Result, error) {
+	run := s.instr.observe(ctx, s.mode, cypher, args)
 	res, err := s.Run(cypher, args, configurers...)
 	if err != nil {
+		err = ClassifyError(err)
+		run.done(nil, err)
 		return nil, err
 	}
 	return &Result{
 		Result: res,
+		run:    run,
 	}, nil
 }
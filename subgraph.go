@@ -0,0 +1,520 @@
+package neox
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+// Subgraph is an in-memory graph collecting the nodes and relationships of results,
+// deduplicated by id and indexed by label and type. Relationships are kept even when
+// their nodes are not collected. A Subgraph is not safe for concurrent use
+//
+//	g := neox.NewSubgraph()
+//	if err := g.Collect(res); err != nil {
+//		return err
+//	}
+//	for _, friend := range g.Neighbors(id, "FRIENDS_WITH") {
+//		...
+//	}
+//	err = g.WriteDOT(os.Stdout)
+type Subgraph struct {
+	nodes map[int64]neo4j.Node
+	rels  map[int64]neo4j.Relationship
+
+	// nodeIDs and relIDs hold the ids in the order they were collected
+	nodeIDs []int64
+	relIDs  []int64
+
+	labels map[string][]int64
+	types  map[string][]int64
+	out    map[int64][]int64
+	in     map[int64][]int64
+}
+
+// NewSubgraph returns an empty subgraph
+func NewSubgraph() *Subgraph {
+	return &Subgraph{
+		nodes:  make(map[int64]neo4j.Node),
+		rels:   make(map[int64]neo4j.Relationship),
+		labels: make(map[string][]int64),
+		types:  make(map[string][]int64),
+		out:    make(map[int64][]int64),
+		in:     make(map[int64][]int64),
+	}
+}
+
+// Add adds the nodes, relationships and paths among the values, looking into lists and
+// maps. Values already collected are ignored, as are values that are not graph values
+func (g *Subgraph) Add(values ...interface{}) {
+	for _, v := range values {
+		switch v := v.(type) {
+		case neo4j.Node:
+			g.addNode(v)
+		case neo4j.Relationship:
+			g.addRelationship(v)
+		case neo4j.Path:
+			for _, n := range v.Nodes() {
+				g.addNode(n)
+			}
+			for _, r := range v.Relationships() {
+				g.addRelationship(r)
+			}
+		case []interface{}:
+			g.Add(v...)
+		case map[string]interface{}:
+			for _, item := range v {
+				g.Add(item)
+			}
+		}
+	}
+}
+
+// Collect adds the values of the remaining records of the result
+func (g *Subgraph) Collect(res *Result) error {
+	for res.Next() {
+		g.Add(res.Record().Values()...)
+	}
+	return res.Err()
+}
+
+func (g *Subgraph) addNode(n neo4j.Node) {
+	if _, ok := g.nodes[n.Id()]; ok {
+		return
+	}
+	g.nodes[n.Id()] = n
+	g.nodeIDs = append(g.nodeIDs, n.Id())
+	for _, label := range n.Labels() {
+		g.labels[label] = append(g.labels[label], n.Id())
+	}
+}
+
+func (g *Subgraph) addRelationship(r neo4j.Relationship) {
+	if _, ok := g.rels[r.Id()]; ok {
+		return
+	}
+	g.rels[r.Id()] = r
+	g.relIDs = append(g.relIDs, r.Id())
+	g.types[r.Type()] = append(g.types[r.Type()], r.Id())
+	g.out[r.StartId()] = append(g.out[r.StartId()], r.Id())
+	g.in[r.EndId()] = append(g.in[r.EndId()], r.Id())
+}
+
+// Node returns the node with the provided id
+func (g *Subgraph) Node(id int64) (neo4j.Node, bool) {
+	n, ok := g.nodes[id]
+	return n, ok
+}
+
+// Relationship returns the relationship with the provided id
+func (g *Subgraph) Relationship(id int64) (neo4j.Relationship, bool) {
+	r, ok := g.rels[id]
+	return r, ok
+}
+
+// Nodes returns the nodes in the order they were collected
+func (g *Subgraph) Nodes() []neo4j.Node {
+	return g.nodesOf(g.nodeIDs)
+}
+
+// Relationships returns the relationships in the order they were collected
+func (g *Subgraph) Relationships() []neo4j.Relationship {
+	return g.relsOf(g.relIDs, nil)
+}
+
+// NodesByLabel returns the nodes with the provided label
+func (g *Subgraph) NodesByLabel(label string) []neo4j.Node {
+	return g.nodesOf(g.labels[label])
+}
+
+// RelationshipsByType returns the relationships of the provided type
+func (g *Subgraph) RelationshipsByType(relType string) []neo4j.Relationship {
+	return g.relsOf(g.types[relType], nil)
+}
+
+// Outgoing returns the relationships starting at the node, of any of the provided
+// types, or of any type when none is provided
+func (g *Subgraph) Outgoing(id int64, types ...string) []neo4j.Relationship {
+	return g.relsOf(g.out[id], types)
+}
+
+// Incoming returns the relationships ending at the node, of any of the provided
+// types, or of any type when none is provided
+func (g *Subgraph) Incoming(id int64, types ...string) []neo4j.Relationship {
+	return g.relsOf(g.in[id], types)
+}
+
+// Neighbors returns the nodes related to the node in either direction by relationships
+// of any of the provided types, or of any type when none is provided
+func (g *Subgraph) Neighbors(id int64, types ...string) []neo4j.Node {
+	var (
+		ids  []int64
+		seen = make(map[int64]bool)
+	)
+	for _, r := range g.relationships(id, types) {
+		other := otherEnd(r, id)
+		if _, ok := g.nodes[other]; ok && !seen[other] {
+			seen[other] = true
+			ids = append(ids, other)
+		}
+	}
+	return g.nodesOf(ids)
+}
+
+// Walk visits the nodes reachable from the start node in breadth first order, following
+// relationships in either direction up to the provided depth, or without limit when it is
+// negative. The start node is visited at depth 0. The walk stops when visit returns false
+func (g *Subgraph) Walk(start int64, depth int, visit func(n neo4j.Node, depth int) bool) {
+	if _, ok := g.nodes[start]; !ok {
+		return
+	}
+	seen := map[int64]bool{start: true}
+	level := []int64{start}
+	for d := 0; len(level) > 0 && (depth < 0 || d <= depth); d++ {
+		var next []int64
+		for _, id := range level {
+			if !visit(g.nodes[id], d) {
+				return
+			}
+			for _, n := range g.Neighbors(id) {
+				if !seen[n.Id()] {
+					seen[n.Id()] = true
+					next = append(next, n.Id())
+				}
+			}
+		}
+		level = next
+	}
+}
+
+// ShortestPath returns a path with the fewest relationships between two nodes, following
+// relationships in either direction, and false when the nodes are not connected
+func (g *Subgraph) ShortestPath(from, to int64) (*Path, bool) {
+	if _, ok := g.nodes[from]; !ok {
+		return nil, false
+	}
+	if _, ok := g.nodes[to]; !ok {
+		return nil, false
+	}
+
+	// via holds the relationship each node was reached through
+	via := map[int64]neo4j.Relationship{from: nil}
+	queue := []int64{from}
+	for len(queue) > 0 && via[to] == nil && from != to {
+		id := queue[0]
+		queue = queue[1:]
+		for _, r := range g.relationships(id, nil) {
+			other := otherEnd(r, id)
+			if _, ok := g.nodes[other]; !ok {
+				continue
+			}
+			if _, ok := via[other]; !ok {
+				via[other] = r
+				queue = append(queue, other)
+			}
+		}
+	}
+	if _, ok := via[to]; !ok {
+		return nil, false
+	}
+
+	p := &graphPath{nodes: []neo4j.Node{g.nodes[to]}}
+	for id := to; id != from; {
+		r := via[id]
+		id = otherEnd(r, id)
+		p.nodes = append([]neo4j.Node{g.nodes[id]}, p.nodes...)
+		p.rels = append([]neo4j.Relationship{r}, p.rels...)
+	}
+	path, err := NewPath(p)
+	return path, err == nil
+}
+
+// relationships returns the relationships of a node in either direction, of any of the
+// provided types. A relationship from the node to itself is returned once
+func (g *Subgraph) relationships(id int64, types []string) []neo4j.Relationship {
+	rels := g.relsOf(g.out[id], types)
+	for _, r := range g.relsOf(g.in[id], types) {
+		if r.StartId() != id {
+			rels = append(rels, r)
+		}
+	}
+	return rels
+}
+
+func (g *Subgraph) nodesOf(ids []int64) []neo4j.Node {
+	nodes := make([]neo4j.Node, len(ids))
+	for i, id := range ids {
+		nodes[i] = g.nodes[id]
+	}
+	return nodes
+}
+
+func (g *Subgraph) relsOf(ids []int64, types []string) []neo4j.Relationship {
+	rels := make([]neo4j.Relationship, 0, len(ids))
+	for _, id := range ids {
+		r := g.rels[id]
+		if len(types) == 0 || contains(types, r.Type()) {
+			rels = append(rels, r)
+		}
+	}
+	return rels
+}
+
+func otherEnd(r neo4j.Relationship, id int64) int64 {
+	if r.StartId() == id {
+		return r.EndId()
+	}
+	return r.StartId()
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalJSON marshals the subgraph as an object holding its nodes and relationships,
+// converted by ToJSONValue
+func (g *Subgraph) MarshalJSON() ([]byte, error) {
+	nodes := make([]interface{}, len(g.nodeIDs))
+	for i, n := range g.Nodes() {
+		nodes[i] = ToJSONValue(n)
+	}
+	rels := make([]interface{}, len(g.relIDs))
+	for i, r := range g.Relationships() {
+		rels[i] = ToJSONValue(r)
+	}
+	return json.Marshal(map[string]interface{}{"nodes": nodes, "relationships": rels})
+}
+
+// WriteJSON writes the subgraph to w as JSON, see MarshalJSON
+func (g *Subgraph) WriteJSON(w io.Writer) error {
+	b, err := g.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
+
+// WriteDOT writes the subgraph to w in the DOT language of Graphviz. Nodes are labeled
+// with their labels and properties, relationships with their type
+func (g *Subgraph) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("digraph {\n")
+	for _, n := range g.Nodes() {
+		lines := []string{":" + strings.Join(n.Labels(), ":")}
+		lines = append(lines, propertyLines(n.Props())...)
+		fmt.Fprintf(bw, "  n%d [label=%s];\n", n.Id(), dotString(strings.Join(lines, "\n")))
+	}
+	for _, r := range g.Relationships() {
+		fmt.Fprintf(bw, "  n%d -> n%d [label=%s];\n", r.StartId(), r.EndId(), dotString(r.Type()))
+	}
+	bw.WriteString("}\n")
+	return bw.Flush()
+}
+
+// propertyLines renders properties as name: value lines sorted by name
+func propertyLines(props map[string]interface{}) []string {
+	names := sortedKeys(props)
+	lines := make([]string, len(names))
+	for i, name := range names {
+		lines[i] = name + ": " + textValue(props[name])
+	}
+	return lines
+}
+
+// textValue renders a value as text, strings as is and other values as JSON
+func textValue(v interface{}) string {
+	v = ToJSONValue(v)
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+func dotString(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(s) + `"`
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// graphml documents, see http://graphml.graphdrawing.org
+type (
+	graphml struct {
+		XMLName xml.Name     `xml:"graphml"`
+		XMLNS   string       `xml:"xmlns,attr"`
+		Keys    []graphmlKey `xml:"key"`
+		Graph   graphmlGraph `xml:"graph"`
+	}
+
+	graphmlKey struct {
+		ID   string `xml:"id,attr"`
+		For  string `xml:"for,attr"`
+		Name string `xml:"attr.name,attr"`
+		Type string `xml:"attr.type,attr"`
+	}
+
+	graphmlGraph struct {
+		ID          string        `xml:"id,attr"`
+		EdgeDefault string        `xml:"edgedefault,attr"`
+		Nodes       []graphmlNode `xml:"node"`
+		Edges       []graphmlEdge `xml:"edge"`
+	}
+
+	graphmlNode struct {
+		ID   string        `xml:"id,attr"`
+		Data []graphmlData `xml:"data"`
+	}
+
+	graphmlEdge struct {
+		ID     string        `xml:"id,attr"`
+		Source string        `xml:"source,attr"`
+		Target string        `xml:"target,attr"`
+		Data   []graphmlData `xml:"data"`
+	}
+
+	graphmlData struct {
+		Key   string `xml:"key,attr"`
+		Value string `xml:",chardata"`
+	}
+)
+
+// WriteGraphML writes the subgraph to w as a GraphML document. The labels of nodes, the
+// type of relationships and their properties are written as data, typed as long, double
+// or boolean when every value of a property has that type, and as string otherwise. Nodes
+// at the end of relationships that were not collected are written without data
+func (g *Subgraph) WriteGraphML(w io.Writer) error {
+	doc := graphml{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphmlKey{
+			{ID: "labels", For: "node", Name: "labels", Type: "string"},
+			{ID: "type", For: "edge", Name: "type", Type: "string"},
+		},
+		Graph: graphmlGraph{ID: "G", EdgeDefault: "directed"},
+	}
+
+	var nodeProps, relProps []map[string]interface{}
+	for _, n := range g.Nodes() {
+		nodeProps = append(nodeProps, n.Props())
+	}
+	for _, r := range g.Relationships() {
+		relProps = append(relProps, r.Props())
+	}
+	nodeKeys, nodeIDs := graphmlKeys("node", "n_", nodeProps)
+	relKeys, relIDs := graphmlKeys("edge", "e_", relProps)
+	doc.Keys = append(append(doc.Keys, nodeKeys...), relKeys...)
+
+	for _, n := range g.Nodes() {
+		node := graphmlNode{ID: "n" + strconv.FormatInt(n.Id(), 10)}
+		node.Data = append(node.Data, graphmlData{Key: "labels", Value: ":" + strings.Join(n.Labels(), ":")})
+		node.Data = append(node.Data, graphmlProps(nodeIDs, n.Props())...)
+		doc.Graph.Nodes = append(doc.Graph.Nodes, node)
+	}
+	// relationships may end on nodes that were not collected, those are written without
+	// data so that every edge refers to a node of the document
+	written := make(map[int64]bool)
+	for _, n := range g.Nodes() {
+		written[n.Id()] = true
+	}
+	for _, r := range g.Relationships() {
+		for _, id := range []int64{r.StartId(), r.EndId()} {
+			if !written[id] {
+				written[id] = true
+				doc.Graph.Nodes = append(doc.Graph.Nodes, graphmlNode{ID: "n" + strconv.FormatInt(id, 10)})
+			}
+		}
+	}
+	for _, r := range g.Relationships() {
+		edge := graphmlEdge{
+			ID:     "e" + strconv.FormatInt(r.Id(), 10),
+			Source: "n" + strconv.FormatInt(r.StartId(), 10),
+			Target: "n" + strconv.FormatInt(r.EndId(), 10),
+		}
+		edge.Data = append(edge.Data, graphmlData{Key: "type", Value: r.Type()})
+		edge.Data = append(edge.Data, graphmlProps(relIDs, r.Props())...)
+		doc.Graph.Edges = append(doc.Graph.Edges, edge)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// graphmlKeys returns the keys declaring the properties of nodes or edges sorted by name,
+// along with their ids by property name. Ids are generated as names may not be valid ids
+func graphmlKeys(domain, prefix string, props []map[string]interface{}) ([]graphmlKey, map[string]string) {
+	types := make(map[string]string)
+	for _, p := range props {
+		for name, v := range p {
+			t := graphmlType(v)
+			if prev, ok := types[name]; ok && prev != t {
+				t = "string"
+			}
+			types[name] = t
+		}
+	}
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	keys := make([]graphmlKey, len(names))
+	ids := make(map[string]string, len(names))
+	for i, name := range names {
+		ids[name] = prefix + strconv.Itoa(i)
+		keys[i] = graphmlKey{ID: ids[name], For: domain, Name: name, Type: types[name]}
+	}
+	return keys, ids
+}
+
+func graphmlType(v interface{}) string {
+	switch v.(type) {
+	case int64:
+		return "long"
+	case float64:
+		return "double"
+	case bool:
+		return "boolean"
+	}
+	return "string"
+}
+
+func graphmlProps(ids map[string]string, props map[string]interface{}) []graphmlData {
+	names := sortedKeys(props)
+	data := make([]graphmlData, len(names))
+	for i, name := range names {
+		data[i] = graphmlData{Key: ids[name], Value: textValue(props[name])}
+	}
+	return data
+}
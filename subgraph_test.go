@@ -0,0 +1,270 @@
+package neox_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+	"github.com/syllabix/neox"
+	"github.com/syllabix/neox/neoxtest"
+)
+
+// subgraph returns a subgraph of people, alice knowing bob who knows carol, and
+// alice working at acme. dave is not connected
+func subgraph(t *testing.T) *neox.Subgraph {
+	alice := neoxtest.NewNode(1, []string{"Person"}, map[string]interface{}{"name": "Alice", "age": int64(42)})
+	bob := neoxtest.NewNode(2, []string{"Person"}, map[string]interface{}{"name": "Bob", "age": 1.5})
+	carol := neoxtest.NewNode(3, []string{"Person", "Admin"}, map[string]interface{}{"name": "Carol"})
+	acme := neoxtest.NewNode(4, []string{"Company"}, map[string]interface{}{"name": "Acme \"Inc\""})
+	dave := neoxtest.NewNode(5, []string{"Person"}, nil)
+	ab := neoxtest.NewRelationship(10, 1, 2, "KNOWS", map[string]interface{}{"since": int64(2019)})
+	cb := neoxtest.NewRelationship(11, 3, 2, "KNOWS", nil)
+	aw := neoxtest.NewRelationship(12, 1, 4, "WORKS_AT", nil)
+
+	keys := []string{"p", "friends", "work", "loner"}
+	res := neoxtest.NewResultx(
+		neoxtest.NewRecord(keys, []interface{}{
+			neoxtest.NewPath([]neo4j.Node{alice, bob, carol}, []neo4j.Relationship{ab, cb}),
+			[]interface{}{bob, carol},
+			map[string]interface{}{"company": acme, "rel": aw, "name": "Acme"},
+			nil,
+		}),
+		neoxtest.NewRecord(keys, []interface{}{nil, []interface{}{alice}, nil, dave}),
+	)
+
+	g := neox.NewSubgraph()
+	if err := g.Collect(res); err != nil {
+		t.Fatalf("Subgraph.Collect() error = %v", err)
+	}
+	return g
+}
+
+func nodeIDs(nodes []neo4j.Node) []int64 {
+	ids := []int64{}
+	for _, n := range nodes {
+		ids = append(ids, n.Id())
+	}
+	return ids
+}
+
+func relIDs(rels []neo4j.Relationship) []int64 {
+	ids := []int64{}
+	for _, r := range rels {
+		ids = append(ids, r.Id())
+	}
+	return ids
+}
+
+func TestSubgraph(t *testing.T) {
+	t.Parallel()
+
+	g := subgraph(t)
+
+	tests := []struct {
+		name string
+		got  []int64
+		want []int64
+	}{
+		{name: "Nodes", got: nodeIDs(g.Nodes()), want: []int64{1, 2, 3, 4, 5}},
+		{name: "Relationships", got: relIDs(g.Relationships()), want: []int64{10, 11, 12}},
+		{name: "NodesByLabel", got: nodeIDs(g.NodesByLabel("Person")), want: []int64{1, 2, 3, 5}},
+		{name: "NodesByLabel", got: nodeIDs(g.NodesByLabel("Admin")), want: []int64{3}},
+		{name: "RelationshipsByType", got: relIDs(g.RelationshipsByType("KNOWS")), want: []int64{10, 11}},
+		{name: "Outgoing", got: relIDs(g.Outgoing(1)), want: []int64{10, 12}},
+		{name: "Outgoing", got: relIDs(g.Outgoing(1, "WORKS_AT")), want: []int64{12}},
+		{name: "Incoming", got: relIDs(g.Incoming(2)), want: []int64{10, 11}},
+		{name: "Neighbors", got: nodeIDs(g.Neighbors(2)), want: []int64{1, 3}},
+		{name: "Neighbors", got: nodeIDs(g.Neighbors(1, "KNOWS")), want: []int64{2}},
+		{name: "Neighbors", got: nodeIDs(g.Neighbors(5)), want: []int64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, tt.want) {
+				t.Errorf("Subgraph.%s() got = %v, want %v", tt.name, tt.got, tt.want)
+			}
+		})
+	}
+
+	t.Run("Walk", func(t *testing.T) {
+		var visited, depths []int64
+		g.Walk(3, -1, func(n neo4j.Node, depth int) bool {
+			visited = append(visited, n.Id())
+			depths = append(depths, int64(depth))
+			return true
+		})
+		if want := []int64{3, 2, 1, 4}; !reflect.DeepEqual(visited, want) {
+			t.Errorf("Subgraph.Walk() visited = %v, want %v", visited, want)
+		}
+		if want := []int64{0, 1, 2, 3}; !reflect.DeepEqual(depths, want) {
+			t.Errorf("Subgraph.Walk() depths = %v, want %v", depths, want)
+		}
+
+		visited = nil
+		g.Walk(3, 1, func(n neo4j.Node, depth int) bool {
+			visited = append(visited, n.Id())
+			return true
+		})
+		if want := []int64{3, 2}; !reflect.DeepEqual(visited, want) {
+			t.Errorf("Subgraph.Walk() visited = %v, want %v", visited, want)
+		}
+	})
+
+	t.Run("ShortestPath", func(t *testing.T) {
+		p, ok := g.ShortestPath(4, 3)
+		if !ok {
+			t.Fatalf("Subgraph.ShortestPath() ok = %v, want %v", ok, true)
+		}
+		if got, want := nodeIDs(p.Nodes()), []int64{4, 1, 2, 3}; !reflect.DeepEqual(got, want) {
+			t.Errorf("Subgraph.ShortestPath() nodes = %v, want %v", got, want)
+		}
+		directions := []neox.Direction{}
+		for _, s := range p.Segments {
+			directions = append(directions, s.Direction)
+		}
+		if want := []neox.Direction{neox.Backward, neox.Forward, neox.Backward}; !reflect.DeepEqual(directions, want) {
+			t.Errorf("Subgraph.ShortestPath() directions = %v, want %v", directions, want)
+		}
+		if _, ok := g.ShortestPath(1, 5); ok {
+			t.Errorf("Subgraph.ShortestPath() ok = %v, want %v", ok, false)
+		}
+		if p, ok := g.ShortestPath(5, 5); !ok || p.Len() != 0 {
+			t.Errorf("Subgraph.ShortestPath() got = %v, want a path without segments", p)
+		}
+	})
+}
+
+func TestSubgraph_Write(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		write func(g *neox.Subgraph, buf *bytes.Buffer) error
+		want  string
+	}{
+		{
+			name:  "Should write JSON",
+			write: func(g *neox.Subgraph, buf *bytes.Buffer) error { return g.WriteJSON(buf) },
+			want: `{"nodes":[` +
+				`{"id":1,"labels":["Person"],"properties":{"age":42,"name":"Alice"}},` +
+				`{"id":2,"labels":["Person"],"properties":{"age":1.5,"name":"Bob"}},` +
+				`{"id":3,"labels":["Person","Admin"],"properties":{"name":"Carol"}},` +
+				`{"id":4,"labels":["Company"],"properties":{"name":"Acme \"Inc\""}},` +
+				`{"id":5,"labels":["Person"],"properties":{}}],` +
+				`"relationships":[` +
+				`{"end":2,"id":10,"properties":{"since":2019},"start":1,"type":"KNOWS"},` +
+				`{"end":2,"id":11,"properties":{},"start":3,"type":"KNOWS"},` +
+				`{"end":4,"id":12,"properties":{},"start":1,"type":"WORKS_AT"}]}` + "\n",
+		},
+		{
+			name:  "Should write DOT",
+			write: func(g *neox.Subgraph, buf *bytes.Buffer) error { return g.WriteDOT(buf) },
+			want: `digraph {
+  n1 [label=":Person\nage: 42\nname: Alice"];
+  n2 [label=":Person\nage: 1.5\nname: Bob"];
+  n3 [label=":Person:Admin\nname: Carol"];
+  n4 [label=":Company\nname: Acme \"Inc\""];
+  n5 [label=":Person"];
+  n1 -> n2 [label="KNOWS"];
+  n3 -> n2 [label="KNOWS"];
+  n1 -> n4 [label="WORKS_AT"];
+}
+`,
+		},
+		{
+			name:  "Should write GraphML",
+			write: func(g *neox.Subgraph, buf *bytes.Buffer) error { return g.WriteGraphML(buf) },
+			want: `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="labels" for="node" attr.name="labels" attr.type="string"></key>
+  <key id="type" for="edge" attr.name="type" attr.type="string"></key>
+  <key id="n_0" for="node" attr.name="age" attr.type="string"></key>
+  <key id="n_1" for="node" attr.name="name" attr.type="string"></key>
+  <key id="e_0" for="edge" attr.name="since" attr.type="long"></key>
+  <graph id="G" edgedefault="directed">
+    <node id="n1">
+      <data key="labels">:Person</data>
+      <data key="n_0">42</data>
+      <data key="n_1">Alice</data>
+    </node>
+    <node id="n2">
+      <data key="labels">:Person</data>
+      <data key="n_0">1.5</data>
+      <data key="n_1">Bob</data>
+    </node>
+    <node id="n3">
+      <data key="labels">:Person:Admin</data>
+      <data key="n_1">Carol</data>
+    </node>
+    <node id="n4">
+      <data key="labels">:Company</data>
+      <data key="n_1">Acme &#34;Inc&#34;</data>
+    </node>
+    <node id="n5">
+      <data key="labels">:Person</data>
+    </node>
+    <edge id="e10" source="n1" target="n2">
+      <data key="type">KNOWS</data>
+      <data key="e_0">2019</data>
+    </edge>
+    <edge id="e11" source="n3" target="n2">
+      <data key="type">KNOWS</data>
+    </edge>
+    <edge id="e12" source="n1" target="n4">
+      <data key="type">WORKS_AT</data>
+    </edge>
+  </graph>
+</graphml>
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.write(subgraph(t), &buf); err != nil {
+				t.Fatalf("Subgraph.Write() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Subgraph.Write() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubgraph_WriteGraphML(t *testing.T) {
+	t.Parallel()
+
+	g := neox.NewSubgraph()
+	g.Add(
+		neoxtest.NewNode(1, []string{"Person"}, nil),
+		neoxtest.NewRelationship(10, 1, 2, "KNOWS", nil),
+		neoxtest.NewRelationship(11, 3, 2, "KNOWS", nil),
+	)
+
+	var buf bytes.Buffer
+	if err := g.WriteGraphML(&buf); err != nil {
+		t.Fatalf("Subgraph.WriteGraphML() error = %v", err)
+	}
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="labels" for="node" attr.name="labels" attr.type="string"></key>
+  <key id="type" for="edge" attr.name="type" attr.type="string"></key>
+  <graph id="G" edgedefault="directed">
+    <node id="n1">
+      <data key="labels">:Person</data>
+    </node>
+    <node id="n2"></node>
+    <node id="n3"></node>
+    <edge id="e10" source="n1" target="n2">
+      <data key="type">KNOWS</data>
+    </edge>
+    <edge id="e11" source="n3" target="n2">
+      <data key="type">KNOWS</data>
+    </edge>
+  </graph>
+</graphml>
+`
+	if got := buf.String(); got != want {
+		t.Errorf("Subgraph.WriteGraphML() got = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,36 @@
+// Package trace instruments a neox.Driver with tracing spans following the OpenTelemetry
+// database conventions, without depending on a tracing library.
+//
+// Every statement run through Runx, RunxContext or a neox.Transaction and every call to a
+// transaction helper of a neox.Session starts a span. Spans are parented from the context passed
+// to RunxContext, ReadTransactionxContext or WriteTransactionxContext, and statements run within a
+// transaction are children of the transaction span.
+//
+// An OpenTelemetry tracer can be adapted to the Tracer interface of this package in a few lines
+//
+//	type otelTracer struct{ trace.Tracer }
+//
+//	func (t otelTracer) Start(ctx context.Context, name string) (context.Context, neoxtrace.Span) {
+//		ctx, span := t.Tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient))
+//		return ctx, otelSpan{span}
+//	}
+//
+//	type otelSpan struct{ trace.Span }
+//
+//	func (s otelSpan) SetAttributes(attrs ...neoxtrace.Attribute) {
+//		for _, attr := range attrs {
+//			s.Span.SetAttributes(attribute.String(attr.Key, fmt.Sprint(attr.Value)))
+//		}
+//	}
+//
+//	func (s otelSpan) RecordError(err error) {
+//		s.Span.RecordError(err)
+//		s.Span.SetStatus(codes.Error, err.Error())
+//	}
+//
+//	func (s otelSpan) End() { s.Span.End() }
+//
+// and registered on the driver
+//
+//	driver.Use(neoxtrace.NewHook(otelTracer{otel.Tracer("neox")}))
+package trace
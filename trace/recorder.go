@@ -0,0 +1,80 @@
+package trace
+
+import (
+	"context"
+	"sync"
+)
+
+// Recorder is an in-memory Tracer that keeps every span it starts,
+// intended for asserting on traces in tests. It is safe for concurrent use
+type Recorder struct {
+	mu    sync.Mutex
+	spans []*RecordedSpan
+}
+
+// RecordedSpan is a span started by a Recorder
+type RecordedSpan struct {
+	Name       string
+	Parent     *RecordedSpan
+	Attributes map[string]interface{}
+	Errors     []error
+	Ended      bool
+
+	mu *sync.Mutex
+}
+
+type recorderkey struct{}
+
+// Start starts a span as a child of the span recorded in ctx, if any
+func (r *Recorder) Start(ctx context.Context, name string) (context.Context, Span) {
+	parent, _ := ctx.Value(recorderkey{}).(*RecordedSpan)
+	span := &RecordedSpan{
+		Name:       name,
+		Parent:     parent,
+		Attributes: make(map[string]interface{}),
+		mu:         &r.mu,
+	}
+
+	r.mu.Lock()
+	r.spans = append(r.spans, span)
+	r.mu.Unlock()
+
+	return context.WithValue(ctx, recorderkey{}, span), span
+}
+
+// Spans returns the recorded spans in the order they were started
+func (r *Recorder) Spans() []*RecordedSpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*RecordedSpan(nil), r.spans...)
+}
+
+// Reset discards the recorded spans
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	r.spans = nil
+	r.mu.Unlock()
+}
+
+// SetAttributes records the provided attributes
+func (s *RecordedSpan) SetAttributes(attrs ...Attribute) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, attr := range attrs {
+		s.Attributes[attr.Key] = attr.Value
+	}
+}
+
+// RecordError records the provided error
+func (s *RecordedSpan) RecordError(err error) {
+	s.mu.Lock()
+	s.Errors = append(s.Errors, err)
+	s.mu.Unlock()
+}
+
+// End marks the span as ended
+func (s *RecordedSpan) End() {
+	s.mu.Lock()
+	s.Ended = true
+	s.mu.Unlock()
+}
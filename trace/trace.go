@@ -0,0 +1,159 @@
+package trace
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+	"github.com/syllabix/neox"
+)
+
+// Attribute keys set on spans
+const (
+	KeySystem     = "db.system"
+	KeyStatement  = "db.statement"
+	KeyAccessMode = "db.neo4j.access_mode"
+	KeyAddress    = "server.address"
+	KeyVersion    = "db.neo4j.server_version"
+	KeyStatusCode = "db.neo4j.status_code"
+	KeyAttempts   = "db.neo4j.attempts"
+)
+
+// System is the value of the db.system attribute
+const System = "neo4j"
+
+// Attribute is a key value pair describing a span
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// A Tracer starts spans
+type Tracer interface {
+	// Start starts a span with the provided name as a child of the
+	// span in ctx, if any, returning a context holding the new span
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// A Span is a single traced operation
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+type spankey struct{}
+
+type hook struct {
+	tracer Tracer
+}
+
+// NewHook returns a hook that traces the statements and transactions
+// run through a neox.Driver using the provided tracer
+func NewHook(tracer Tracer) neox.TransactionHook {
+	return &hook{tracer: tracer}
+}
+
+func (h *hook) BeforeRun(ctx context.Context, q *neox.Query) context.Context {
+	ctx, span := h.tracer.Start(ctx, spanName(q.Cypher))
+	span.SetAttributes(
+		Attribute{KeySystem, System},
+		Attribute{KeyStatement, q.Cypher},
+		Attribute{KeyAccessMode, accessMode(q.AccessMode)},
+	)
+	return context.WithValue(ctx, spankey{}, span)
+}
+
+func (h *hook) AfterRun(ctx context.Context, q *neox.Query) {
+	span, ok := ctx.Value(spankey{}).(Span)
+	if !ok {
+		return
+	}
+	if q.Summary != nil {
+		span.SetAttributes(summaryAttributes(q.Summary)...)
+	}
+	recordError(span, q.Err)
+	span.End()
+}
+
+func (h *hook) BeforeTransaction(ctx context.Context, tx *neox.TransactionInfo) context.Context {
+	name := "neo4j.write_transaction"
+	if tx.AccessMode == neo4j.AccessModeRead {
+		name = "neo4j.read_transaction"
+	}
+	ctx, span := h.tracer.Start(ctx, name)
+	span.SetAttributes(
+		Attribute{KeySystem, System},
+		Attribute{KeyAccessMode, accessMode(tx.AccessMode)},
+	)
+	return context.WithValue(ctx, spankey{}, span)
+}
+
+func (h *hook) AfterTransaction(ctx context.Context, tx *neox.TransactionInfo) {
+	span, ok := ctx.Value(spankey{}).(Span)
+	if !ok {
+		return
+	}
+	span.SetAttributes(Attribute{KeyAttempts, tx.Attempts})
+	recordError(span, tx.Err)
+	span.End()
+}
+
+func recordError(span Span, err error) {
+	if err == nil {
+		return
+	}
+	var classified *neox.Error
+	if errors.As(err, &classified) && classified.Code != "" {
+		span.SetAttributes(Attribute{KeyStatusCode, classified.Code})
+	}
+	span.RecordError(err)
+}
+
+func summaryAttributes(summary neo4j.ResultSummary) []Attribute {
+	var attrs []Attribute
+	if server := summary.Server(); server != nil {
+		attrs = append(attrs,
+			Attribute{KeyAddress, server.Address()},
+			Attribute{KeyVersion, server.Version()},
+		)
+	}
+	if counters := summary.Counters(); counters != nil {
+		attrs = append(attrs,
+			Attribute{"db.neo4j.nodes_created", counters.NodesCreated()},
+			Attribute{"db.neo4j.nodes_deleted", counters.NodesDeleted()},
+			Attribute{"db.neo4j.relationships_created", counters.RelationshipsCreated()},
+			Attribute{"db.neo4j.relationships_deleted", counters.RelationshipsDeleted()},
+			Attribute{"db.neo4j.properties_set", counters.PropertiesSet()},
+			Attribute{"db.neo4j.labels_added", counters.LabelsAdded()},
+			Attribute{"db.neo4j.labels_removed", counters.LabelsRemoved()},
+			Attribute{"db.neo4j.indexes_added", counters.IndexesAdded()},
+			Attribute{"db.neo4j.indexes_removed", counters.IndexesRemoved()},
+			Attribute{"db.neo4j.constraints_added", counters.ConstraintsAdded()},
+			Attribute{"db.neo4j.constraints_removed", counters.ConstraintsRemoved()},
+		)
+	}
+	return attrs
+}
+
+func accessMode(mode neo4j.AccessMode) string {
+	if mode == neo4j.AccessModeRead {
+		return "read"
+	}
+	return "write"
+}
+
+// spanName returns the leading clause of the statement, ie: MATCH, falling
+// back to the database system when the statement is empty
+func spanName(cypher string) string {
+	fields := strings.Fields(cypher)
+	if len(fields) == 0 {
+		return System
+	}
+	clause := fields[0]
+	if i := strings.IndexAny(clause, "({"); i > 0 {
+		clause = clause[:i]
+	}
+	return strings.ToUpper(clause)
+}
@@ -0,0 +1,218 @@
+package trace
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+	"github.com/syllabix/neox"
+)
+
+func TestNewHook(t *testing.T) {
+	t.Parallel()
+
+	recorder := new(Recorder)
+	driver := &neox.Driver{Driver: &fdriver{}}
+	driver.Use(NewHook(recorder))
+
+	session, err := driver.Sessionx(neo4j.AccessModeWrite)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, _ := recorder.Start(context.Background(), "request")
+	_, err = session.WriteTransactionxContext(ctx, func(tx *neox.Transaction) (interface{}, error) {
+		res, err := tx.Runx("create (u:User {name: $name})", neox.Args{"name": "Yolanda"})
+		if err != nil {
+			return nil, err
+		}
+		return res.Consume()
+	})
+	if err != nil {
+		t.Fatalf("Session.WriteTransactionxContext() error = %v", err)
+	}
+
+	_, err = session.Runx("matchx (u:User) return u", nil)
+	if err == nil {
+		t.Fatalf("Session.Runx() expected an error")
+	}
+
+	spans := recorder.Spans()
+	if len(spans) != 4 {
+		t.Fatalf("recorded %d spans, want 4", len(spans))
+	}
+
+	root, tx, create, failed := spans[0], spans[1], spans[2], spans[3]
+	tests := []struct {
+		name   string
+		span   *RecordedSpan
+		parent *RecordedSpan
+		attrs  map[string]interface{}
+		errs   int
+	}{
+		{
+			name:   "Should trace transactions",
+			span:   tx,
+			parent: root,
+			attrs: map[string]interface{}{
+				KeySystem:     System,
+				KeyAccessMode: "write",
+				KeyAttempts:   1,
+			},
+		},
+		{
+			name:   "Should trace statements within transactions",
+			span:   create,
+			parent: tx,
+			attrs: map[string]interface{}{
+				KeySystem:                System,
+				KeyStatement:             "create (u:User {name: $name})",
+				KeyAccessMode:            "write",
+				KeyAddress:               "localhost:7687",
+				KeyVersion:               "Neo4j/3.5.0",
+				"db.neo4j.nodes_created": 1,
+			},
+		},
+		{
+			name: "Should record failures",
+			span: failed,
+			attrs: map[string]interface{}{
+				KeyStatement:  "matchx (u:User) return u",
+				KeyStatusCode: "Neo.ClientError.Statement.SyntaxError",
+			},
+			errs: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.span.Ended {
+				t.Errorf("span %s was not ended", tt.span.Name)
+			}
+			if tt.span.Parent != tt.parent {
+				t.Errorf("span %s parent = %v, want %v", tt.span.Name, tt.span.Parent, tt.parent)
+			}
+			for k, want := range tt.attrs {
+				if got := tt.span.Attributes[k]; got != want {
+					t.Errorf("span %s attribute %s = %v, want %v", tt.span.Name, k, got, want)
+				}
+			}
+			if len(tt.span.Errors) != tt.errs {
+				t.Errorf("span %s recorded %d errors, want %d", tt.span.Name, len(tt.span.Errors), tt.errs)
+			}
+		})
+	}
+
+	if create.Name != "CREATE" || tx.Name != "neo4j.write_transaction" {
+		t.Errorf("span names = %s, %s", tx.Name, create.Name)
+	}
+}
+
+func Test_spanName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		cypher string
+		want   string
+	}{
+		{"match (n) return n", "MATCH"},
+		{"\n\tMERGE(n:User {id: 1})", "MERGE"},
+		{"unwind $batch as row create (n)", "UNWIND"},
+		{"", System},
+	}
+	for _, tt := range tests {
+		if got := spanName(tt.cypher); got != tt.want {
+			t.Errorf("spanName(%q) = %v, want %v", tt.cypher, got, tt.want)
+		}
+	}
+}
+
+// fake neo4j.Driver
+type fdriver struct {
+	neo4j.Driver
+}
+
+func (d *fdriver) Session(mode neo4j.AccessMode, bookmarks ...string) (neo4j.Session, error) {
+	return &fsession{}, nil
+}
+
+// fake neo4j.Session, statements starting with matchx fail with a syntax error
+type fsession struct {
+	neo4j.Session
+}
+
+func (s *fsession) Run(cypher string, params map[string]interface{}, configurers ...func(*neo4j.TransactionConfig)) (neo4j.Result, error) {
+	return run(cypher)
+}
+
+func (s *fsession) WriteTransaction(work neo4j.TransactionWork, configurers ...func(*neo4j.TransactionConfig)) (interface{}, error) {
+	return work(&ftx{})
+}
+
+// fake neo4j.Transaction
+type ftx struct {
+	neo4j.Transaction
+}
+
+func (t *ftx) Run(cypher string, params map[string]interface{}) (neo4j.Result, error) {
+	return run(cypher)
+}
+
+func run(cypher string) (neo4j.Result, error) {
+	if len(cypher) > 6 && cypher[:6] == "matchx" {
+		return nil, &fdberr{"Neo.ClientError.Statement.SyntaxError"}
+	}
+	return &fresult{}, nil
+}
+
+// fake neo4j.Result without records
+type fresult struct {
+	neo4j.Result
+}
+
+func (r *fresult) Next() bool { return false }
+func (r *fresult) Err() error { return nil }
+func (r *fresult) Consume() (neo4j.ResultSummary, error) {
+	return &fsummary{}, nil
+}
+
+type fsummary struct {
+	neo4j.ResultSummary
+}
+
+func (s *fsummary) Server() neo4j.ServerInfo { return &fserver{} }
+func (s *fsummary) Counters() neo4j.Counters { return &fcounters{} }
+
+type fserver struct{}
+
+func (s *fserver) Address() string { return "localhost:7687" }
+func (s *fserver) Version() string { return "Neo4j/3.5.0" }
+
+type fcounters struct {
+	neo4j.Counters
+}
+
+func (c *fcounters) NodesCreated() int         { return 1 }
+func (c *fcounters) NodesDeleted() int         { return 0 }
+func (c *fcounters) RelationshipsCreated() int { return 0 }
+func (c *fcounters) RelationshipsDeleted() int { return 0 }
+func (c *fcounters) PropertiesSet() int        { return 1 }
+func (c *fcounters) LabelsAdded() int          { return 1 }
+func (c *fcounters) LabelsRemoved() int        { return 0 }
+func (c *fcounters) IndexesAdded() int         { return 0 }
+func (c *fcounters) IndexesRemoved() int       { return 0 }
+func (c *fcounters) ConstraintsAdded() int     { return 0 }
+func (c *fcounters) ConstraintsRemoved() int   { return 0 }
+
+// fake gobolt.DatabaseError
+type fdberr struct {
+	code string
+}
+
+func (e *fdberr) BoltError() bool        { return true }
+func (e *fdberr) Classification() string { return "ClientError" }
+func (e *fdberr) Code() string           { return e.code }
+func (e *fdberr) Message() string        { return "Invalid input" }
+func (e *fdberr) Error() string {
+	return fmt.Sprintf("database returned error [%s]: %s", e.code, e.Message())
+}
@@ -0,0 +1,99 @@
+package neox
+
+import (
+	"context"
+	"errors"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+// TransactionWork is a unit of work executed within a neox.Transaction
+// by the transaction helpers of a neox.Session
+type TransactionWork func(tx *Transaction) (interface{}, error)
+
+// Transaction wraps the standard implementation of a neo4j.Transaction
+// adding extension methods for running cypher queries
+type Transaction struct {
+	neo4j.Transaction
+	ctx   context.Context
+	mode  neo4j.AccessMode
+	instr instrumentation
+}
+
+// Runx is an extension method that runs the provided cypher
+// query with the respective args within the transaction and returns
+// a neox.Result. Failures reported by neo4j are returned as a classified neox.Error.
+// The context the transaction helper was called with is passed on to the hooks of the driver
+func (t *Transaction) Runx(cypher string, args Args) (*Result, error) {
+	ctx := t.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	run := t.instr.observe(ctx, t.mode, cypher, args)
+	res, err := t.Run(cypher, args)
+	if err != nil {
+		err = ClassifyError(err)
+		run.done(nil, err)
+		return nil, err
+	}
+	return &Result{
+		Result: res,
+		run:    run,
+	}, nil
+}
+
+// ReadTransactionx executes the provided unit of work in a read transaction
+// with the retry logic of the driver in place. Failures reported by neo4j are
+// returned as a classified neox.Error
+func (s *Session) ReadTransactionx(work TransactionWork, configurers ...func(*neo4j.TransactionConfig)) (interface{}, error) {
+	return s.ReadTransactionxContext(context.Background(), work, configurers...)
+}
+
+// ReadTransactionxContext is like ReadTransactionx, passing the provided context on to the hooks
+// of the driver. The context is not used to cancel the transaction
+func (s *Session) ReadTransactionxContext(ctx context.Context, work TransactionWork, configurers ...func(*neo4j.TransactionConfig)) (interface{}, error) {
+	return s.runTransaction(ctx, neo4j.AccessModeRead, s.ReadTransaction, work, configurers)
+}
+
+// WriteTransactionx executes the provided unit of work in a write transaction
+// with the retry logic of the driver in place. Failures reported by neo4j are
+// returned as a classified neox.Error
+func (s *Session) WriteTransactionx(work TransactionWork, configurers ...func(*neo4j.TransactionConfig)) (interface{}, error) {
+	return s.WriteTransactionxContext(context.Background(), work, configurers...)
+}
+
+// WriteTransactionxContext is like WriteTransactionx, passing the provided context on to the hooks
+// of the driver. The context is not used to cancel the transaction
+func (s *Session) WriteTransactionxContext(ctx context.Context, work TransactionWork, configurers ...func(*neo4j.TransactionConfig)) (interface{}, error) {
+	return s.runTransaction(ctx, neo4j.AccessModeWrite, s.WriteTransaction, work, configurers)
+}
+
+type transactionFunc func(neo4j.TransactionWork, ...func(*neo4j.TransactionConfig)) (interface{}, error)
+
+func (s *Session) runTransaction(ctx context.Context, mode neo4j.AccessMode, fn transactionFunc, work TransactionWork, configurers []func(*neo4j.TransactionConfig)) (interface{}, error) {
+	ctx, tx := s.instr.observeTransaction(ctx, mode)
+	res, err := fn(s.transactionWork(ctx, mode, tx, work), configurers...)
+	err = ClassifyError(err)
+	tx.done(err)
+	return res, err
+}
+
+// transactionWork adapts a neox.TransactionWork to the driver. Classified errors
+// are unwrapped before they are handed back, as the retry logic of the driver
+// relies on the concrete type of its own errors
+func (s *Session) transactionWork(ctx context.Context, mode neo4j.AccessMode, tx *txrun, work TransactionWork) neo4j.TransactionWork {
+	return func(t neo4j.Transaction) (interface{}, error) {
+		tx.attempt()
+		res, err := work(&Transaction{
+			Transaction: t,
+			ctx:         ctx,
+			mode:        mode,
+			instr:       s.instr,
+		})
+		var classified *Error
+		if errors.As(err, &classified) {
+			err = classified.cause
+		}
+		return res, err
+	}
+}
@@ -0,0 +1,115 @@
+package neox
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSession_WriteTransactionx(t *testing.T) {
+	t.Parallel()
+
+	violation := &mdberr{
+		code:    "Neo.ClientError.Schema.ConstraintValidationFailed",
+		message: "Node(0) already exists with label `User` and property `email` = 'ya@cool.com'",
+	}
+
+	tx := new(mtx)
+	tx.On("Run", "create (u:User {email: $email})", map[string]interface{}(Args{"email": "ya@cool.com"})).
+		Return(nil, violation)
+
+	session := new(msess)
+	session.On("WriteTransaction").Return(tx)
+
+	s := &Session{Session: session}
+	_, err := s.WriteTransactionx(func(tx *Transaction) (interface{}, error) {
+		return tx.Runx("create (u:User {email: $email})", Args{"email": "ya@cool.com"})
+	})
+
+	var constraint *ConstraintError
+	if !errors.As(err, &constraint) {
+		t.Fatalf("Session.WriteTransactionx() error = %v, want a *neox.ConstraintError", err)
+	}
+	if constraint.Property != "email" || constraint.Value != "ya@cool.com" {
+		t.Errorf("Session.WriteTransactionx() property = %v, value = %v", constraint.Property, constraint.Value)
+	}
+	if session.workErr != violation {
+		t.Errorf("driver received %#v, want the unclassified driver error", session.workErr)
+	}
+}
+
+func TestSession_ReadTransactionx(t *testing.T) {
+	t.Parallel()
+
+	record := new(mrec)
+	record.On("Get", "user_name").Return("Yolanda Erasmus", true)
+
+	result := new(mres)
+	result.On("Record").Return(record)
+	result.On("Err").Return(nil)
+
+	tx := new(mtx)
+	tx.On("Run", "match (u:User) return u.name as user_name", map[string]interface{}(nil)).Return(result, nil)
+
+	session := new(msess)
+	session.On("ReadTransaction").Return(tx)
+
+	s := &Session{Session: session}
+	name, err := s.ReadTransactionx(func(tx *Transaction) (interface{}, error) {
+		res, err := tx.Runx("match (u:User) return u.name as user_name", nil)
+		if err != nil {
+			return nil, err
+		}
+		name, _ := res.Recordx().GetString("user_name")
+		return name, nil
+	})
+	if err != nil {
+		t.Fatalf("Session.ReadTransactionx() error = %v", err)
+	}
+	if name != "Yolanda Erasmus" {
+		t.Errorf("Session.ReadTransactionx() = %v, want %v", name, "Yolanda Erasmus")
+	}
+}
+
+// mocked neo4j.Session, running the provided work once
+type msess struct {
+	neo4j.Session
+	mock.Mock
+	workErr error
+}
+
+func (m *msess) ReadTransaction(work neo4j.TransactionWork, configurers ...func(*neo4j.TransactionConfig)) (interface{}, error) {
+	args := m.Called()
+	return m.run(args.Get(0).(neo4j.Transaction), work)
+}
+
+func (m *msess) WriteTransaction(work neo4j.TransactionWork, configurers ...func(*neo4j.TransactionConfig)) (interface{}, error) {
+	args := m.Called()
+	return m.run(args.Get(0).(neo4j.Transaction), work)
+}
+
+func (m *msess) run(tx neo4j.Transaction, work neo4j.TransactionWork) (interface{}, error) {
+	res, err := work(tx)
+	m.workErr = err
+	return res, err
+}
+
+// mocked neo4j.Transaction
+type mtx struct {
+	neo4j.Transaction
+	mock.Mock
+}
+
+func (m *mtx) Run(cypher string, params map[string]interface{}) (neo4j.Result, error) {
+	args := m.Called(cypher, params)
+	result, _ := args.Get(0).(neo4j.Result)
+	return result, args.Error(1)
+}
+
+func (m *msess) Run(cypher string, params map[string]interface{}, configurers ...func(*neo4j.TransactionConfig)) (neo4j.Result, error) {
+	args := m.Called(cypher, params)
+	result, _ := args.Get(0).(neo4j.Result)
+	return result, args.Error(1)
+}